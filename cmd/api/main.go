@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"time"
+	_ "time/tzdata" // org timezones without relying on the image's zoneinfo
 
 	"github.com/tron-legacy/api/internal/config"
 	"github.com/tron-legacy/api/internal/crypto"
@@ -61,6 +62,20 @@ func main() {
 	handlers.RegisterJob("auto_boost", "Auto-Boost Processor", "Avalia posts e cria campanhas automáticas", "5 min", handlers.ProcessAutoBoosts)
 	handlers.RegisterJob("integrated_publish", "Integrated Publish", "Processa publicações integradas agendadas", "1 min", handlers.ProcessScheduledIntegratedPublishes)
 	handlers.RegisterJob("billing_grace", "Billing Grace Enforcer", "Rebaixa assinaturas inadimplentes após período de graça", "10 min", handlers.ProcessBillingGracePeriod)
+	handlers.RegisterJob("inbox_backfill", "Comment Inbox Backfill", "Importa comentários recentes do Instagram e Facebook para a caixa de moderação", "30 min", handlers.BackfillInboxComments)
+	handlers.RegisterJob("ig_webhook_retry", "Instagram Webhook Retry", "Reprocessa eventos de webhook do Instagram que falharam", "2 min", handlers.RetryFailedWebhookEvents)
+	handlers.RegisterJob("media_insights", "Instagram Post Insights", "Captura insights dos posts publicados em 1h, 6h, 24h, 72h e 7d", "15 min", handlers.CaptureMediaInsights)
+	handlers.RegisterJob("account_snapshots", "Instagram Account Snapshots", "Salva o snapshot diário de seguidores e insights das contas do Instagram", "360 min", handlers.CaptureAccountSnapshots)
+	handlers.RegisterJob("scheduling_queue", "Smart Scheduling Queue", "Atribui o melhor horário aos posts da fila e cria os agendamentos do Instagram", "5 min", handlers.ProcessSchedulingQueue)
+	handlers.RegisterJob("competitor_snapshots", "Competitor Snapshots", "Salva o snapshot diário dos concorrentes via Business Discovery e dispara alertas", "360 min", handlers.SnapshotCompetitors)
+	handlers.RegisterJob("meta_token_refresh", "Meta Token Refresh", "Verifica os tokens da Meta, renova os que estão perto de expirar e avisa os owners sobre reconexão", "360 min", handlers.RefreshMetaTokens)
+	handlers.RegisterJob("post_series", "Post Series", "Gera os agendamentos das séries recorrentes e filas evergreen para os próximos dias", "60 min", handlers.MaterializePostSeries)
+	handlers.RegisterJob("facebook_video_upload", "Facebook Video Upload", "Envia à página os vídeos e Reels agendados do Facebook que chegaram no horário", "1 min", handlers.ProcessScheduledFacebookVideos)
+	handlers.RegisterJob("facebook_video_status", "Facebook Video Processing", "Acompanha o processamento de vídeos e Reels enviados ao Facebook e conclui a publicação", "2 min", handlers.PollFacebookVideoProcessing)
+	handlers.RegisterJob("facebook_insights", "Facebook Page Insights", "Salva o snapshot diário dos insights das Páginas do Facebook e dos posts publicados pelos agendamentos", "360 min", handlers.CaptureFacebookInsights)
+	handlers.RegisterJob("facebook_native_sync", "Facebook Native Scheduling", "Entrega agendamentos ao agendador nativo do Facebook e sincroniza o status dos posts publicados por ele", "5 min", handlers.SyncNativeFacebookSchedules)
+	handlers.RegisterJob("threads_scheduler", "Threads Scheduler", "Publica posts agendados do Threads", "1 min", handlers.ProcessScheduledThreadsPosts)
+	handlers.RegisterJob("threads_token_refresh", "Threads Token Refresh", "Renova os tokens do Threads perto de expirar e avisa os owners sobre reconexão", "360 min", handlers.RefreshThreadsTokens)
 	handlers.RegisterJob("billing_sync", "Billing Asaas Sync", "Sincroniza estado das assinaturas com Asaas", fmt.Sprintf("%d min", cfg.BillingSyncIntervalMins), handlers.SyncBillingWithAsaas)
 
 	// ── Start background schedulers ───────────────────────────────
@@ -74,6 +89,20 @@ func main() {
 	go integratedPublishScheduler()
 	go billingGraceEnforcer()
 	go billingAsaasSync()
+	go inboxBackfill()
+	go igWebhookRetry()
+	go mediaInsightsCapture()
+	go accountSnapshotsCapture()
+	go schedulingQueue()
+	go competitorSnapshots()
+	go metaTokenRefresh()
+	go postSeries()
+	go facebookVideoUpload()
+	go facebookVideoStatus()
+	go facebookInsightsCapture()
+	go facebookNativeSync()
+	go threadsScheduler()
+	go threadsTokenRefresh()
 
 	// Start server
 	addr := ":" + cfg.Port
@@ -162,6 +191,152 @@ func billingAsaasSync() {
 	}
 }
 
+func inboxBackfill() {
+	time.Sleep(2 * time.Minute)
+	log.Println("Comment inbox backfill started (30 min interval)")
+	ticker := time.NewTicker(30 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("inbox_backfill")
+	}
+}
+
+func igWebhookRetry() {
+	time.Sleep(40 * time.Second)
+	log.Println("Instagram webhook retry worker started (2 min interval)")
+	ticker := time.NewTicker(2 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("ig_webhook_retry")
+	}
+}
+
+func mediaInsightsCapture() {
+	time.Sleep(3 * time.Minute)
+	log.Println("Instagram post insights job started (15 min interval)")
+	ticker := time.NewTicker(15 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("media_insights")
+	}
+}
+
+func accountSnapshotsCapture() {
+	time.Sleep(4 * time.Minute)
+	log.Println("Instagram account snapshots job started (360 min interval)")
+	handlers.RunJobWithTracking("account_snapshots")
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("account_snapshots")
+	}
+}
+
+func schedulingQueue() {
+	time.Sleep(50 * time.Second)
+	log.Println("Smart scheduling queue started (5 min interval)")
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("scheduling_queue")
+	}
+}
+
+func competitorSnapshots() {
+	time.Sleep(5 * time.Minute)
+	log.Println("Competitor snapshots job started (360 min interval)")
+	handlers.RunJobWithTracking("competitor_snapshots")
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("competitor_snapshots")
+	}
+}
+
+func metaTokenRefresh() {
+	time.Sleep(6 * time.Minute)
+	log.Println("Meta token refresh job started (360 min interval)")
+	handlers.RunJobWithTracking("meta_token_refresh")
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("meta_token_refresh")
+	}
+}
+
+func postSeries() {
+	time.Sleep(2 * time.Minute)
+	log.Println("Post series job started (60 min interval)")
+	handlers.RunJobWithTracking("post_series")
+	ticker := time.NewTicker(60 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("post_series")
+	}
+}
+
+func facebookVideoUpload() {
+	time.Sleep(21 * time.Second)
+	log.Println("Facebook video upload job started (1 min interval)")
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("facebook_video_upload")
+	}
+}
+
+func facebookVideoStatus() {
+	time.Sleep(40 * time.Second)
+	log.Println("Facebook video status job started (2 min interval)")
+	ticker := time.NewTicker(2 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("facebook_video_status")
+	}
+}
+
+func facebookInsightsCapture() {
+	time.Sleep(3 * time.Minute)
+	log.Println("Facebook insights job started (360 min interval)")
+	handlers.RunJobWithTracking("facebook_insights")
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("facebook_insights")
+	}
+}
+
+func facebookNativeSync() {
+	time.Sleep(50 * time.Second)
+	log.Println("Facebook native scheduling job started (5 min interval)")
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("facebook_native_sync")
+	}
+}
+
+func threadsScheduler() {
+	time.Sleep(24 * time.Second)
+	log.Println("Threads scheduler started (1 min interval)")
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("threads_scheduler")
+	}
+}
+
+func threadsTokenRefresh() {
+	time.Sleep(7 * time.Minute)
+	log.Println("Threads token refresh job started (360 min interval)")
+	handlers.RunJobWithTracking("threads_token_refresh")
+	ticker := time.NewTicker(6 * time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		handlers.RunJobWithTracking("threads_token_refresh")
+	}
+}
+
 // keepAlive pings the health endpoint every 14 minutes to prevent Render free tier sleep.
 func keepAlive(url string) {
 	// Wait for server to start
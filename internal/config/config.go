@@ -27,12 +27,15 @@ type Config struct {
 	MetaAppSecret       string
 	MetaAdsAccountID    string
 	MetaAdsAccessToken  string
+	ThreadsAppID        string
+	ThreadsAppSecret    string
 	AsaasAPIKey             string
 	AsaasSandbox            bool
 	AsaasWebhookToken       string
 	ContabilAPIURL          string
 	BillingGracePeriodDays  int
 	BillingSyncIntervalMins int
+	IGInsightsAllMedia      bool
 }
 
 var cfg *Config
@@ -70,12 +73,15 @@ func Load() *Config {
 		MetaAppSecret:      getEnv("META_APP_SECRET", ""),
 		MetaAdsAccountID:   getEnv("META_ADS_ACCOUNT_ID", ""),
 		MetaAdsAccessToken: getEnv("META_ADS_ACCESS_TOKEN", ""),
+		ThreadsAppID:       getEnv("THREADS_APP_ID", ""),
+		ThreadsAppSecret:   getEnv("THREADS_APP_SECRET", ""),
 		AsaasAPIKey:             getEnv("ASAAS_API_KEY", ""),
 		AsaasSandbox:            getEnv("ASAAS_SANDBOX", "true") == "true",
 		AsaasWebhookToken:      getEnv("ASAAS_WEBHOOK_TOKEN", ""),
 		ContabilAPIURL:          getEnv("CONTABIL_API_URL", "http://localhost:8089"),
 		BillingGracePeriodDays:  parseIntEnv("BILLING_GRACE_PERIOD_DAYS", 5),
 		BillingSyncIntervalMins: parseIntEnv("BILLING_SYNC_INTERVAL_MINS", 60),
+		IGInsightsAllMedia:      getEnv("IG_INSIGHTS_ALL_MEDIA", "false") == "true",
 	}
 
 	return cfg
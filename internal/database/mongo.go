@@ -7,6 +7,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -156,6 +157,104 @@ func FacebookConfigs() *mongo.Collection {
 	return DB.Collection("facebook_configs")
 }
 
+func InboxComments() *mongo.Collection {
+	return DB.Collection("inbox_comments")
+}
+
+func DMConversations() *mongo.Collection {
+	return DB.Collection("dm_conversations")
+}
+
+func DMMessages() *mongo.Collection {
+	return DB.Collection("dm_messages")
+}
+
+func BrandMentions() *mongo.Collection {
+	return DB.Collection("brand_mentions")
+}
+
+func IGWebhookEvents() *mongo.Collection {
+	return DB.Collection("ig_webhook_events")
+}
+
+func IGWebhookItems() *mongo.Collection {
+	return DB.Collection("ig_webhook_items")
+}
+
+func GiveawayDraws() *mongo.Collection {
+	return DB.Collection("giveaway_draws")
+}
+
+func MediaInsights() *mongo.Collection {
+	return DB.Collection("ig_media_insights")
+}
+
+func IGAccountSnapshots() *mongo.Collection {
+	return DB.Collection("ig_account_snapshots")
+}
+
+func SchedulingPreferences() *mongo.Collection {
+	return DB.Collection("scheduling_preferences")
+}
+
+func QueuedPosts() *mongo.Collection {
+	return DB.Collection("queued_posts")
+}
+
+func Competitors() *mongo.Collection {
+	return DB.Collection("competitors")
+}
+
+func CompetitorSnapshots() *mongo.Collection {
+	return DB.Collection("competitor_snapshots")
+}
+
+func CompetitorAlerts() *mongo.Collection {
+	return DB.Collection("competitor_alerts")
+}
+
+func HashtagSets() *mongo.Collection {
+	return DB.Collection("hashtag_sets")
+}
+
+func Notifications() *mongo.Collection {
+	return DB.Collection("notifications")
+}
+
+func PostSeries() *mongo.Collection {
+	return DB.Collection("post_series")
+}
+
+func CalendarFeeds() *mongo.Collection {
+	return DB.Collection("calendar_feeds")
+}
+
+func ContentComments() *mongo.Collection {
+	return DB.Collection("content_comments")
+}
+
+func FBPageSnapshots() *mongo.Collection {
+	return DB.Collection("fb_page_snapshots")
+}
+
+func FBPostInsights() *mongo.Collection {
+	return DB.Collection("fb_post_insights")
+}
+
+func ThreadsConfigs() *mongo.Collection {
+	return DB.Collection("threads_configs")
+}
+
+func ThreadsSchedules() *mongo.Collection {
+	return DB.Collection("threads_schedules")
+}
+
+// Videos returns the GridFS bucket holding uploaded video files
+// (videos.files / videos.chunks); they exceed the 16MB document limit.
+func Videos() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(DB, options.GridFSBucket().SetName("videos"))
+}
+
 // ── Contabil collections ─────────────────────────────────────────────
 
 func ContabilUserMappings() *mongo.Collection {
@@ -326,10 +425,15 @@ func EnsureIndexes() error {
 		return err
 	}
 
-	// instagram_leads: unique index on sender_ig_id
+	// Migration: drop old unique index on sender_ig_id (clashed on username-only mention leads)
+	InstagramLeads().Indexes().DropOne(ctx, "sender_ig_id_1")
+
+	// instagram_leads: unique index on sender_ig_id (partial: mention leads may only have a username)
 	_, err = InstagramLeads().Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.D{{Key: "sender_ig_id", Value: 1}},
-		Options: options.Index().SetUnique(true),
+		Keys: bson.D{{Key: "sender_ig_id", Value: 1}},
+		Options: options.Index().SetName("sender_ig_id_nonempty").SetUnique(true).SetPartialFilterExpression(bson.M{
+			"sender_ig_id": bson.M{"$gt": ""},
+		}),
 	})
 	if err != nil {
 		return err
@@ -508,6 +612,327 @@ func EnsureIndexes() error {
 		return err
 	}
 
+	// inbox_comments: unique index on {org_id, platform, comment_id} for idempotent ingestion
+	// (orgs sharing an account or page each keep their own copy)
+	_, err = InboxComments().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "platform", Value: 1}, {Key: "comment_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// inbox_comments: compound index on {org_id, status, commented_at} for inbox listing
+	_, err = InboxComments().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "status", Value: 1}, {Key: "commented_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// inbox_comments: index on {org_id, media_id} for per-post listing
+	_, err = InboxComments().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "media_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// dm_conversations: unique index on {org_id, participant_ig_id} (one thread per IG user per org)
+	_, err = DMConversations().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "participant_ig_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// dm_conversations: index on {org_id, last_message_at} for inbox listing
+	_, err = DMConversations().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "last_message_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// dm_messages: index on {conversation_id, created_at} for thread reads
+	_, err = DMMessages().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "conversation_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// dm_messages: unique index on mid (inbound only) so webhook retries don't duplicate
+	_, err = DMMessages().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "mid", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"mid": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// brand_mentions: unique index on {account_id, type, media_id, comment_id, mid} to dedupe webhook retries
+	_, err = BrandMentions().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "account_id", Value: 1},
+			{Key: "type", Value: 1},
+			{Key: "media_id", Value: 1},
+			{Key: "comment_id", Value: 1},
+			{Key: "mid", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// brand_mentions: index on {org_id, mentioned_at} for listing
+	_, err = BrandMentions().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "mentioned_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// ig_webhook_events: compound index on {status, next_retry_at} for the retry worker
+	_, err = IGWebhookEvents().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_retry_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// ig_webhook_events: TTL index — auto-delete after 30 days (also serves range replays)
+	_, err = IGWebhookEvents().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "received_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(30 * 24 * 60 * 60),
+	})
+	if err != nil {
+		return err
+	}
+
+	// ig_webhook_items: TTL index — processed item keys only need to outlive Meta's retries
+	_, err = IGWebhookItems().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(7 * 24 * 60 * 60),
+	})
+	if err != nil {
+		return err
+	}
+
+	// giveaway_draws: compound index on {org_id, created_at desc} for listing
+	_, err = GiveawayDraws().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// ig_media_insights: unique index on {media_id, checkpoint} — one snapshot per checkpoint
+	_, err = MediaInsights().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "media_id", Value: 1}, {Key: "checkpoint", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// ig_media_insights: compound index on {org_id, media_id, captured_at} for charts
+	_, err = MediaInsights().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "media_id", Value: 1}, {Key: "captured_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// ig_account_snapshots: unique index on {org_id, account_id, date} — one snapshot per
+	// org account per day (also serves reports)
+	_, err = IGAccountSnapshots().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "account_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// scheduling_preferences: unique index on org_id
+	_, err = SchedulingPreferences().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// queued_posts: compound index on {org_id, status, priority desc, created_at} for queue order
+	_, err = QueuedPosts().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "status", Value: 1}, {Key: "priority", Value: -1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// competitors: unique index on {org_id, username}
+	_, err = Competitors().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "username", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// competitor_snapshots: unique index on {competitor_id, date} (one snapshot per day)
+	_, err = CompetitorSnapshots().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "competitor_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// competitor_alerts: compound index on {org_id, created_at desc}
+	_, err = CompetitorAlerts().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// hashtag_sets: unique index on {org_id, name}
+	_, err = HashtagSets().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// notifications: compound index on {org_id, user_id, created_at desc}
+	_, err = Notifications().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// notifications: TTL index on created_at (90 days)
+	_, err = Notifications().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(90 * 24 * 3600),
+	})
+	if err != nil {
+		return err
+	}
+
+	// post_series: compound index on {org_id, created_at desc}
+	_, err = PostSeries().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// instagram_schedules: unique series occurrence, so concurrent runs can't materialize a slot twice
+	_, err = InstagramSchedules().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "occurrence_at", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+			"series_id": bson.M{"$exists": true},
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// facebook_schedules: unique series occurrence
+	_, err = FacebookSchedules().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "series_id", Value: 1}, {Key: "occurrence_at", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+			"series_id": bson.M{"$exists": true},
+		}),
+	})
+	if err != nil {
+		return err
+	}
+
+	// calendar_feeds: unique token (looked up by the public feed URL)
+	_, err = CalendarFeeds().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// calendar_feeds: one feed per user per org
+	_, err = CalendarFeeds().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// content_comments: compound index on {org_id, item_type, item_id, created_at}
+	_, err = ContentComments().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "item_type", Value: 1}, {Key: "item_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// fb_page_snapshots: unique index on {org_id, page_id, date} — one snapshot per
+	// org page per day (also serves reports)
+	_, err = FBPageSnapshots().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "page_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// fb_post_insights: unique index on {org_id, post_id, date} — one snapshot per org post per day
+	_, err = FBPostInsights().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}, {Key: "post_id", Value: 1}, {Key: "date", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// fb_post_insights: compound index on {org_id, published_at} for reports
+	_, err = FBPostInsights().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "published_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// threads_configs: unique index on org_id (one config per org)
+	_, err = ThreadsConfigs().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "org_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// threads_schedules: compound index on {status, scheduled_at} for scheduler queries
+	_, err = ThreadsSchedules().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "scheduled_at", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// threads_schedules: compound index on {org_id, scheduled_at} for listing and the calendar
+	_, err = ThreadsSchedules().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "org_id", Value: 1}, {Key: "scheduled_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
 	// ── Contabil indexes ────────────────────────────────────────────
 
 	// contabil_user_mappings: unique index on {tron_user_id, org_id}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// approvalLink is where reviewers and authors are sent from notifications.
+const approvalLink = "/admin/calendar"
+
+// contentCollections maps a content item type (as used by the calendar) to its collection.
+var contentCollections = map[string]func() *mongo.Collection{
+	"instagram_schedule": database.InstagramSchedules,
+	"facebook_schedule":  database.FacebookSchedules,
+	"threads_schedule":   database.ThreadsSchedules,
+	"integrated_publish": database.IntegratedPublishes,
+}
+
+// canApproveContent reports whether a membership may approve content.
+func canApproveContent(m models.OrgMembership) bool {
+	if m.OrgRole == "owner" || m.OrgRole == "admin" {
+		return true
+	}
+	if m.OrgRole == "viewer" {
+		return false
+	}
+	for _, p := range m.Permissions {
+		if p == "content:approve" {
+			return true
+		}
+	}
+	return false
+}
+
+// approvalRequired reports whether content created by the user must be reviewed:
+// the org enabled the workflow and the user cannot approve content.
+func approvalRequired(ctx context.Context, orgID, userID primitive.ObjectID) bool {
+	var org models.Organization
+	opts := options.FindOne().SetProjection(bson.M{"settings.require_approval": 1})
+	if err := database.Organizations().FindOne(ctx, bson.M{"_id": orgID}, opts).Decode(&org); err != nil || !org.Settings.RequireApproval {
+		return false
+	}
+	var m models.OrgMembership
+	if err := database.OrgMemberships().FindOne(ctx, bson.M{"org_id": orgID, "user_id": userID}).Decode(&m); err != nil {
+		return true
+	}
+	return !canApproveContent(m)
+}
+
+// approvalEntry builds a history entry for the user.
+func approvalEntry(ctx context.Context, action string, userID primitive.ObjectID, comment string) models.ApprovalDecision {
+	entry := models.ApprovalDecision{Action: action, UserID: userID, Comment: comment, At: time.Now()}
+	var u models.User
+	if database.Users().FindOne(ctx, bson.M{"_id": userID}).Decode(&u) == nil {
+		entry.UserEmail = u.Email
+	}
+	return entry
+}
+
+// initialApprovalState returns the status and history for a new item created by userID.
+func initialApprovalState(ctx context.Context, orgID, userID primitive.ObjectID) (string, []models.ApprovalDecision) {
+	if !approvalRequired(ctx, orgID, userID) {
+		return "scheduled", nil
+	}
+	return "pending_approval", []models.ApprovalDecision{approvalEntry(ctx, "submitted", userID, "")}
+}
+
+// resubmitOnEdit decides the status of an edited item: rejected items go back to review,
+// and edits by users who cannot approve send approved items back to review too.
+// Returns the history entry to push, or nil when the status does not change.
+func resubmitOnEdit(ctx context.Context, orgID, editorID primitive.ObjectID, status string) *models.ApprovalDecision {
+	if status == "pending_approval" {
+		return nil
+	}
+	if status != "rejected" && !approvalRequired(ctx, orgID, editorID) {
+		return nil
+	}
+	entry := approvalEntry(ctx, "submitted", editorID, "")
+	return &entry
+}
+
+// orgReviewers returns the users who can approve content in the org.
+func orgReviewers(ctx context.Context, orgID primitive.ObjectID) []primitive.ObjectID {
+	cursor, err := database.OrgMemberships().Find(ctx, bson.M{"org_id": orgID})
+	if err != nil {
+		return nil
+	}
+	var members []models.OrgMembership
+	cursor.All(ctx, &members)
+	cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for _, m := range members {
+		if canApproveContent(m) {
+			ids = append(ids, m.UserID)
+		}
+	}
+	return ids
+}
+
+// notifyReviewers tells the org's reviewers that content is waiting for approval.
+func notifyReviewers(ctx context.Context, orgID primitive.ObjectID, count int, caption string) {
+	title := "Novo post aguardando aprovação"
+	message := "Um post foi enviado para aprovação."
+	if caption != "" {
+		message = fmt.Sprintf("Um post foi enviado para aprovação: \"%s\".", calendarTitle(caption))
+	}
+	if count > 1 {
+		title = fmt.Sprintf("%d posts aguardando aprovação", count)
+		message = fmt.Sprintf("%d posts foram enviados para aprovação.", count)
+	}
+	notifyUsers(ctx, orgID, orgReviewers(ctx, orgID), "approval_requested", title, message, approvalLink, true)
+}
+
+// ListPendingApprovals returns the items waiting for review, across channels.
+// @Summary Listar aprovações pendentes
+// @Description Lista os agendamentos do Instagram, do Facebook e do Threads e as publicações integradas aguardando aprovação, com o histórico de decisões
+// @Tags approvals
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending_approval (padrão) ou rejected"
+// @Success 200 {array} models.CalendarItem
+// @Failure 403 {string} string "Forbidden"
+// @Router /admin/approvals [get]
+func ListPendingApprovals(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	status := r.URL.Query().Get("status")
+	if status != "rejected" {
+		status = "pending_approval"
+	}
+	filter := bson.M{"org_id": orgID, "status": status}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	items := collectCalendarItems(ctx, filter, "")
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ScheduledAt.Before(items[j].ScheduledAt) })
+	fillCalendarCommentCounts(ctx, orgID, items)
+	json.NewEncoder(w).Encode(items)
+}
+
+// ApproveContent approves an item so the schedulers pick it up.
+// @Summary Aprovar post
+// @Description Aprova um item aguardando aprovação; ele passa a ser publicado no horário agendado. O autor é notificado
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "instagram_schedule, facebook_schedule, threads_schedule ou integrated_publish"
+// @Param id path string true "ID do item"
+// @Param body body models.ApprovalDecisionRequest false "Comentário"
+// @Success 200 {object} models.CalendarItem
+// @Failure 400 {object} models.PostValidationResult "Validation failed"
+// @Failure 404 {string} string "Item not found"
+// @Failure 409 {string} string "Item is not pending approval"
+// @Router /admin/approvals/{type}/{id}/approve [post]
+func ApproveContent(w http.ResponseWriter, r *http.Request) {
+	decideContent(w, r, true)
+}
+
+// RejectContent rejects an item with a comment. It can be edited and resubmitted.
+// @Summary Rejeitar post
+// @Description Rejeita um item aguardando aprovação com um comentário obrigatório. O autor é notificado e pode editar o item para reenviá-lo
+// @Tags approvals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "instagram_schedule, facebook_schedule, threads_schedule ou integrated_publish"
+// @Param id path string true "ID do item"
+// @Param body body models.ApprovalDecisionRequest true "Motivo da rejeição"
+// @Success 200 {object} models.CalendarItem
+// @Failure 400 {string} string "comment is required"
+// @Failure 404 {string} string "Item not found"
+// @Failure 409 {string} string "Item is not pending approval"
+// @Router /admin/approvals/{type}/{id}/reject [post]
+func RejectContent(w http.ResponseWriter, r *http.Request) {
+	decideContent(w, r, false)
+}
+
+func decideContent(w http.ResponseWriter, r *http.Request, approve bool) {
+	orgID := middleware.GetOrgID(r)
+	userID := middleware.GetUserID(r)
+
+	itemType := r.PathValue("type")
+	collection, ok := contentCollections[itemType]
+	if !ok {
+		http.Error(w, "type must be instagram_schedule, facebook_schedule, threads_schedule or integrated_publish", http.StatusBadRequest)
+		return
+	}
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ApprovalDecisionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if !approve && req.Comment == "" {
+		http.Error(w, "comment is required when rejecting", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": oid, "org_id": orgID}
+	var item models.CalendarItem
+	switch itemType {
+	case "instagram_schedule":
+		var s models.InstagramSchedule
+		err = collection().FindOne(ctx, filter).Decode(&s)
+		item = instagramCalendarItem(s)
+		if err == nil && approve && s.Status == "pending_approval" {
+			if res := validateInstagramPost(ctx, s.Caption, s.MediaType, s.ImageIDs); !res.Valid {
+				writeValidationErrors(w, res)
+				return
+			}
+		}
+	case "facebook_schedule":
+		var s models.FacebookSchedule
+		err = collection().FindOne(ctx, filter).Decode(&s)
+		item = facebookCalendarItem(s)
+	case "threads_schedule":
+		var s models.ThreadsSchedule
+		err = collection().FindOne(ctx, filter).Decode(&s)
+		item = threadsCalendarItem(s)
+		if err == nil && approve && s.Status == "pending_approval" {
+			if msg := validateThreadsPost(ctx, s.Caption, s.MediaType, s.ImageIDs); msg != "" {
+				http.Error(w, msg, http.StatusBadRequest)
+				return
+			}
+		}
+	case "integrated_publish":
+		var p models.IntegratedPublish
+		err = collection().FindOne(ctx, filter).Decode(&p)
+		item = integratedCalendarItem(p)
+		if err == nil && approve && p.Status == "pending_approval" {
+			if res := validateInstagramPost(ctx, p.Caption, p.MediaType, p.ImageIDs); !res.Valid {
+				writeValidationErrors(w, res)
+				return
+			}
+		}
+	}
+	if err != nil {
+		http.Error(w, "Item not found", http.StatusNotFound)
+		return
+	}
+	if item.RawStatus != "pending_approval" {
+		http.Error(w, "Item is not pending approval", http.StatusConflict)
+		return
+	}
+
+	action, status := "rejected", "rejected"
+	if approve {
+		action, status = "approved", "scheduled"
+	}
+	entry := approvalEntry(ctx, action, userID, req.Comment)
+
+	res, err := collection().UpdateOne(ctx,
+		bson.M{"_id": oid, "org_id": orgID, "status": "pending_approval"},
+		bson.M{
+			"$set":  bson.M{"status": status, "updated_at": time.Now()},
+			"$push": bson.M{"approvals": entry},
+		},
+	)
+	if err != nil {
+		http.Error(w, "Error saving decision", http.StatusInternalServerError)
+		return
+	}
+	if res.MatchedCount == 0 {
+		http.Error(w, "Item is not pending approval", http.StatusConflict)
+		return
+	}
+
+	item.Status, item.RawStatus = status, status
+	item.Editable = calendarEditable(status)
+	item.Approvals = append(item.Approvals, entry)
+
+	title := "Post aprovado"
+	message := fmt.Sprintf("Seu post \"%s\" foi aprovado e será publicado no horário agendado.", item.Title)
+	if !approve {
+		title = "Post rejeitado"
+		message = fmt.Sprintf("Seu post \"%s\" foi rejeitado: %s", item.Title, req.Comment)
+	}
+	if item.UserID != userID {
+		notifyUsers(ctx, orgID, []primitive.ObjectID{item.UserID}, "approval_"+action, title, message, approvalLink, true)
+	}
+
+	slog.Info("content_"+action,
+		"type", itemType,
+		"id", oid.Hex(),
+		"org_id", orgID.Hex(),
+		"reviewer_id", userID.Hex(),
+	)
+	json.NewEncoder(w).Encode(item)
+}
@@ -0,0 +1,509 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// maxCalendarRange bounds a single calendar query (a quarter plus slack).
+	maxCalendarRange = 93 * 24 * time.Hour
+	calendarTitleLen = 80
+)
+
+// parseCalendarTime accepts RFC 3339 or a YYYY-MM-DD date in the org timezone.
+func parseCalendarTime(raw string, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", raw, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// calendarTitle returns the first line of the caption, shortened.
+func calendarTitle(caption string) string {
+	title := strings.TrimSpace(strings.SplitN(caption, "\n", 2)[0])
+	if utf8.RuneCountInString(title) > calendarTitleLen {
+		title = string([]rune(title)[:calendarTitleLen-1]) + "…"
+	}
+	return title
+}
+
+// calendarStatus maps a collection status to the normalized calendar status.
+func calendarStatus(raw string) string {
+	switch raw {
+	case "publishing", "publishing_ig", "publishing_ads", "processing":
+		return "publishing"
+	case "published", "completed":
+		return "published"
+	default:
+		return raw
+	}
+}
+
+// calendarEditable reports whether an item in this status can be rescheduled.
+func calendarEditable(status string) bool {
+	return status == "scheduled" || status == "failed" || status == "pending_approval"
+}
+
+// rescheduledStatus keeps the review state; failed items go back to scheduled.
+func rescheduledStatus(status string) string {
+	if status == "failed" {
+		return "scheduled"
+	}
+	return status
+}
+
+func calendarThumbnail(imageIDs []string) string {
+	if len(imageIDs) == 0 {
+		return ""
+	}
+	return "/api/v1/blog/images/" + imageIDs[0]
+}
+
+func instagramCalendarItem(s models.InstagramSchedule) models.CalendarItem {
+	channels := []string{"instagram"}
+	if s.PostToFacebook {
+		channels = append(channels, "facebook")
+	}
+	if s.PostToThreads {
+		channels = append(channels, "threads")
+	}
+	return models.CalendarItem{
+		ID:           s.ID.Hex(),
+		Type:         "instagram_schedule",
+		Channels:     channels,
+		Title:        calendarTitle(s.Caption),
+		Caption:      s.Caption,
+		MediaType:    s.MediaType,
+		ScheduledAt:  s.ScheduledAt,
+		PublishedAt:  s.PublishedAt,
+		Status:       calendarStatus(s.Status),
+		RawStatus:    s.Status,
+		ThumbnailURL: calendarThumbnail(s.ImageIDs),
+		ImageCount:   len(s.ImageIDs),
+		SeriesID:     s.SeriesID,
+		ErrorMessage: s.ErrorMessage,
+		Editable:     calendarEditable(s.Status),
+		UserID:       s.UserID,
+		Approvals:    s.Approvals,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func facebookCalendarItem(s models.FacebookSchedule) models.CalendarItem {
+	return models.CalendarItem{
+		ID:           s.ID.Hex(),
+		Type:         "facebook_schedule",
+		Channels:     []string{"facebook"},
+		Title:        calendarTitle(s.Message),
+		Caption:      s.Message,
+		MediaType:    s.MediaType,
+		ScheduledAt:  s.ScheduledAt,
+		Status:       calendarStatus(s.Status),
+		RawStatus:    s.Status,
+		ThumbnailURL: calendarThumbnail(s.ImageIDs),
+		ImageCount:   len(s.ImageIDs),
+		SeriesID:     s.SeriesID,
+		ErrorMessage: s.ErrorMessage,
+		Editable:     calendarEditable(s.Status),
+		UserID:       s.UserID,
+		Approvals:    s.Approvals,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func threadsCalendarItem(s models.ThreadsSchedule) models.CalendarItem {
+	return models.CalendarItem{
+		ID:           s.ID.Hex(),
+		Type:         "threads_schedule",
+		Channels:     []string{"threads"},
+		Title:        calendarTitle(s.Caption),
+		Caption:      s.Caption,
+		MediaType:    s.MediaType,
+		ScheduledAt:  s.ScheduledAt,
+		PublishedAt:  s.PublishedAt,
+		Status:       calendarStatus(s.Status),
+		RawStatus:    s.Status,
+		ThumbnailURL: calendarThumbnail(s.ImageIDs),
+		ImageCount:   len(s.ImageIDs),
+		ErrorMessage: s.ErrorMessage,
+		Editable:     calendarEditable(s.Status),
+		UserID:       s.UserID,
+		Approvals:    s.Approvals,
+		UpdatedAt:    s.UpdatedAt,
+	}
+}
+
+func integratedCalendarItem(p models.IntegratedPublish) models.CalendarItem {
+	return models.CalendarItem{
+		ID:           p.ID.Hex(),
+		Type:         "integrated_publish",
+		Channels:     []string{"instagram", "meta_ads"},
+		Title:        calendarTitle(p.Caption),
+		Caption:      p.Caption,
+		MediaType:    p.MediaType,
+		ScheduledAt:  p.ScheduledAt,
+		PublishedAt:  p.IGPublishedAt,
+		Status:       calendarStatus(p.Status),
+		RawStatus:    p.Status,
+		ThumbnailURL: calendarThumbnail(p.ImageIDs),
+		ImageCount:   len(p.ImageIDs),
+		ErrorMessage: p.ErrorMessage,
+		Editable:     calendarEditable(p.Status),
+		UserID:       p.UserID,
+		Approvals:    p.Approvals,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+// collectCalendarItems loads the items matching filter from every channel collection.
+// channel optionally restricts the result to "instagram", "facebook", "threads" or "meta_ads".
+func collectCalendarItems(ctx context.Context, filter bson.M, channel string) []models.CalendarItem {
+	items := []models.CalendarItem{}
+
+	if channel == "" || channel == "instagram" || channel == "facebook" || channel == "threads" {
+		var schedules []models.InstagramSchedule
+		if cursor, err := database.InstagramSchedules().Find(ctx, filter); err == nil {
+			cursor.All(ctx, &schedules)
+			cursor.Close(ctx)
+		}
+		for _, s := range schedules {
+			if channel == "facebook" && !s.PostToFacebook || channel == "threads" && !s.PostToThreads {
+				continue
+			}
+			items = append(items, instagramCalendarItem(s))
+		}
+	}
+	if channel == "" || channel == "facebook" {
+		var schedules []models.FacebookSchedule
+		if cursor, err := database.FacebookSchedules().Find(ctx, filter); err == nil {
+			cursor.All(ctx, &schedules)
+			cursor.Close(ctx)
+		}
+		for _, s := range schedules {
+			items = append(items, facebookCalendarItem(s))
+		}
+	}
+	if channel == "" || channel == "threads" {
+		var schedules []models.ThreadsSchedule
+		if cursor, err := database.ThreadsSchedules().Find(ctx, filter); err == nil {
+			cursor.All(ctx, &schedules)
+			cursor.Close(ctx)
+		}
+		for _, s := range schedules {
+			items = append(items, threadsCalendarItem(s))
+		}
+	}
+	if channel == "" || channel == "instagram" || channel == "meta_ads" {
+		var pubs []models.IntegratedPublish
+		if cursor, err := database.IntegratedPublishes().Find(ctx, filter); err == nil {
+			cursor.All(ctx, &pubs)
+			cursor.Close(ctx)
+		}
+		for _, p := range pubs {
+			items = append(items, integratedCalendarItem(p))
+		}
+	}
+	return items
+}
+
+// GetCalendar returns every scheduled and published item in a date range, across channels.
+// @Summary Calendário de conteúdo
+// @Description Retorna todos os itens (agendamentos do Instagram, do Facebook e do Threads e publicações integradas) no período, normalizados entre canais, com status e miniatura. Datas em RFC 3339 ou AAAA-MM-DD no fuso da organização; período máximo de 93 dias
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "Início do período"
+// @Param to query string true "Fim do período"
+// @Param channel query string false "instagram, facebook, threads ou meta_ads"
+// @Param status query string false "scheduled, publishing, published ou failed"
+// @Success 200 {object} models.CalendarResponse
+// @Failure 400 {string} string "Invalid range"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/calendar [get]
+func GetCalendar(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	loc := orgLocation(ctx, orgID)
+	from, okFrom := parseCalendarTime(r.URL.Query().Get("from"), loc)
+	to, okTo := parseCalendarTime(r.URL.Query().Get("to"), loc)
+	if !okFrom || !okTo {
+		http.Error(w, "from and to are required (RFC 3339 or YYYY-MM-DD)", http.StatusBadRequest)
+		return
+	}
+	if len(r.URL.Query().Get("to")) == len("2006-01-02") {
+		to = to.AddDate(0, 0, 1) // a date includes the whole day
+	}
+	if !to.After(from) {
+		http.Error(w, "to must be after from", http.StatusBadRequest)
+		return
+	}
+	if to.Sub(from) > maxCalendarRange {
+		http.Error(w, "Range must be 93 days or less", http.StatusBadRequest)
+		return
+	}
+	channel := r.URL.Query().Get("channel")
+	status := r.URL.Query().Get("status")
+
+	items := collectCalendarItems(ctx, bson.M{"org_id": orgID, "scheduled_at": bson.M{"$gte": from, "$lt": to}}, channel)
+
+	if status != "" {
+		kept := items[:0]
+		for _, item := range items {
+			if item.Status == status {
+				kept = append(kept, item)
+			}
+		}
+		items = kept
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ScheduledAt.Before(items[j].ScheduledAt) })
+	fillCalendarCommentCounts(ctx, orgID, items)
+
+	json.NewEncoder(w).Encode(models.CalendarResponse{
+		From:     from,
+		To:       to,
+		Timezone: loc.String(),
+		Items:    items,
+		Total:    len(items),
+	})
+}
+
+// RescheduleCalendarItem moves an item to a new time, dispatching to its collection
+// and revalidating the post. Used by drag-to-reschedule.
+// @Summary Reagendar item do calendário
+// @Description Move um item do calendário para um novo horário. O tipo (instagram_schedule, facebook_schedule, threads_schedule ou integrated_publish) define a coleção; o post é revalidado, itens com falha voltam para agendado e itens em aprovação continuam aguardando
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "Tipo do item"
+// @Param id path string true "ID do item"
+// @Param body body models.RescheduleCalendarItemRequest true "Novo horário"
+// @Success 200 {object} models.CalendarItem
+// @Failure 400 {object} models.PostValidationResult "Validation failed"
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Item not found"
+// @Failure 409 {string} string "Item can no longer be rescheduled"
+// @Router /admin/calendar/{type}/{id} [patch]
+func RescheduleCalendarItem(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.RescheduleCalendarItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
+	if err != nil {
+		http.Error(w, "scheduled_at must be a valid ISO 8601 date", http.StatusBadRequest)
+		return
+	}
+	if !scheduledAt.After(time.Now()) {
+		http.Error(w, "scheduled_at must be in the future", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var item models.CalendarItem
+	var ok bool
+	switch r.PathValue("type") {
+	case "instagram_schedule":
+		if !canScheduleChannel(w, r, "instagram") {
+			return
+		}
+		item, ok = rescheduleInstagramSchedule(ctx, w, orgID, oid, scheduledAt)
+	case "facebook_schedule":
+		if !canScheduleChannel(w, r, "facebook") {
+			return
+		}
+		item, ok = rescheduleFacebookSchedule(ctx, w, orgID, oid, scheduledAt)
+	case "threads_schedule":
+		if !canScheduleChannel(w, r, "threads") {
+			return
+		}
+		item, ok = rescheduleThreadsSchedule(ctx, w, orgID, oid, scheduledAt)
+	case "integrated_publish":
+		if role := middleware.GetOrgRole(r); role != "owner" && role != "admin" {
+			http.Error(w, "Forbidden: insufficient permissions", http.StatusForbidden)
+			return
+		}
+		item, ok = rescheduleIntegratedPublish(ctx, w, orgID, oid, scheduledAt)
+	default:
+		http.Error(w, "type must be instagram_schedule, facebook_schedule, threads_schedule or integrated_publish", http.StatusBadRequest)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	slog.Info("calendar_item_rescheduled", "type", item.Type, "id", item.ID, "scheduled_at", scheduledAt.Format(time.RFC3339))
+	json.NewEncoder(w).Encode(item)
+}
+
+// rescheduleApplied reports whether a status-guarded reschedule update matched,
+// writing the error response otherwise.
+func rescheduleApplied(w http.ResponseWriter, res *mongo.UpdateResult, err error) bool {
+	if err != nil {
+		http.Error(w, "Error rescheduling item", http.StatusInternalServerError)
+		return false
+	}
+	if res.MatchedCount == 0 {
+		http.Error(w, "Item can no longer be rescheduled", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+// rescheduleInstagramSchedule moves an Instagram schedule after revalidating it, and
+// warns when the new time would exceed the publishing quota.
+func rescheduleInstagramSchedule(ctx context.Context, w http.ResponseWriter, orgID, oid primitive.ObjectID, scheduledAt time.Time) (models.CalendarItem, bool) {
+	var s models.InstagramSchedule
+	if err := database.InstagramSchedules().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&s); err != nil {
+		http.Error(w, "Item not found", http.StatusNotFound)
+		return models.CalendarItem{}, false
+	}
+	if !calendarEditable(s.Status) {
+		http.Error(w, "Item can no longer be rescheduled", http.StatusConflict)
+		return models.CalendarItem{}, false
+	}
+	if res := validateInstagramPost(ctx, s.Caption, s.MediaType, s.ImageIDs); !res.Valid {
+		writeValidationErrors(w, res)
+		return models.CalendarItem{}, false
+	}
+
+	status := rescheduledStatus(s.Status)
+	set := bson.M{"scheduled_at": scheduledAt, "status": status, "error_message": "", "updated_at": time.Now()}
+	if s.SeriesID != nil {
+		set["detached"] = true
+	}
+	// The status guard loses against a scheduler that claimed the post meanwhile
+	res, err := database.InstagramSchedules().UpdateOne(ctx, bson.M{"_id": oid, "org_id": orgID, "status": s.Status}, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"deferred_until": "", "defer_reason": ""},
+	})
+	if !rescheduleApplied(w, res, err) {
+		return models.CalendarItem{}, false
+	}
+
+	s.ScheduledAt, s.Status, s.ErrorMessage = scheduledAt, status, ""
+	item := instagramCalendarItem(s)
+	item.Warnings = publishingQuotaWarnings(ctx, s.UserID, orgID, scheduledAt, s.ID)
+	return item, true
+}
+
+// rescheduleFacebookSchedule moves a Facebook schedule after revalidating it.
+func rescheduleFacebookSchedule(ctx context.Context, w http.ResponseWriter, orgID, oid primitive.ObjectID, scheduledAt time.Time) (models.CalendarItem, bool) {
+	var s models.FacebookSchedule
+	if err := database.FacebookSchedules().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&s); err != nil {
+		http.Error(w, "Item not found", http.StatusNotFound)
+		return models.CalendarItem{}, false
+	}
+	if !calendarEditable(s.Status) {
+		http.Error(w, "Item can no longer be rescheduled", http.StatusConflict)
+		return models.CalendarItem{}, false
+	}
+	if msg := validateFacebookPost(ctx, orgID, s.Message, s.MediaType, s.LinkURL, s.ImageIDs, s.VideoID); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return models.CalendarItem{}, false
+	}
+
+	status := rescheduledStatus(s.Status)
+	set := bson.M{"scheduled_at": scheduledAt, "status": status, "error_message": "", "updated_at": time.Now()}
+	if s.SeriesID != nil {
+		set["detached"] = true
+	}
+	res, err := database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": oid, "org_id": orgID, "status": s.Status}, bson.M{"$set": set})
+	if !rescheduleApplied(w, res, err) {
+		return models.CalendarItem{}, false
+	}
+
+	prev := s
+	s.ScheduledAt, s.Status, s.ErrorMessage = scheduledAt, status, ""
+	s, err = syncNativeFacebookSchedule(ctx, prev, s)
+	if err != nil {
+		http.Error(w, "Item rescheduled, but the post scheduled on Facebook could not be updated: "+err.Error(), http.StatusBadGateway)
+		return models.CalendarItem{}, false
+	}
+	return facebookCalendarItem(s), true
+}
+
+// rescheduleThreadsSchedule moves a Threads schedule after revalidating it.
+func rescheduleThreadsSchedule(ctx context.Context, w http.ResponseWriter, orgID, oid primitive.ObjectID, scheduledAt time.Time) (models.CalendarItem, bool) {
+	var s models.ThreadsSchedule
+	if err := database.ThreadsSchedules().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&s); err != nil {
+		http.Error(w, "Item not found", http.StatusNotFound)
+		return models.CalendarItem{}, false
+	}
+	if !calendarEditable(s.Status) {
+		http.Error(w, "Item can no longer be rescheduled", http.StatusConflict)
+		return models.CalendarItem{}, false
+	}
+	if msg := validateThreadsPost(ctx, s.Caption, s.MediaType, s.ImageIDs); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return models.CalendarItem{}, false
+	}
+
+	status := rescheduledStatus(s.Status)
+	set := bson.M{"scheduled_at": scheduledAt, "status": status, "error_message": "", "updated_at": time.Now()}
+	res, err := database.ThreadsSchedules().UpdateOne(ctx, bson.M{"_id": oid, "org_id": orgID, "status": s.Status}, bson.M{"$set": set})
+	if !rescheduleApplied(w, res, err) {
+		return models.CalendarItem{}, false
+	}
+
+	s.ScheduledAt, s.Status, s.ErrorMessage = scheduledAt, status, ""
+	return threadsCalendarItem(s), true
+}
+
+// rescheduleIntegratedPublish moves an integrated publish after revalidating its post.
+func rescheduleIntegratedPublish(ctx context.Context, w http.ResponseWriter, orgID, oid primitive.ObjectID, scheduledAt time.Time) (models.CalendarItem, bool) {
+	var p models.IntegratedPublish
+	if err := database.IntegratedPublishes().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&p); err != nil {
+		http.Error(w, "Item not found", http.StatusNotFound)
+		return models.CalendarItem{}, false
+	}
+	if !calendarEditable(p.Status) {
+		http.Error(w, "Item can no longer be rescheduled", http.StatusConflict)
+		return models.CalendarItem{}, false
+	}
+	if res := validateInstagramPost(ctx, p.Caption, p.MediaType, p.ImageIDs); !res.Valid {
+		writeValidationErrors(w, res)
+		return models.CalendarItem{}, false
+	}
+
+	status := rescheduledStatus(p.Status)
+	set := bson.M{"scheduled_at": scheduledAt, "status": status, "error_message": "", "error_phase": "", "updated_at": time.Now()}
+	res, err := database.IntegratedPublishes().UpdateOne(ctx, bson.M{"_id": oid, "org_id": orgID, "status": p.Status}, bson.M{"$set": set})
+	if !rescheduleApplied(w, res, err) {
+		return models.CalendarItem{}, false
+	}
+
+	p.ScheduledAt, p.Status, p.ErrorMessage, p.ErrorPhase = scheduledAt, status, "", ""
+	return integratedCalendarItem(p), true
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tron-legacy/api/internal/config"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// The feed covers recent history and the upcoming schedule.
+	calendarFeedPast    = 30 * 24 * time.Hour
+	calendarFeedAhead   = 180 * 24 * time.Hour
+	calendarFeedEvent   = 15 * time.Minute // events are points in time; give them a visible length
+	calendarFeedExcerpt = 300
+)
+
+var calendarChannelNames = map[string]string{
+	"instagram": "Instagram",
+	"facebook":  "Facebook",
+	"threads":   "Threads",
+	"meta_ads":  "Meta Ads",
+}
+
+var calendarStatusNames = map[string]string{
+	"pending_approval": "Aguardando aprovação",
+	"rejected":         "Rejeitado",
+	"scheduled":        "Agendado",
+	"publishing":       "Publicando",
+	"published":        "Publicado",
+	"failed":           "Falhou",
+}
+
+func calendarFeedURL(token string) string {
+	return publicAPIBaseURL() + "/api/v1/calendar/feeds/" + token + ".ics"
+}
+
+func calendarFeedResponse(feed *models.CalendarFeed) models.CalendarFeedResponse {
+	if feed == nil {
+		return models.CalendarFeedResponse{Enabled: false}
+	}
+	createdAt := feed.CreatedAt
+	return models.CalendarFeedResponse{
+		Enabled:        true,
+		URL:            calendarFeedURL(feed.Token),
+		CreatedAt:      &createdAt,
+		LastAccessedAt: feed.LastAccessedAt,
+	}
+}
+
+// GetCalendarFeed returns the caller's iCalendar feed URL for the current org.
+// @Summary URL do feed iCal
+// @Description Retorna a URL secreta do feed iCalendar do usuário nesta organização, para assinar no Google Agenda ou Outlook
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CalendarFeedResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/calendar/feed [get]
+func GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	userID := middleware.GetUserID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var feed models.CalendarFeed
+	err := database.CalendarFeeds().FindOne(ctx, bson.M{"org_id": orgID, "user_id": userID}).Decode(&feed)
+	if err == mongo.ErrNoDocuments {
+		json.NewEncoder(w).Encode(calendarFeedResponse(nil))
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error fetching calendar feed", http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(calendarFeedResponse(&feed))
+}
+
+// RotateCalendarFeed creates the caller's feed or replaces its token, revoking the old URL.
+// @Summary Gerar URL do feed iCal
+// @Description Cria o feed iCalendar do usuário ou gera uma nova URL secreta; a URL anterior deixa de funcionar
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CalendarFeedResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/calendar/feed [post]
+func RotateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	userID := middleware.GetUserID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		slog.Error("calendar_feed_token_error", "error", err)
+		http.Error(w, "Error generating token", http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+
+	var feed models.CalendarFeed
+	err := database.CalendarFeeds().FindOneAndUpdate(ctx,
+		bson.M{"org_id": orgID, "user_id": userID},
+		bson.M{
+			"$set":   bson.M{"token": hex.EncodeToString(tokenBytes), "created_at": now},
+			"$unset": bson.M{"last_accessed_at": ""},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&feed)
+	if err != nil {
+		slog.Error("calendar_feed_rotate_error", "org_id", orgID.Hex(), "error", err)
+		http.Error(w, "Error creating calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("calendar_feed_rotated", "org_id", orgID.Hex(), "user_id", userID.Hex())
+	json.NewEncoder(w).Encode(calendarFeedResponse(&feed))
+}
+
+// RevokeCalendarFeed disables the caller's feed.
+// @Summary Revogar feed iCal
+// @Description Revoga a URL do feed iCalendar do usuário nesta organização
+// @Tags calendar
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/calendar/feed [delete]
+func RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	userID := middleware.GetUserID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := database.CalendarFeeds().DeleteOne(ctx, bson.M{"org_id": orgID, "user_id": userID}); err != nil {
+		http.Error(w, "Error revoking calendar feed", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("calendar_feed_revoked", "org_id", orgID.Hex(), "user_id", userID.Hex())
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeCalendarFeed renders the org's content calendar as an iCalendar (RFC 5545) feed.
+// Authenticated by the secret token in the URL; the feed stops working once the
+// token is revoked, the user leaves the organization or is demoted to viewer.
+// @Summary Feed iCal
+// @Description Feed iCalendar público (autenticado pelo token da URL) com os agendamentos do Instagram, do Facebook e do Threads e as publicações integradas dos últimos 30 e próximos 180 dias
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Token do feed (com ou sem .ics)"
+// @Success 200 {string} string "iCalendar"
+// @Failure 404 {string} string "Feed not found"
+// @Router /calendar/feeds/{token} [get]
+func ServeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(r.PathValue("token"), ".ics")
+	if token == "" {
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var feed models.CalendarFeed
+	if err := database.CalendarFeeds().FindOne(ctx, bson.M{"token": token}).Decode(&feed); err != nil {
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+	// Viewers can't see the calendar, so their feeds don't serve it either
+	var membership models.OrgMembership
+	err := database.OrgMemberships().FindOne(ctx, bson.M{
+		"org_id":   feed.OrgID,
+		"user_id":  feed.UserID,
+		"org_role": bson.M{"$in": []string{"owner", "admin", "member"}},
+	}).Decode(&membership)
+	if err != nil {
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+	var org models.Organization
+	database.Organizations().FindOne(ctx, bson.M{"_id": feed.OrgID}).Decode(&org)
+
+	now := time.Now()
+	items := collectCalendarItems(ctx, bson.M{
+		"org_id":       feed.OrgID,
+		"scheduled_at": bson.M{"$gte": now.Add(-calendarFeedPast), "$lt": now.Add(calendarFeedAhead)},
+	}, "")
+
+	database.CalendarFeeds().UpdateOne(ctx, bson.M{"_id": feed.ID}, bson.M{"$set": bson.M{"last_accessed_at": now}})
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.Write([]byte(renderICalendar(org.Name, items, now)))
+}
+
+// renderICalendar builds the VCALENDAR document. Each item keeps the same UID across
+// requests, so clients update moved or edited events and drop deleted ones.
+func renderICalendar(orgName string, items []models.CalendarItem, now time.Time) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(foldICalLine(name + ":" + value))
+	}
+
+	calName := "Whodo"
+	if orgName != "" {
+		calName += " – " + orgName
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Whodo//Content Calendar//PT")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICalText(calName))
+	line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	line("X-PUBLISHED-TTL", "PT1H")
+
+	for _, item := range items {
+		modified := item.UpdatedAt
+		if modified.IsZero() {
+			modified = now
+		}
+		status := "CONFIRMED"
+		if item.Status == "pending_approval" || item.Status == "rejected" {
+			status = "TENTATIVE"
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", item.Type+"-"+item.ID+"@whodo")
+		line("DTSTAMP", formatICalTime(modified))
+		line("LAST-MODIFIED", formatICalTime(modified))
+		line("DTSTART", formatICalTime(item.ScheduledAt))
+		line("DTEND", formatICalTime(item.ScheduledAt.Add(calendarFeedEvent)))
+		line("SUMMARY", escapeICalText(calendarFeedSummary(item)))
+		line("DESCRIPTION", escapeICalText(calendarFeedDescription(item)))
+		line("URL", calendarItemLink(item))
+		line("STATUS", status)
+		line("CATEGORIES", strings.Join(calendarChannelLabels(item.Channels), ","))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+func calendarChannelLabels(channels []string) []string {
+	labels := make([]string, 0, len(channels))
+	for _, c := range channels {
+		if name, ok := calendarChannelNames[c]; ok {
+			labels = append(labels, name)
+		} else {
+			labels = append(labels, c)
+		}
+	}
+	return labels
+}
+
+func calendarStatusLabel(status string) string {
+	if name, ok := calendarStatusNames[status]; ok {
+		return name
+	}
+	return status
+}
+
+func calendarFeedSummary(item models.CalendarItem) string {
+	title := item.Title
+	if title == "" {
+		title = "(sem legenda)"
+	}
+	summary := "[" + strings.Join(calendarChannelLabels(item.Channels), " + ") + "] " + title
+	if item.Status != "scheduled" && item.Status != "published" {
+		summary += " (" + calendarStatusLabel(item.Status) + ")"
+	}
+	return summary
+}
+
+func calendarFeedDescription(item models.CalendarItem) string {
+	excerpt := strings.TrimSpace(item.Caption)
+	if utf8.RuneCountInString(excerpt) > calendarFeedExcerpt {
+		excerpt = string([]rune(excerpt)[:calendarFeedExcerpt-1]) + "…"
+	}
+	lines := []string{
+		"Canais: " + strings.Join(calendarChannelLabels(item.Channels), ", "),
+		"Status: " + calendarStatusLabel(item.Status),
+	}
+	if item.ErrorMessage != "" {
+		lines = append(lines, "Erro: "+item.ErrorMessage)
+	}
+	if excerpt != "" {
+		lines = append(lines, "", excerpt)
+	}
+	lines = append(lines, "", calendarItemLink(item))
+	return strings.Join(lines, "\n")
+}
+
+// calendarItemLink deep-links to the item in the admin calendar.
+func calendarItemLink(item models.CalendarItem) string {
+	base := strings.TrimRight(config.Get().FrontendURL, "/")
+	return fmt.Sprintf("%s%s?type=%s&id=%s", base, approvalLink, item.Type, item.ID)
+}
+
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeICalText escapes a TEXT value (RFC 5545 §3.3.11).
+func escapeICalText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// foldICalLine splits a content line into 75-octet chunks without breaking UTF-8
+// sequences (RFC 5545 §3.1) and terminates it with CRLF.
+func foldICalLine(s string) string {
+	const limit = 75
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1 // the leading space counts toward the next line
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,787 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListInboxComments returns a paginated, filterable list of inbox comments.
+// @Summary Listar comentários da caixa de moderação
+// @Description Lista comentários do Instagram e Facebook com filtros por post, status, plataforma e responsável
+// @Tags inbox
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Param platform query string false "instagram ou facebook"
+// @Param status query string false "open, done ou deleted (padrão: todos exceto deleted)"
+// @Param media_id query string false "Filtrar por post"
+// @Param assigned_to query string false "ID do membro ou 'me'"
+// @Param hidden query bool false "Filtrar por ocultos"
+// @Param search query string false "Buscar no texto ou username"
+// @Success 200 {object} models.InboxCommentListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error listing comments"
+// @Router /admin/inbox/comments [get]
+func ListInboxComments(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"org_id": orgID}
+	if platform := q.Get("platform"); platform != "" {
+		filter["platform"] = platform
+	}
+	if status := q.Get("status"); status != "" {
+		filter["status"] = status
+	} else {
+		filter["status"] = bson.M{"$ne": "deleted"}
+	}
+	if mediaID := q.Get("media_id"); mediaID != "" {
+		filter["media_id"] = mediaID
+	}
+	if assigned := q.Get("assigned_to"); assigned != "" {
+		if assigned == "me" {
+			filter["assigned_to"] = middleware.GetUserID(r)
+		} else if oid, err := primitive.ObjectIDFromHex(assigned); err == nil {
+			filter["assigned_to"] = oid
+		}
+	}
+	if hidden := q.Get("hidden"); hidden != "" {
+		filter["hidden"] = hidden == "true"
+	}
+	if search := q.Get("search"); search != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(search), "$options": "i"}
+		filter["$or"] = []bson.M{{"text": pattern}, {"author_username": pattern}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.InboxComments().CountDocuments(ctx, filter)
+	if err != nil {
+		slog.Error("list_inbox_comments_count", "error", err)
+		http.Error(w, "Error listing comments", http.StatusInternalServerError)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "commented_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.InboxComments().Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("list_inbox_comments_find", "error", err)
+		http.Error(w, "Error listing comments", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var comments []models.InboxComment
+	if err := cursor.All(ctx, &comments); err != nil {
+		slog.Error("list_inbox_comments_decode", "error", err)
+		http.Error(w, "Error listing comments", http.StatusInternalServerError)
+		return
+	}
+	if comments == nil {
+		comments = []models.InboxComment{}
+	}
+
+	json.NewEncoder(w).Encode(models.InboxCommentListResponse{
+		Comments: comments,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	})
+}
+
+// ListInboxPosts groups inbox comments per post with open/total counts.
+// @Summary Listar posts com comentários
+// @Description Agrupa os comentários da caixa de moderação por post, com contagem de abertos e total
+// @Tags inbox
+// @Produce json
+// @Security BearerAuth
+// @Param platform query string false "instagram ou facebook"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error listing posts"
+// @Router /admin/inbox/posts [get]
+func ListInboxPosts(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	match := bson.M{"org_id": orgID, "status": bson.M{"$ne": "deleted"}}
+	if platform := r.URL.Query().Get("platform"); platform != "" {
+		match["platform"] = platform
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":             bson.M{"platform": "$platform", "media_id": "$media_id"},
+			"total":           bson.M{"$sum": 1},
+			"open":            bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", "open"}}, 1, 0}}},
+			"last_comment_at": bson.M{"$max": "$commented_at"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"last_comment_at": -1}}},
+		{{Key: "$limit", Value: 100}},
+	}
+
+	cursor, err := database.InboxComments().Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("list_inbox_posts_aggregate", "error", err)
+		http.Error(w, "Error listing posts", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	type postRow struct {
+		Platform      string    `json:"platform"`
+		MediaID       string    `json:"media_id"`
+		Total         int       `json:"total"`
+		Open          int       `json:"open"`
+		LastCommentAt time.Time `json:"last_comment_at"`
+	}
+
+	posts := []postRow{}
+	for cursor.Next(ctx) {
+		var row struct {
+			ID struct {
+				Platform string `bson:"platform"`
+				MediaID  string `bson:"media_id"`
+			} `bson:"_id"`
+			Total         int       `bson:"total"`
+			Open          int       `bson:"open"`
+			LastCommentAt time.Time `bson:"last_comment_at"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			continue
+		}
+		posts = append(posts, postRow{
+			Platform:      row.ID.Platform,
+			MediaID:       row.ID.MediaID,
+			Total:         row.Total,
+			Open:          row.Open,
+			LastCommentAt: row.LastCommentAt,
+		})
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"posts": posts})
+}
+
+// ReplyInboxComment posts a public reply to a comment.
+// @Summary Responder comentário
+// @Description Publica uma resposta pública ao comentário no Instagram ou Facebook
+// @Tags inbox
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do comentário na caixa"
+// @Param body body models.ReplyInboxCommentRequest true "Mensagem"
+// @Success 200 {object} models.InboxComment
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Comment not found"
+// @Failure 502 {string} string "Platform API error"
+// @Router /admin/inbox/comments/{id}/reply [post]
+func ReplyInboxComment(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.ReplyInboxCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		http.Error(w, "Message is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	comment, ok := findInboxComment(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	token, err := resolveInboxCommentToken(ctx, comment)
+	if err != nil {
+		slog.Error("inbox_reply_creds", "error", err, "comment_id", comment.CommentID)
+		http.Error(w, "Credentials not available for this account", http.StatusBadRequest)
+		return
+	}
+
+	if comment.Platform == "facebook" {
+		params := url.Values{}
+		params.Set("message", req.Message)
+		_, err = metaGraphPost("/"+comment.CommentID+"/comments", token, params)
+	} else {
+		err = sendCommentReply(token, comment.CommentID, req.Message)
+	}
+	if err != nil {
+		slog.Error("inbox_reply_failed", "error", err, "comment_id", comment.CommentID, "platform", comment.Platform)
+		http.Error(w, "Platform API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	reply := models.InboxCommentReply{Message: req.Message, UserID: userID, CreatedAt: time.Now()}
+	updateInboxComment(ctx, w, comment.ID, bson.M{
+		"$push": bson.M{"replies": reply},
+		"$set":  bson.M{"status": "done", "updated_at": time.Now()},
+	})
+}
+
+// HideInboxComment hides or unhides a comment on the platform.
+// @Summary Ocultar comentário
+// @Description Oculta ou reexibe o comentário no Instagram ou Facebook
+// @Tags inbox
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do comentário na caixa"
+// @Param body body models.HideInboxCommentRequest true "Ocultar ou reexibir"
+// @Success 200 {object} models.InboxComment
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Comment not found"
+// @Failure 502 {string} string "Platform API error"
+// @Router /admin/inbox/comments/{id}/hide [post]
+func HideInboxComment(w http.ResponseWriter, r *http.Request) {
+	var req models.HideInboxCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	comment, ok := findInboxComment(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	token, err := resolveInboxCommentToken(ctx, comment)
+	if err != nil {
+		slog.Error("inbox_hide_creds", "error", err, "comment_id", comment.CommentID)
+		http.Error(w, "Credentials not available for this account", http.StatusBadRequest)
+		return
+	}
+
+	params := url.Values{}
+	if comment.Platform == "facebook" {
+		params.Set("is_hidden", strconv.FormatBool(req.Hidden))
+	} else {
+		params.Set("hide", strconv.FormatBool(req.Hidden))
+	}
+	if _, err := metaGraphPost("/"+comment.CommentID, token, params); err != nil {
+		slog.Error("inbox_hide_failed", "error", err, "comment_id", comment.CommentID, "platform", comment.Platform)
+		http.Error(w, "Platform API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	updateInboxComment(ctx, w, comment.ID, bson.M{
+		"$set": bson.M{"hidden": req.Hidden, "updated_at": time.Now()},
+	})
+}
+
+// DeleteInboxComment deletes a comment on the platform and marks it deleted in the inbox.
+// @Summary Excluir comentário
+// @Description Exclui o comentário no Instagram ou Facebook e marca como excluído na caixa
+// @Tags inbox
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do comentário na caixa"
+// @Success 200 {object} map[string]string
+// @Failure 404 {string} string "Comment not found"
+// @Failure 502 {string} string "Platform API error"
+// @Router /admin/inbox/comments/{id} [delete]
+func DeleteInboxComment(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	comment, ok := findInboxComment(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	token, err := resolveInboxCommentToken(ctx, comment)
+	if err != nil {
+		slog.Error("inbox_delete_creds", "error", err, "comment_id", comment.CommentID)
+		http.Error(w, "Credentials not available for this account", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := metaGraphDelete("/"+comment.CommentID, token); err != nil {
+		slog.Error("inbox_delete_failed", "error", err, "comment_id", comment.CommentID, "platform", comment.Platform)
+		http.Error(w, "Platform API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	_, err = database.InboxComments().UpdateOne(ctx, bson.M{"_id": comment.ID}, bson.M{
+		"$set": bson.M{"status": "deleted", "updated_at": time.Now()},
+	})
+	if err != nil {
+		slog.Error("inbox_delete_update", "error", err)
+	}
+
+	slog.Info("inbox_comment_deleted", "comment_id", comment.CommentID, "platform", comment.Platform)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Comment deleted"})
+}
+
+// UpdateInboxCommentStatus marks a comment as done or reopens it.
+// @Summary Alterar status do comentário
+// @Description Marca o comentário como resolvido (done) ou reabre (open)
+// @Tags inbox
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do comentário na caixa"
+// @Param body body models.UpdateInboxCommentStatusRequest true "Novo status"
+// @Success 200 {object} models.InboxComment
+// @Failure 400 {string} string "Invalid status"
+// @Failure 404 {string} string "Comment not found"
+// @Router /admin/inbox/comments/{id}/status [patch]
+func UpdateInboxCommentStatus(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateInboxCommentStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Status != "open" && req.Status != "done" {
+		http.Error(w, "Invalid status (open, done)", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	comment, ok := findInboxComment(ctx, w, r)
+	if !ok {
+		return
+	}
+	if comment.Status == "deleted" {
+		http.Error(w, "Comment was deleted", http.StatusBadRequest)
+		return
+	}
+
+	updateInboxComment(ctx, w, comment.ID, bson.M{
+		"$set": bson.M{"status": req.Status, "updated_at": time.Now()},
+	})
+}
+
+// AssignInboxComment assigns a comment to an org member (or clears the assignment).
+// @Summary Atribuir comentário
+// @Description Atribui o comentário a um membro da organização (user_id vazio remove a atribuição)
+// @Tags inbox
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do comentário na caixa"
+// @Param body body models.AssignInboxCommentRequest true "Membro"
+// @Success 200 {object} models.InboxComment
+// @Failure 400 {string} string "Invalid member"
+// @Failure 404 {string} string "Comment not found"
+// @Router /admin/inbox/comments/{id}/assign [put]
+func AssignInboxComment(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	var req models.AssignInboxCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	comment, ok := findInboxComment(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if req.UserID == "" {
+		updateInboxComment(ctx, w, comment.ID, bson.M{
+			"$unset": bson.M{"assigned_to": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		})
+		return
+	}
+
+	assignee, ok := requireOrgMember(ctx, w, orgID, req.UserID)
+	if !ok {
+		return
+	}
+
+	updateInboxComment(ctx, w, comment.ID, bson.M{
+		"$set": bson.M{"assigned_to": assignee, "updated_at": time.Now()},
+	})
+}
+
+// ── Helpers ──────────────────────────────────────────────────────────
+
+// findInboxComment loads the inbox comment from the {id} path value, scoped to the org.
+func findInboxComment(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.InboxComment, bool) {
+	var comment models.InboxComment
+
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return comment, false
+	}
+
+	err = database.InboxComments().FindOne(ctx, bson.M{"_id": oid, "org_id": middleware.GetOrgID(r)}).Decode(&comment)
+	if err != nil {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return comment, false
+	}
+	return comment, true
+}
+
+// updateInboxComment applies the update and writes the updated comment as the response.
+func updateInboxComment(ctx context.Context, w http.ResponseWriter, id primitive.ObjectID, update bson.M) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated models.InboxComment
+	err := database.InboxComments().FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&updated)
+	if err != nil {
+		slog.Error("inbox_comment_update", "error", err, "id", id.Hex())
+		http.Error(w, "Error updating comment", http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
+
+// requireOrgMember validates that userIDHex is a member of the org.
+func requireOrgMember(ctx context.Context, w http.ResponseWriter, orgID primitive.ObjectID, userIDHex string) (primitive.ObjectID, bool) {
+	uid, err := primitive.ObjectIDFromHex(userIDHex)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return primitive.NilObjectID, false
+	}
+	count, err := database.OrgMemberships().CountDocuments(ctx, bson.M{"org_id": orgID, "user_id": uid})
+	if err != nil || count == 0 {
+		http.Error(w, "User is not a member of this organization", http.StatusBadRequest)
+		return primitive.NilObjectID, false
+	}
+	return uid, true
+}
+
+// resolveInboxCommentToken returns the token able to act on the comment's account.
+func resolveInboxCommentToken(ctx context.Context, comment models.InboxComment) (string, error) {
+	if comment.Platform == "facebook" {
+		creds, err := getFacebookCredentials(ctx, primitive.NilObjectID, comment.OrgID)
+		if err != nil {
+			return "", err
+		}
+		if creds == nil || creds.PageID != comment.AccountID {
+			return "", fmt.Errorf("facebook page %s not configured", comment.AccountID)
+		}
+		return creds.Token, nil
+	}
+
+	creds, err := resolveCredsByAccountID(ctx, comment.AccountID)
+	if err != nil {
+		return "", err
+	}
+	if creds == nil {
+		return "", fmt.Errorf("instagram account %s not configured", comment.AccountID)
+	}
+	return creds.Token, nil
+}
+
+// ingestInboxComment upserts a comment into the inbox. Status and assignment
+// are preserved for comments that already exist.
+func ingestInboxComment(ctx context.Context, c models.InboxComment) {
+	if c.OrgID == primitive.NilObjectID || c.CommentID == "" {
+		return
+	}
+
+	now := time.Now()
+	if c.CommentedAt.IsZero() {
+		c.CommentedAt = now
+	}
+
+	set := bson.M{
+		"account_id":   c.AccountID,
+		"media_id":     c.MediaID,
+		"text":         c.Text,
+		"author_id":    c.AuthorID,
+		"commented_at": c.CommentedAt,
+		"updated_at":   now,
+	}
+	if c.AuthorUsername != "" {
+		set["author_username"] = c.AuthorUsername
+	}
+	if c.ParentID != "" {
+		set["parent_id"] = c.ParentID
+	}
+
+	setOnInsert := bson.M{
+		"org_id":     c.OrgID,
+		"status":     "open",
+		"replies":    []models.InboxCommentReply{},
+		"source":     c.Source,
+		"created_at": now,
+	}
+	// Backfill reports the platform's hidden flag; webhooks don't carry it.
+	if c.Source == "backfill" {
+		set["hidden"] = c.Hidden
+	} else {
+		setOnInsert["hidden"] = false
+	}
+
+	_, err := database.InboxComments().UpdateOne(ctx,
+		bson.M{"org_id": c.OrgID, "platform": c.Platform, "comment_id": c.CommentID},
+		bson.M{"$set": set, "$setOnInsert": setOnInsert},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		slog.Error("inbox_ingest_error", "error", err, "comment_id", c.CommentID, "platform", c.Platform)
+	}
+}
+
+// ── Backfill job ─────────────────────────────────────────────────────
+
+// inboxBackfillMaxCommentPages caps how many comment pages a single media or
+// post contributes per backfill run.
+const inboxBackfillMaxCommentPages = 20 // 50 comments per page
+
+// BackfillInboxComments pulls recent comments from every connected Instagram
+// account and Facebook Page into the inbox. Catches anything webhooks missed.
+func BackfillInboxComments() {
+	if !crypto.Available() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var igTotal, fbTotal int
+
+	igCursor, err := database.InstagramConfigs().Find(ctx, bson.M{})
+	if err != nil {
+		slog.Error("inbox_backfill_ig_configs", "error", err)
+		return
+	}
+	var igConfigs []models.InstagramConfig
+	if err := igCursor.All(ctx, &igConfigs); err != nil {
+		slog.Error("inbox_backfill_ig_decode", "error", err)
+		return
+	}
+
+	for _, cfg := range igConfigs {
+		token, err := crypto.Decrypt(cfg.AccessTokenEnc)
+		if err != nil {
+			slog.Warn("inbox_backfill_ig_decrypt", "account", maskAccountID(cfg.InstagramAccountID), "error", err)
+			continue
+		}
+		igTotal += backfillInstagramComments(ctx, cfg.OrgID, cfg.InstagramAccountID, token)
+	}
+
+	fbCursor, err := database.FacebookConfigs().Find(ctx, bson.M{})
+	if err != nil {
+		slog.Error("inbox_backfill_fb_configs", "error", err)
+		return
+	}
+	var fbConfigs []models.FacebookConfig
+	if err := fbCursor.All(ctx, &fbConfigs); err != nil {
+		slog.Error("inbox_backfill_fb_decode", "error", err)
+		return
+	}
+
+	for _, cfg := range fbConfigs {
+		token, err := crypto.Decrypt(cfg.PageAccessToken)
+		if err != nil {
+			slog.Warn("inbox_backfill_fb_decrypt", "page", maskAccountID(cfg.PageID), "error", err)
+			continue
+		}
+		fbTotal += backfillFacebookComments(ctx, cfg.OrgID, cfg.PageID, token)
+	}
+
+	if igTotal > 0 || fbTotal > 0 {
+		slog.Info("inbox_backfill_complete", "instagram_comments", igTotal, "facebook_comments", fbTotal)
+	}
+}
+
+// backfillInstagramComments ingests comments for the account's most recent media.
+func backfillInstagramComments(ctx context.Context, orgID primitive.ObjectID, accountID, token string) int {
+	mediaParams := url.Values{}
+	mediaParams.Set("fields", "id,comments_count")
+	mediaParams.Set("limit", "10")
+	mediaResult, err := metaGraphGet("/"+accountID+"/media", token, mediaParams)
+	if err != nil {
+		slog.Warn("inbox_backfill_ig_media", "account", maskAccountID(accountID), "error", err)
+		return 0
+	}
+
+	mediaList, _ := mediaResult["data"].([]interface{})
+	count := 0
+	for _, m := range mediaList {
+		media, _ := m.(map[string]interface{})
+		mediaID, _ := media["id"].(string)
+		if cc, _ := media["comments_count"].(float64); mediaID == "" || cc == 0 {
+			continue
+		}
+
+		params := url.Values{}
+		params.Set("fields", "id,text,timestamp,hidden,from,username,parent_id")
+		data, err := fetchBackfillComments(mediaID, token, params)
+		if err != nil {
+			slog.Warn("inbox_backfill_ig_comments", "media_id", mediaID, "error", err)
+		}
+
+		for _, item := range data {
+			c, _ := item.(map[string]interface{})
+			from, _ := c["from"].(map[string]interface{})
+			authorID, _ := from["id"].(string)
+			if authorID == accountID {
+				continue // our own replies
+			}
+			username, _ := c["username"].(string)
+			if username == "" {
+				username, _ = from["username"].(string)
+			}
+
+			comment := models.InboxComment{
+				OrgID:          orgID,
+				Platform:       "instagram",
+				AccountID:      accountID,
+				MediaID:        mediaID,
+				AuthorID:       authorID,
+				AuthorUsername: username,
+				Source:         "backfill",
+			}
+			comment.CommentID, _ = c["id"].(string)
+			comment.Text, _ = c["text"].(string)
+			comment.ParentID, _ = c["parent_id"].(string)
+			comment.Hidden, _ = c["hidden"].(bool)
+			if ts, _ := c["timestamp"].(string); ts != "" {
+				comment.CommentedAt, _ = time.Parse("2006-01-02T15:04:05-0700", ts)
+			}
+
+			ingestInboxComment(ctx, comment)
+			count++
+		}
+	}
+	return count
+}
+
+// backfillFacebookComments ingests comments for the page's most recent posts.
+func backfillFacebookComments(ctx context.Context, orgID primitive.ObjectID, pageID, token string) int {
+	postParams := url.Values{}
+	postParams.Set("fields", "id")
+	postParams.Set("limit", "10")
+	postsResult, err := metaGraphGet("/"+pageID+"/posts", token, postParams)
+	if err != nil {
+		slog.Warn("inbox_backfill_fb_posts", "page", maskAccountID(pageID), "error", err)
+		return 0
+	}
+
+	posts, _ := postsResult["data"].([]interface{})
+	count := 0
+	for _, p := range posts {
+		post, _ := p.(map[string]interface{})
+		postID, _ := post["id"].(string)
+		if postID == "" {
+			continue
+		}
+
+		params := url.Values{}
+		params.Set("fields", "id,message,created_time,is_hidden,from,parent{id}")
+		params.Set("filter", "stream")
+		data, err := fetchBackfillComments(postID, token, params)
+		if err != nil {
+			slog.Warn("inbox_backfill_fb_comments", "post_id", postID, "error", err)
+		}
+
+		for _, item := range data {
+			c, _ := item.(map[string]interface{})
+			from, _ := c["from"].(map[string]interface{})
+			authorID, _ := from["id"].(string)
+			if authorID == pageID {
+				continue // our own replies
+			}
+			parent, _ := c["parent"].(map[string]interface{})
+
+			comment := models.InboxComment{
+				OrgID:     orgID,
+				Platform:  "facebook",
+				AccountID: pageID,
+				MediaID:   postID,
+				AuthorID:  authorID,
+				Source:    "backfill",
+			}
+			comment.CommentID, _ = c["id"].(string)
+			comment.Text, _ = c["message"].(string)
+			comment.AuthorUsername, _ = from["name"].(string)
+			comment.ParentID, _ = parent["id"].(string)
+			comment.Hidden, _ = c["is_hidden"].(bool)
+			if ts, _ := c["created_time"].(string); ts != "" {
+				comment.CommentedAt, _ = time.Parse("2006-01-02T15:04:05-0700", ts)
+			}
+
+			ingestInboxComment(ctx, comment)
+			count++
+		}
+	}
+	return count
+}
+
+// fetchBackfillComments pages through the comments of a media or post, up to
+// inboxBackfillMaxCommentPages. On error it returns the pages read so far.
+func fetchBackfillComments(objectID, token string, params url.Values) ([]interface{}, error) {
+	var comments []interface{}
+	after := ""
+	for page := 0; page < inboxBackfillMaxCommentPages; page++ {
+		params.Set("limit", "50")
+		if after != "" {
+			params.Set("after", after)
+		}
+
+		result, err := metaGraphGet("/"+objectID+"/comments", token, params)
+		if err != nil {
+			return comments, err
+		}
+
+		data, _ := result["data"].([]interface{})
+		comments = append(comments, data...)
+
+		paging, _ := result["paging"].(map[string]interface{})
+		cursors, _ := paging["cursors"].(map[string]interface{})
+		after, _ = cursors["after"].(string)
+		if _, hasNext := paging["next"]; !hasNext || after == "" {
+			return comments, nil
+		}
+	}
+
+	slog.Warn("inbox_backfill_comments_truncated", "object_id", objectID, "comments", len(comments))
+	return comments, nil
+}
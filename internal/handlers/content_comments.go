@@ -0,0 +1,491 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxContentCommentLength = 2000
+
+// commentMentionRe matches @handle or @e-mail, not preceded by a word character.
+var commentMentionRe = regexp.MustCompile(`(?:^|[^\w@.])@([\w.%+\-]+(?:@[\w\-]+(?:\.[\w\-]+)+)?)`)
+
+// contentCommentItem is the part of a commented item needed for checks and notifications.
+type contentCommentItem struct {
+	ID      primitive.ObjectID `bson:"_id"`
+	UserID  primitive.ObjectID `bson:"user_id"`
+	Caption string             `bson:"caption"`
+	Message string             `bson:"message"`
+}
+
+// findContentItem resolves {type}/{id} from the path to an item of the caller's org.
+func findContentItem(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, contentCommentItem, bool) {
+	itemType := r.PathValue("type")
+	collection, ok := contentCollections[itemType]
+	if !ok {
+		http.Error(w, "type must be instagram_schedule, facebook_schedule, threads_schedule or integrated_publish", http.StatusBadRequest)
+		return "", contentCommentItem{}, false
+	}
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return "", contentCommentItem{}, false
+	}
+
+	var item contentCommentItem
+	opts := options.FindOne().SetProjection(bson.M{"user_id": 1, "caption": 1, "message": 1})
+	filter := bson.M{"_id": oid, "org_id": middleware.GetOrgID(r)}
+	if err := collection().FindOne(ctx, filter, opts).Decode(&item); err != nil {
+		http.Error(w, "Item not found", http.StatusNotFound)
+		return "", contentCommentItem{}, false
+	}
+	return itemType, item, true
+}
+
+// findContentComment loads the {commentId} comment of the given item.
+func findContentComment(ctx context.Context, w http.ResponseWriter, r *http.Request, itemType string, itemID primitive.ObjectID) (models.ContentComment, bool) {
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("commentId"))
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return models.ContentComment{}, false
+	}
+	var comment models.ContentComment
+	filter := bson.M{"_id": oid, "org_id": middleware.GetOrgID(r), "item_type": itemType, "item_id": itemID}
+	if err := database.ContentComments().FindOne(ctx, filter).Decode(&comment); err != nil {
+		http.Error(w, "Comment not found", http.StatusNotFound)
+		return models.ContentComment{}, false
+	}
+	return comment, true
+}
+
+func validateContentCommentBody(body string) (string, string) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", "body is required"
+	}
+	if utf8.RuneCountInString(body) > maxContentCommentLength {
+		return "", fmt.Sprintf("body must be %d characters or less", maxContentCommentLength)
+	}
+	return body, ""
+}
+
+// resolveMentions returns the org members mentioned in body, matched by full e-mail or
+// by the part before the @ (case-insensitive). The author is never included.
+func resolveMentions(ctx context.Context, orgID, authorID primitive.ObjectID, body string) []primitive.ObjectID {
+	handles := map[string]bool{}
+	for _, m := range commentMentionRe.FindAllStringSubmatch(body, -1) {
+		handles[strings.ToLower(strings.TrimRight(m[1], "."))] = true
+	}
+	if len(handles) == 0 {
+		return nil
+	}
+
+	cursor, err := database.OrgMemberships().Find(ctx, bson.M{"org_id": orgID})
+	if err != nil {
+		return nil
+	}
+	var members []models.OrgMembership
+	cursor.All(ctx, &members)
+	cursor.Close(ctx)
+	memberIDs := make([]primitive.ObjectID, 0, len(members))
+	for _, m := range members {
+		memberIDs = append(memberIDs, m.UserID)
+	}
+
+	var users []models.User
+	opts := options.Find().SetProjection(bson.M{"email": 1})
+	if cursor, err := database.Users().Find(ctx, bson.M{"_id": bson.M{"$in": memberIDs}}, opts); err == nil {
+		cursor.All(ctx, &users)
+		cursor.Close(ctx)
+	}
+
+	var mentioned []primitive.ObjectID
+	for _, u := range users {
+		email := strings.ToLower(u.Email)
+		local, _, _ := strings.Cut(email, "@")
+		if u.ID != authorID && (handles[email] || handles[local]) {
+			mentioned = append(mentioned, u.ID)
+		}
+	}
+	return mentioned
+}
+
+// notifyMentions notifies newly mentioned members about a comment.
+func notifyMentions(ctx context.Context, orgID primitive.ObjectID, userIDs []primitive.ObjectID, authorID primitive.ObjectID, itemType string, item contentCommentItem, body string) {
+	if len(userIDs) == 0 {
+		return
+	}
+	var profile models.Profile
+	database.Profiles().FindOne(ctx, bson.M{"user_id": authorID}).Decode(&profile)
+	author := profile.Name
+	if author == "" {
+		author = "Alguém"
+	}
+
+	caption := item.Caption
+	if itemType == "facebook_schedule" {
+		caption = item.Message
+	}
+	title := author + " mencionou você em um comentário"
+	message := fmt.Sprintf("Em \"%s\": %s", calendarTitle(caption), calendarTitle(body))
+	link := fmt.Sprintf("%s?type=%s&id=%s", approvalLink, itemType, item.ID.Hex())
+	notifyUsers(ctx, orgID, userIDs, "comment_mention", title, message, link, true)
+}
+
+// newMentions returns the IDs in after that are not in before.
+func newMentions(before, after []primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool, len(before))
+	for _, id := range before {
+		seen[id] = true
+	}
+	var added []primitive.ObjectID
+	for _, id := range after {
+		if !seen[id] {
+			added = append(added, id)
+		}
+	}
+	return added
+}
+
+// enrichContentComments adds author names and nests replies under their thread root.
+// Replies whose root is not among comments are returned at the top level.
+func enrichContentComments(ctx context.Context, comments []models.ContentComment) []models.ContentCommentResponse {
+	authorIDs := map[primitive.ObjectID]bool{}
+	for _, c := range comments {
+		authorIDs[c.UserID] = true
+	}
+	ids := make([]primitive.ObjectID, 0, len(authorIDs))
+	for id := range authorIDs {
+		ids = append(ids, id)
+	}
+	profileMap := map[primitive.ObjectID]models.Profile{}
+	if cursor, err := database.Profiles().Find(ctx, bson.M{"user_id": bson.M{"$in": ids}}); err == nil {
+		var profiles []models.Profile
+		if cursor.All(ctx, &profiles) == nil {
+			for _, p := range profiles {
+				profileMap[p.UserID] = p
+			}
+		}
+		cursor.Close(ctx)
+	}
+
+	roots := []models.ContentCommentResponse{}
+	index := map[primitive.ObjectID]int{}
+	var replies []models.ContentCommentResponse
+	for _, c := range comments {
+		resp := models.ContentCommentResponse{ContentComment: c}
+		if profile, ok := profileMap[c.UserID]; ok {
+			resp.AuthorName = profile.Name
+			resp.AuthorAvatar = profile.Avatar
+		}
+		if c.ParentID == nil {
+			index[c.ID] = len(roots)
+			roots = append(roots, resp)
+		} else {
+			replies = append(replies, resp)
+		}
+	}
+	for _, reply := range replies {
+		if i, ok := index[*reply.ParentID]; ok {
+			roots[i].Replies = append(roots[i].Replies, reply)
+		} else {
+			roots = append(roots, reply) // thread root not in this batch
+		}
+	}
+	return roots
+}
+
+// contentCommentCounts returns the number of comments per item of one type.
+func contentCommentCounts(ctx context.Context, orgID primitive.ObjectID, itemType string, itemIDs []primitive.ObjectID) map[primitive.ObjectID]int {
+	counts := map[primitive.ObjectID]int{}
+	if len(itemIDs) == 0 {
+		return counts
+	}
+	pipeline := []bson.M{
+		{"$match": bson.M{"org_id": orgID, "item_type": itemType, "item_id": bson.M{"$in": itemIDs}}},
+		{"$group": bson.M{"_id": "$item_id", "count": bson.M{"$sum": 1}}},
+	}
+	cursor, err := database.ContentComments().Aggregate(ctx, pipeline)
+	if err != nil {
+		return counts
+	}
+	defer cursor.Close(ctx)
+	var rows []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Count int                `bson:"count"`
+	}
+	cursor.All(ctx, &rows)
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts
+}
+
+// fillCalendarCommentCounts sets CommentCount on calendar items.
+func fillCalendarCommentCounts(ctx context.Context, orgID primitive.ObjectID, items []models.CalendarItem) {
+	byType := map[string][]primitive.ObjectID{}
+	for _, item := range items {
+		if oid, err := primitive.ObjectIDFromHex(item.ID); err == nil {
+			byType[item.Type] = append(byType[item.Type], oid)
+		}
+	}
+	counts := map[string]map[primitive.ObjectID]int{}
+	for itemType, ids := range byType {
+		counts[itemType] = contentCommentCounts(ctx, orgID, itemType, ids)
+	}
+	for i := range items {
+		if oid, err := primitive.ObjectIDFromHex(items[i].ID); err == nil {
+			items[i].CommentCount = counts[items[i].Type][oid]
+		}
+	}
+}
+
+// deleteContentComments removes every comment of a deleted item.
+func deleteContentComments(ctx context.Context, orgID primitive.ObjectID, itemType string, itemID primitive.ObjectID) {
+	if _, err := database.ContentComments().DeleteMany(ctx, bson.M{"org_id": orgID, "item_type": itemType, "item_id": itemID}); err != nil {
+		slog.Error("content_comments_delete", "item_type", itemType, "item_id", itemID.Hex(), "error", err)
+	}
+}
+
+// ListContentComments lists an item's internal comment threads.
+// @Summary Listar comentários internos
+// @Description Lista os comentários internos da equipe em um agendamento do Instagram, do Facebook ou do Threads ou em uma publicação integrada, agrupados em threads (mais antigos primeiro). Visível apenas para membros da organização
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "instagram_schedule, facebook_schedule, threads_schedule ou integrated_publish"
+// @Param id path string true "ID do item"
+// @Success 200 {object} models.ContentCommentListResponse
+// @Failure 404 {string} string "Item not found"
+// @Router /admin/content/{type}/{id}/comments [get]
+func ListContentComments(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	itemType, item, ok := findContentItem(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{"org_id": middleware.GetOrgID(r), "item_type": itemType, "item_id": item.ID}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := database.ContentComments().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error fetching comments", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var comments []models.ContentComment
+	if err := cursor.All(ctx, &comments); err != nil {
+		http.Error(w, "Error decoding comments", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.ContentCommentListResponse{
+		Comments: enrichContentComments(ctx, comments),
+		Total:    int64(len(comments)),
+	})
+}
+
+// CreateContentComment adds a comment or a reply and notifies mentioned members.
+// @Summary Comentar item
+// @Description Adiciona um comentário interno (ou resposta, com parent_id) a um item. Membros mencionados com @e-mail ou @nome (parte do e-mail antes do @) são notificados
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "instagram_schedule, facebook_schedule, threads_schedule ou integrated_publish"
+// @Param id path string true "ID do item"
+// @Param body body models.CreateContentCommentRequest true "Comentário"
+// @Success 201 {object} models.ContentCommentResponse
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Item not found"
+// @Router /admin/content/{type}/{id}/comments [post]
+func CreateContentComment(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	userID := middleware.GetUserID(r)
+
+	var req models.CreateContentCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	body, msg := validateContentCommentBody(req.Body)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	itemType, item, ok := findContentItem(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	comment := models.ContentComment{
+		ID:        primitive.NewObjectID(),
+		OrgID:     orgID,
+		ItemType:  itemType,
+		ItemID:    item.ID,
+		UserID:    userID,
+		Body:      body,
+		Mentions:  resolveMentions(ctx, orgID, userID, body),
+		CreatedAt: time.Now(),
+	}
+	if req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err != nil {
+			http.Error(w, "Invalid parent_id", http.StatusBadRequest)
+			return
+		}
+		var parent models.ContentComment
+		filter := bson.M{"_id": parentID, "org_id": orgID, "item_type": itemType, "item_id": item.ID}
+		if err := database.ContentComments().FindOne(ctx, filter).Decode(&parent); err != nil {
+			http.Error(w, "Parent comment not found", http.StatusBadRequest)
+			return
+		}
+		// Threads are one level deep: replying to a reply joins its thread
+		if parent.ParentID != nil {
+			parentID = *parent.ParentID
+		}
+		comment.ParentID = &parentID
+	}
+
+	if _, err := database.ContentComments().InsertOne(ctx, comment); err != nil {
+		http.Error(w, "Error creating comment", http.StatusInternalServerError)
+		return
+	}
+	notifyMentions(ctx, orgID, comment.Mentions, userID, itemType, item, body)
+
+	slog.Info("content_comment_created",
+		"comment_id", comment.ID.Hex(),
+		"item_type", itemType,
+		"item_id", item.ID.Hex(),
+		"mentions", len(comment.Mentions),
+	)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(enrichContentComments(ctx, []models.ContentComment{comment})[0])
+}
+
+// UpdateContentComment edits the caller's own comment; members mentioned for the
+// first time are notified.
+// @Summary Editar comentário interno
+// @Description Edita um comentário do próprio usuário. Novas menções são notificadas
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type path string true "instagram_schedule, facebook_schedule, threads_schedule ou integrated_publish"
+// @Param id path string true "ID do item"
+// @Param commentId path string true "ID do comentário"
+// @Param body body models.UpdateContentCommentRequest true "Novo texto"
+// @Success 200 {object} models.ContentCommentResponse
+// @Failure 403 {string} string "Only the author can edit a comment"
+// @Failure 404 {string} string "Comment not found"
+// @Router /admin/content/{type}/{id}/comments/{commentId} [patch]
+func UpdateContentComment(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	userID := middleware.GetUserID(r)
+
+	var req models.UpdateContentCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	body, msg := validateContentCommentBody(req.Body)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	itemType, item, ok := findContentItem(ctx, w, r)
+	if !ok {
+		return
+	}
+	comment, ok := findContentComment(ctx, w, r, itemType, item.ID)
+	if !ok {
+		return
+	}
+	if comment.UserID != userID {
+		http.Error(w, "Only the author can edit a comment", http.StatusForbidden)
+		return
+	}
+
+	mentions := resolveMentions(ctx, orgID, userID, body)
+	added := newMentions(comment.Mentions, mentions)
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"body": body, "mentions": mentions, "edited_at": now}}
+	if _, err := database.ContentComments().UpdateOne(ctx, bson.M{"_id": comment.ID}, update); err != nil {
+		http.Error(w, "Error updating comment", http.StatusInternalServerError)
+		return
+	}
+	notifyMentions(ctx, orgID, added, userID, itemType, item, body)
+
+	comment.Body = body
+	comment.Mentions = mentions
+	comment.EditedAt = &now
+	json.NewEncoder(w).Encode(enrichContentComments(ctx, []models.ContentComment{comment})[0])
+}
+
+// DeleteContentComment deletes a comment and, for a thread root, its replies.
+// @Summary Excluir comentário interno
+// @Description Exclui um comentário (autor, owner ou admin). Excluir o primeiro comentário de uma thread exclui as respostas
+// @Tags comments
+// @Security BearerAuth
+// @Param type path string true "instagram_schedule, facebook_schedule, threads_schedule ou integrated_publish"
+// @Param id path string true "ID do item"
+// @Param commentId path string true "ID do comentário"
+// @Success 204
+// @Failure 403 {string} string "Forbidden"
+// @Failure 404 {string} string "Comment not found"
+// @Router /admin/content/{type}/{id}/comments/{commentId} [delete]
+func DeleteContentComment(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	itemType, item, ok := findContentItem(ctx, w, r)
+	if !ok {
+		return
+	}
+	comment, ok := findContentComment(ctx, w, r, itemType, item.ID)
+	if !ok {
+		return
+	}
+	role := middleware.GetOrgRole(r)
+	if comment.UserID != userID && role != "owner" && role != "admin" {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	filter := bson.M{"org_id": comment.OrgID, "$or": []bson.M{{"_id": comment.ID}, {"parent_id": comment.ID}}}
+	if _, err := database.ContentComments().DeleteMany(ctx, filter); err != nil {
+		http.Error(w, "Error deleting comment", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("content_comment_deleted", "comment_id", comment.ID.Hex(), "item_type", itemType)
+	w.WriteHeader(http.StatusNoContent)
+}
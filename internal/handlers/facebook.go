@@ -34,6 +34,47 @@ type facebookCredentials struct {
 	OrgID    primitive.ObjectID
 }
 
+// validateFacebookPost checks a Facebook post's shape and that its images and the org's
+// video exist. Returns an error message, or "" when valid.
+func validateFacebookPost(ctx context.Context, orgID primitive.ObjectID, message, mediaType, linkURL string, imageIDs []string, videoID string) string {
+	if msg := facebookPostCheck(message, mediaType, linkURL, len(imageIDs), videoID != ""); msg != "" {
+		return msg
+	}
+	for _, imgID := range imageIDs {
+		if issue, ok := checkInstagramImage(ctx, imgID); !ok && issue.Code != "aspect_ratio" {
+			return issue.Message
+		}
+	}
+	if videoID != "" && !facebookVideoExists(ctx, orgID, videoID) {
+		return "Video not found: " + videoID
+	}
+	return ""
+}
+
+// facebookPostCheck checks the media type against the message, link, image count and video.
+func facebookPostCheck(message, mediaType, linkURL string, imageCount int, hasVideo bool) string {
+	switch {
+	case !validFacebookMediaTypes[mediaType]:
+		return facebookMediaTypeError
+	case isFacebookVideoType(mediaType) && !hasVideo:
+		return "video_id is required for video and reel types"
+	case mediaType == "image" && imageCount != 1:
+		return "image type requires exactly one image; use 'carousel' for multiple"
+	case mediaType == "carousel" && imageCount < 2:
+		return "carousel requires at least 2 images"
+	case mediaType == "link" && linkURL == "":
+		return "link_url is required for link type"
+	case mediaType == "text" && message == "":
+		return "message is required for text type"
+	}
+	return ""
+}
+
+// validFacebookMediaTypes lists the supported Facebook post types.
+var validFacebookMediaTypes = map[string]bool{"text": true, "image": true, "carousel": true, "link": true, "video": true, "reel": true}
+
+const facebookMediaTypeError = "media_type must be 'text', 'image', 'carousel', 'link', 'video', or 'reel'"
+
 // getFacebookCredentials resolves credentials from DB per-org config.
 func getFacebookCredentials(ctx context.Context, userID, orgID primitive.ObjectID) (*facebookCredentials, error) {
 	if orgID == primitive.NilObjectID || !crypto.Available() {
@@ -120,6 +161,11 @@ func GetFacebookConfig(w http.ResponseWriter, r *http.Request) {
 		resp.PageID = maskAccountID(creds.PageID)
 		resp.PageName = creds.PageName
 		resp.Source = creds.Source
+
+		var cfg models.FacebookConfig
+		if database.FacebookConfigs().FindOne(ctx, bson.M{"org_id": orgID}).Decode(&cfg) == nil {
+			resp.Token = tokenHealth(cfg.TokenMeta)
+		}
 	}
 
 	json.NewEncoder(w).Encode(resp)
@@ -194,6 +240,7 @@ func SaveFacebookConfig(w http.ResponseWriter, r *http.Request) {
 	setFields := bson.M{
 		"page_id":               req.PageID,
 		"page_access_token_enc": encToken,
+		"token_meta":            newTokenMeta(req.PageAccessToken, 0),
 		"updated_at":            now,
 	}
 	if pageName != "" {
@@ -217,6 +264,9 @@ func SaveFacebookConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Best-effort: auto-replies need feed and messages events for this page
+	go subscribeFacebookPageWebhooks(req.PageID, req.PageAccessToken)
+
 	slog.Info("facebook_config_saved",
 		"user_id", userID.Hex(),
 		"page_id", maskAccountID(req.PageID),
@@ -520,9 +570,8 @@ func CreateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate media_type
-	validTypes := map[string]bool{"text": true, "image": true, "carousel": true, "link": true}
-	if !validTypes[req.MediaType] {
-		http.Error(w, "media_type must be 'text', 'image', 'carousel', or 'link'", http.StatusBadRequest)
+	if !validFacebookMediaTypes[req.MediaType] {
+		http.Error(w, facebookMediaTypeError, http.StatusBadRequest)
 		return
 	}
 
@@ -552,6 +601,11 @@ func CreateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isFacebookVideoType(req.MediaType) && req.VideoID == "" {
+		http.Error(w, "video_id is required for video and reel types", http.StatusBadRequest)
+		return
+	}
+
 	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
 	if err != nil {
 		http.Error(w, "scheduled_at must be a valid ISO 8601 date", http.StatusBadRequest)
@@ -574,6 +628,12 @@ func CreateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if msg := checkFacebookVideoRefs(ctx, orgID, req.VideoID, req.ThumbnailID); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	status, approvals := initialApprovalState(ctx, orgID, userID)
 
 	now := time.Now()
 	schedule := models.FacebookSchedule{
@@ -584,8 +644,13 @@ func CreateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 		MediaType:   req.MediaType,
 		ImageIDs:    req.ImageIDs,
 		LinkURL:     req.LinkURL,
+		VideoID:     req.VideoID,
+		ThumbnailID: req.ThumbnailID,
+		Title:       req.Title,
 		ScheduledAt: scheduledAt,
-		Status:      "scheduled",
+		Status:      status,
+		Native:      req.Native,
+		Approvals:   approvals,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -595,6 +660,13 @@ func CreateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error creating schedule", http.StatusInternalServerError)
 		return
 	}
+	if status == "pending_approval" {
+		notifyReviewers(ctx, orgID, 1, schedule.Message)
+	}
+	// Hand off to Facebook now; approvals and far-off posts are handed off by SyncNativeFacebookSchedules
+	if schedule.Native {
+		schedule, _ = syncNativeFacebookSchedule(ctx, schedule, schedule)
+	}
 
 	slog.Info("facebook_schedule_created",
 		"schedule_id", schedule.ID.Hex(),
@@ -665,9 +737,16 @@ func ListFacebookSchedules(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]primitive.ObjectID, len(schedules))
+	for i, s := range schedules {
+		ids[i] = s.ID
+	}
+	counts := contentCommentCounts(ctx, orgID, "facebook_schedule", ids)
+
 	responses := make([]models.FacebookScheduleResponse, len(schedules))
 	for i, s := range schedules {
 		responses[i] = buildFacebookScheduleResponse(s)
+		responses[i].CommentCount = counts[s.ID]
 	}
 
 	json.NewEncoder(w).Encode(models.FacebookScheduleListResponse{
@@ -754,8 +833,8 @@ func UpdateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if schedule.Status != "scheduled" && schedule.Status != "failed" {
-		http.Error(w, "Can only edit scheduled or failed posts", http.StatusBadRequest)
+	if schedule.Status != "scheduled" && schedule.Status != "failed" && schedule.Status != "pending_approval" && schedule.Status != "rejected" {
+		http.Error(w, "Can only edit scheduled, failed or in-review posts", http.StatusBadRequest)
 		return
 	}
 
@@ -767,9 +846,8 @@ func UpdateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.MediaType != nil {
-		validTypes := map[string]bool{"text": true, "image": true, "carousel": true, "link": true}
-		if !validTypes[*req.MediaType] {
-			http.Error(w, "media_type must be 'text', 'image', 'carousel', or 'link'", http.StatusBadRequest)
+		if !validFacebookMediaTypes[*req.MediaType] {
+			http.Error(w, facebookMediaTypeError, http.StatusBadRequest)
 			return
 		}
 		setFields["media_type"] = *req.MediaType
@@ -796,6 +874,43 @@ func UpdateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 		setFields["link_url"] = *req.LinkURL
 	}
 
+	if req.VideoID != nil || req.ThumbnailID != nil {
+		videoID, thumbnailID := "", ""
+		if req.VideoID != nil {
+			videoID = *req.VideoID
+			setFields["video_id"] = videoID
+		}
+		if req.ThumbnailID != nil {
+			thumbnailID = *req.ThumbnailID
+			setFields["thumbnail_id"] = thumbnailID
+		}
+		if msg := checkFacebookVideoRefs(ctx, orgID, videoID, thumbnailID); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Title != nil {
+		setFields["title"] = *req.Title
+	}
+
+	if req.Native != nil {
+		setFields["native"] = *req.Native
+	}
+
+	mediaType := schedule.MediaType
+	if req.MediaType != nil {
+		mediaType = *req.MediaType
+	}
+	hasVideo := schedule.VideoID != ""
+	if req.VideoID != nil {
+		hasVideo = *req.VideoID != ""
+	}
+	if isFacebookVideoType(mediaType) && !hasVideo {
+		http.Error(w, "video_id is required for video and reel types", http.StatusBadRequest)
+		return
+	}
+
 	if req.ScheduledAt != nil {
 		scheduledAt, err := time.Parse(time.RFC3339, *req.ScheduledAt)
 		if err != nil {
@@ -811,6 +926,18 @@ func UpdateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 		setFields["error_message"] = ""
 	}
 
+	// Editing a single occurrence detaches it from later series edits
+	if schedule.SeriesID != nil {
+		setFields["detached"] = true
+	}
+
+	// Under the approval workflow, edits send the post back to review
+	resubmitted := resubmitOnEdit(ctx, orgID, middleware.GetUserID(r), schedule.Status)
+	if resubmitted != nil {
+		setFields["status"] = "pending_approval"
+		update["$push"] = bson.M{"approvals": *resubmitted}
+	}
+
 	_, err = database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": oid, "org_id": orgID}, update)
 	if err != nil {
 		http.Error(w, "Error updating schedule", http.StatusInternalServerError)
@@ -819,6 +946,16 @@ func UpdateFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 
 	var updated models.FacebookSchedule
 	database.FacebookSchedules().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&updated)
+	if resubmitted != nil {
+		notifyReviewers(ctx, orgID, 1, updated.Message)
+	}
+
+	// Update, cancel or hand off the post scheduled on Facebook
+	updated, err = syncNativeFacebookSchedule(ctx, schedule, updated)
+	if err != nil {
+		http.Error(w, "Schedule saved, but the post scheduled on Facebook could not be updated: "+err.Error(), http.StatusBadGateway)
+		return
+	}
 
 	slog.Info("facebook_schedule_updated", "schedule_id", oid.Hex())
 
@@ -858,36 +995,53 @@ func DeleteFacebookSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if schedule.Status == "publishing" {
+	if schedule.Status == "publishing" || schedule.Status == "processing" {
 		http.Error(w, "Cannot delete a post that is currently publishing", http.StatusBadRequest)
 		return
 	}
 
+	if err := cancelNativeFacebookPost(ctx, schedule); err != nil {
+		http.Error(w, "Error cancelling the post scheduled on Facebook: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
 	_, err = database.FacebookSchedules().DeleteOne(ctx, bson.M{"_id": oid, "org_id": orgID})
 	if err != nil {
 		http.Error(w, "Error deleting schedule", http.StatusInternalServerError)
 		return
 	}
+	skipSeriesOccurrence(ctx, schedule.SeriesID, schedule.OccurrenceAt)
+	deleteContentComments(ctx, orgID, "facebook_schedule", oid)
 
 	slog.Info("facebook_schedule_deleted", "schedule_id", oid.Hex())
 
 	json.NewEncoder(w).Encode(map[string]string{"message": "Schedule deleted"})
 }
 
-// publishToFacebook publishes a scheduled post to Facebook Page via Graph API
+// publishToFacebook publishes a scheduled post to Facebook Page via Graph API.
+// For video and Reel posts it returns the Facebook video ID; the video is
+// still processing and the post appears once processing completes.
 func publishToFacebook(schedule models.FacebookSchedule) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	timeout := 90 * time.Second
+	if isFacebookVideoType(schedule.MediaType) {
+		timeout = facebookVideoUploadTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	creds, err := getFacebookCredentials(ctx, schedule.UserID, schedule.OrgID)
+	p, err := newPublisher(ctx, "facebook", schedule.UserID, schedule.OrgID)
 	if err != nil {
-		return "", fmt.Errorf("get credentials: %w", err)
-	}
-	if creds == nil {
-		return "", fmt.Errorf("facebook not configured")
+		return "", err
 	}
+	return publishWith(ctx, p, facebookPublishContent(schedule))
+}
 
+// publishFacebookContent publishes schedule's content to the credentials' page,
+// choosing the Graph API flow from the media type. Used by facebookPublisher.
+func publishFacebookContent(ctx context.Context, creds *facebookCredentials, schedule models.FacebookSchedule) (string, error) {
 	switch schedule.MediaType {
+	case "video", "reel":
+		return publishFacebookVideo(ctx, creds, schedule)
 	case "text":
 		return publishFacebookTextPost(creds.PageID, creds.Token, schedule.Message)
 	case "link":
@@ -1112,67 +1266,125 @@ func uploadUnpublishedPhoto(pageID, token, imageURL string) (string, error) {
 	return result.ID, nil
 }
 
-// ProcessScheduledFacebookPosts checks for due posts and publishes them
+// ProcessScheduledFacebookPosts checks for due posts and publishes them.
+// Posts handed to Facebook's native scheduler are tracked by SyncNativeFacebookSchedules;
+// videos and Reels are uploaded by ProcessScheduledFacebookVideos.
 func ProcessScheduledFacebookPosts() {
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	processDueFacebookSchedules(bson.M{"$nin": facebookVideoTypes})
+}
+
+// ProcessScheduledFacebookVideos uploads due video and Reel posts. Uploads take
+// minutes, so they run in their own job instead of holding up the other posts.
+func ProcessScheduledFacebookVideos() {
+	processDueFacebookSchedules(bson.M{"$in": facebookVideoTypes})
+}
+
+// processDueFacebookSchedules publishes the due local schedules whose media_type
+// matches mediaTypes, one at a time.
+func processDueFacebookSchedules(mediaTypes bson.M) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	now := time.Now()
 	filter := bson.M{
-		"status":       "scheduled",
-		"scheduled_at": bson.M{"$lte": now},
+		"status":         "scheduled",
+		"scheduled_at":   bson.M{"$lte": time.Now()},
+		"native_post_id": bson.M{"$in": []interface{}{nil, ""}},
+		"media_type":     mediaTypes,
 	}
 
-	cursor, err := database.FacebookSchedules().Find(ctx, filter)
+	cursor, err := database.FacebookSchedules().Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
 	if err != nil {
 		slog.Error("facebook_scheduler_query_error", "error", err)
 		return
 	}
 	defer cursor.Close(ctx)
 
-	var schedules []models.FacebookSchedule
-	if err := cursor.All(ctx, &schedules); err != nil {
+	var due []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &due); err != nil {
 		slog.Error("facebook_scheduler_decode_error", "error", err)
 		return
 	}
 
-	for _, schedule := range schedules {
-		// Set status to publishing
-		database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
-			"$set": bson.M{"status": "publishing", "updated_at": time.Now()},
-		})
+	for _, d := range due {
+		publishDueFacebookSchedule(d.ID)
+	}
+}
 
-		postID, err := publishToFacebook(schedule)
-		if err != nil {
-			slog.Error("facebook_publish_failed",
-				"schedule_id", schedule.ID.Hex(),
-				"error", err,
-			)
-			database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
-				"$set": bson.M{
-					"status":        "failed",
-					"error_message": err.Error(),
-					"updated_at":    time.Now(),
-				},
-			})
-			continue
+// publishDueFacebookSchedule claims one due schedule and publishes it. Earlier
+// items in the batch may take minutes, so every step uses its own context, and
+// the claim only succeeds while the schedule is still due.
+func publishDueFacebookSchedule(id primitive.ObjectID) {
+	cctx, ccancel := context.WithTimeout(context.Background(), 10*time.Second)
+	var schedule models.FacebookSchedule
+	err := database.FacebookSchedules().FindOneAndUpdate(cctx,
+		bson.M{
+			"_id":            id,
+			"status":         "scheduled",
+			"scheduled_at":   bson.M{"$lte": time.Now()},
+			"native_post_id": bson.M{"$in": []interface{}{nil, ""}},
+		},
+		bson.M{"$set": bson.M{"status": "publishing", "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&schedule)
+	ccancel()
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			slog.Error("facebook_scheduler_claim_error", "schedule_id", id.Hex(), "error", err)
 		}
+		return // edited, rescheduled or claimed meanwhile
+	}
 
-		database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
+	postID, err := publishToFacebook(schedule)
+
+	uctx, ucancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ucancel()
+	if err == nil && isFacebookVideoType(schedule.MediaType) {
+		// Facebook is still processing the video; PollFacebookVideoProcessing finishes the post
+		database.FacebookSchedules().UpdateOne(uctx, bson.M{"_id": schedule.ID}, bson.M{
 			"$set": bson.M{
-				"status":      "published",
-				"fb_post_id":  postID,
+				"status":      "processing",
+				"fb_video_id": postID,
 				"updated_at":  time.Now(),
 			},
 		})
-
-		middleware.IncFacebookPublished()
-
-		slog.Info("facebook_published",
+		slog.Info("facebook_video_uploaded",
 			"schedule_id", schedule.ID.Hex(),
-			"fb_post_id", postID,
+			"fb_video_id", postID,
 		)
+		return
 	}
+	if err != nil {
+		slog.Error("facebook_publish_failed",
+			"schedule_id", schedule.ID.Hex(),
+			"error", err,
+		)
+		database.FacebookSchedules().UpdateOne(uctx, bson.M{"_id": schedule.ID}, bson.M{
+			"$set": bson.M{
+				"status":        "failed",
+				"error_message": err.Error(),
+				"updated_at":    time.Now(),
+			},
+		})
+		return
+	}
+
+	database.FacebookSchedules().UpdateOne(uctx, bson.M{"_id": schedule.ID}, bson.M{
+		"$set": bson.M{
+			"status":       "published",
+			"fb_post_id":   postID,
+			"published_at": time.Now(),
+			"updated_at":   time.Now(),
+		},
+	})
+
+	middleware.IncFacebookPublished()
+
+	slog.Info("facebook_published",
+		"schedule_id", schedule.ID.Hex(),
+		"fb_post_id", postID,
+	)
 }
 
 // UploadFacebookImage uploads an image for Facebook posting (reuses Instagram upload logic)
@@ -1269,8 +1481,12 @@ func buildFacebookScheduleResponse(s models.FacebookSchedule) models.FacebookSch
 	for i, id := range s.ImageIDs {
 		imageURLs[i] = "/api/v1/blog/images/" + id
 	}
-	return models.FacebookScheduleResponse{
+	resp := models.FacebookScheduleResponse{
 		FacebookSchedule: s,
 		ImageURLs:        imageURLs,
 	}
+	if s.ThumbnailID != "" {
+		resp.ThumbnailURL = "/api/v1/blog/images/" + s.ThumbnailID
+	}
+	return resp
 }
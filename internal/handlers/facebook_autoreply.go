@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// facebookFeedValue is the value of a Page "feed" change. Only comment
+// additions are handled; posts, reactions and edits are ignored.
+type facebookFeedValue struct {
+	Item      string `json:"item"` // "comment", "post", "reaction", ...
+	Verb      string `json:"verb"` // "add", "edited", "remove", ...
+	CommentID string `json:"comment_id"`
+	PostID    string `json:"post_id"`
+	ParentID  string `json:"parent_id"` // equals post_id for top-level comments
+	Message   string `json:"message"`
+	From      struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"from"`
+	CreatedTime int64 `json:"created_time"`
+}
+
+// processFacebookPayload dispatches the items of a Page webhook payload:
+// feed comments and Messenger messages. Each item is processed at most once
+// unless force is set.
+func processFacebookPayload(payload webhookPayload, force bool) error {
+	var errs []error
+	for _, entry := range payload.Entry {
+		pageID := entry.ID
+
+		for _, change := range entry.Changes {
+			if change.Field != "feed" {
+				continue
+			}
+			var value facebookFeedValue
+			if err := json.Unmarshal(change.Value, &value); err != nil {
+				slog.Error("webhook_fb: parse feed change", "error", err)
+				continue
+			}
+			// Ignore the page's own comments (including our auto-replies)
+			if value.Item != "comment" || value.Verb != "add" || value.CommentID == "" || value.From.ID == pageID {
+				continue
+			}
+			errs = append(errs, processWebhookItem("fb_comment:"+value.CommentID, force, func() error {
+				return processFacebookComment(pageID, value)
+			}))
+		}
+
+		for _, msg := range entry.Messaging {
+			// Ignore echoes of messages sent by the page itself
+			if msg.Message == nil || msg.Message.IsEcho || msg.Sender.ID == pageID || msg.Message.Text == "" {
+				continue
+			}
+			errs = append(errs, processWebhookItem("fb_mid:"+msg.Message.MID, force, func() error {
+				return processMessengerMessage(pageID, msg)
+			}))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// processFacebookComment handles a new comment on a Page post. Returned
+// errors are transient (database lookups) and make the event eligible for retry.
+func processFacebookComment(pageID string, comment facebookFeedValue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	slog.Info("webhook_fb_comment: received", "page_id", pageID, "from", comment.From.Name, "text", comment.Message, "post_id", comment.PostID)
+
+	creds, err := resolveFacebookCredsByPageID(ctx, pageID)
+	if err != nil || creds == nil {
+		slog.Warn("webhook_fb_comment: no credentials for page", "page_id", pageID, "error", err)
+		BroadcastWebhookEvent(WebhookSSEEvent{
+			Type: "comment", Channel: "facebook", Sender: comment.From.Name,
+			TriggerText: comment.Message, Status: "failed",
+			Response:  "Erro: credenciais do Facebook não encontradas para a página " + pageID,
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return err
+	}
+
+	// Every comment lands in the moderation inbox, matched or not
+	parentID := comment.ParentID
+	if parentID == comment.PostID {
+		parentID = ""
+	}
+	inboxComment := models.InboxComment{
+		OrgID:          creds.OrgID,
+		Platform:       "facebook",
+		AccountID:      pageID,
+		CommentID:      comment.CommentID,
+		ParentID:       parentID,
+		MediaID:        comment.PostID,
+		Text:           comment.Message,
+		AuthorID:       comment.From.ID,
+		AuthorUsername: comment.From.Name,
+		Source:         "webhook",
+	}
+	if comment.CreatedTime > 0 {
+		inboxComment.CommentedAt = time.Unix(comment.CreatedTime, 0)
+	}
+	ingestInboxComment(ctx, inboxComment)
+
+	rules, err := findMatchingRules(ctx, comment.Message, "comment", "facebook", comment.PostID, creds.OrgID)
+	if err != nil {
+		slog.Error("webhook_fb_comment: find rules", "error", err)
+		return err
+	}
+
+	if len(rules) == 0 {
+		slog.Info("webhook_fb_comment: no matching rules", "text", comment.Message)
+		BroadcastWebhookEvent(WebhookSSEEvent{
+			Type: "comment", Channel: "facebook", Sender: comment.From.Name,
+			TriggerText: comment.Message, Status: "no_match",
+			Response:  "Nenhuma regra ativa corresponde a este comentário",
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return nil
+	}
+
+	for _, mr := range rules {
+		rule := mr.Rule
+		keyword := mr.Keyword
+
+		if hasCooldown(ctx, comment.From.ID, rule.ID) {
+			logAutoReply(ctx, rule, "facebook", "comment", comment.From.ID, comment.From.Name, comment.Message, rule.ResponseMessage, "", "skipped_cooldown", "", creds.OrgID)
+			BroadcastWebhookEvent(WebhookSSEEvent{
+				Type: "comment", Channel: "facebook", RuleName: rule.Name, Sender: comment.From.Name,
+				TriggerText: comment.Message, Response: rule.ResponseMessage,
+				Status: "skipped_cooldown", Timestamp: time.Now().Format(time.RFC3339),
+			})
+			continue
+		}
+
+		// 1) Public comment reply (if configured). Failure does NOT block the private reply.
+		commentReplySent := ""
+		if rule.CommentReply != "" {
+			replyMsg := replaceTemplateVars(rule.CommentReply, comment.From.Name, keyword)
+			if _, err := metaGraphPost("/"+comment.CommentID+"/comments", creds.Token, url.Values{"message": {replyMsg}}); err != nil {
+				slog.Error("webhook_fb_comment: public reply failed", "error", err, "comment_id", comment.CommentID, "rule", rule.Name)
+			} else {
+				commentReplySent = replyMsg
+				slog.Info("webhook_fb_comment: public reply sent", "comment_id", comment.CommentID, "rule", rule.Name)
+			}
+		}
+
+		// 2) Messenger private reply (recipient is the comment, the user never messaged the page)
+		dmMsg := replaceTemplateVars(rule.ResponseMessage, comment.From.Name, keyword)
+		if _, err := sendPrivateReply(pageID, creds.Token, comment.CommentID, dmMsg); err != nil {
+			slog.Error("webhook_fb_comment: private reply failed", "error", err, "sender", comment.From.ID, "rule", rule.Name)
+			logAutoReply(ctx, rule, "facebook", "comment", comment.From.ID, comment.From.Name, comment.Message, dmMsg, commentReplySent, "failed", err.Error(), creds.OrgID)
+			BroadcastWebhookEvent(WebhookSSEEvent{
+				Type: "comment", Channel: "facebook", RuleName: rule.Name, Sender: comment.From.Name,
+				TriggerText: comment.Message, Response: dmMsg, CommentReply: commentReplySent,
+				Status: "failed", Timestamp: time.Now().Format(time.RFC3339),
+			})
+			continue
+		}
+
+		slog.Info("webhook_fb_comment: private reply sent", "sender", comment.From.ID, "rule", rule.Name)
+		logAutoReply(ctx, rule, "facebook", "comment", comment.From.ID, comment.From.Name, comment.Message, dmMsg, commentReplySent, "sent", "", creds.OrgID)
+		BroadcastWebhookEvent(WebhookSSEEvent{
+			Type: "comment", Channel: "facebook", RuleName: rule.Name, Sender: comment.From.Name,
+			TriggerText: comment.Message, Response: dmMsg, CommentReply: commentReplySent,
+			Status: "sent", Timestamp: time.Now().Format(time.RFC3339),
+		})
+	}
+	return nil
+}
+
+// processMessengerMessage handles an inbound Messenger text message sent to
+// a Page. Returned errors are transient.
+func processMessengerMessage(pageID string, msg webhookMessaging) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	senderID := msg.Sender.ID
+	text := msg.Message.Text
+
+	slog.Info("webhook_fb_dm: received", "page_id", pageID, "sender", senderID, "text", text)
+
+	creds, err := resolveFacebookCredsByPageID(ctx, pageID)
+	if err != nil || creds == nil {
+		slog.Warn("webhook_fb_dm: no credentials for page", "page_id", pageID, "error", err)
+		BroadcastWebhookEvent(WebhookSSEEvent{
+			Type: "dm", Channel: "facebook", Sender: senderID,
+			TriggerText: text, Status: "failed",
+			Response:  "Erro: credenciais do Facebook não encontradas para a página " + pageID,
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return err
+	}
+
+	rules, err := findMatchingRules(ctx, text, "dm", "facebook", "", creds.OrgID)
+	if err != nil {
+		slog.Error("webhook_fb_dm: find rules", "error", err)
+		return err
+	}
+
+	if len(rules) == 0 {
+		slog.Info("webhook_fb_dm: no matching rules", "text", text)
+		BroadcastWebhookEvent(WebhookSSEEvent{
+			Type: "dm", Channel: "facebook", Sender: senderID,
+			TriggerText: text, Status: "no_match",
+			Response:  "Nenhuma regra ativa corresponde a esta mensagem",
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return nil
+	}
+
+	for _, mr := range rules {
+		rule := mr.Rule
+		keyword := mr.Keyword
+
+		if hasCooldown(ctx, senderID, rule.ID) {
+			logAutoReply(ctx, rule, "facebook", "dm", senderID, "", text, rule.ResponseMessage, "", "skipped_cooldown", "", creds.OrgID)
+			BroadcastWebhookEvent(WebhookSSEEvent{
+				Type: "dm", Channel: "facebook", RuleName: rule.Name, Sender: senderID,
+				TriggerText: text, Response: rule.ResponseMessage,
+				Status: "skipped_cooldown", Timestamp: time.Now().Format(time.RFC3339),
+			})
+			continue
+		}
+
+		dmMsg := replaceTemplateVars(rule.ResponseMessage, "", keyword)
+		if err := sendMessengerMessage(pageID, creds.Token, senderID, dmMsg); err != nil {
+			slog.Error("webhook_fb_dm: send failed", "error", err, "sender", senderID, "rule", rule.Name)
+			logAutoReply(ctx, rule, "facebook", "dm", senderID, "", text, dmMsg, "", "failed", err.Error(), creds.OrgID)
+			BroadcastWebhookEvent(WebhookSSEEvent{
+				Type: "dm", Channel: "facebook", RuleName: rule.Name, Sender: senderID,
+				TriggerText: text, Response: dmMsg,
+				Status: "failed", Timestamp: time.Now().Format(time.RFC3339),
+			})
+			continue
+		}
+
+		slog.Info("webhook_fb_dm: sent", "sender", senderID, "rule", rule.Name)
+		logAutoReply(ctx, rule, "facebook", "dm", senderID, "", text, dmMsg, "", "sent", "", creds.OrgID)
+		BroadcastWebhookEvent(WebhookSSEEvent{
+			Type: "dm", Channel: "facebook", RuleName: rule.Name, Sender: senderID,
+			TriggerText: text, Response: dmMsg,
+			Status: "sent", Timestamp: time.Now().Format(time.RFC3339),
+		})
+	}
+	return nil
+}
+
+// resolveFacebookCredsByPageID finds the org's Facebook credentials for a page.
+// Returns nil, nil when no org has connected the page.
+func resolveFacebookCredsByPageID(ctx context.Context, pageID string) (*facebookCredentials, error) {
+	if !crypto.Available() {
+		return nil, nil
+	}
+
+	var cfg models.FacebookConfig
+	err := database.FacebookConfigs().FindOne(ctx, bson.M{"page_id": pageID}).Decode(&cfg)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db error: %w", err)
+	}
+
+	token, err := crypto.Decrypt(cfg.PageAccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt token: %w", err)
+	}
+	return &facebookCredentials{
+		PageID:   cfg.PageID,
+		Token:    token,
+		PageName: cfg.PageName,
+		Source:   "user",
+		OrgID:    cfg.OrgID,
+	}, nil
+}
+
+// sendMessengerMessage replies to a user who messaged the Page. The
+// RESPONSE messaging type is only valid inside the 24h messaging window,
+// which an inbound message always opens.
+func sendMessengerMessage(pageID, token, recipientID, message string) error {
+	apiURL := fmt.Sprintf("https://graph.facebook.com/v21.0/%s/messages", pageID)
+
+	payload := map[string]interface{}{
+		"recipient":      map[string]string{"id": recipientID},
+		"messaging_type": "RESPONSE",
+		"message":        map[string]string{"text": message},
+	}
+
+	body, _ := json.Marshal(payload)
+	req, err := http.NewRequest("POST", apiURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("messenger API error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// subscribeFacebookPageWebhooks subscribes the app to the page's feed and
+// messages events so comment and Messenger auto-replies receive webhooks.
+// Failures are logged only; the page connection itself still succeeds.
+func subscribeFacebookPageWebhooks(pageID, token string) {
+	params := url.Values{"subscribed_fields": {"feed,messages"}}
+	if _, err := metaGraphPost("/"+pageID+"/subscribed_apps", token, params); err != nil {
+		slog.Warn("facebook_webhook_subscribe_failed", "page_id", maskAccountID(pageID), "error", err)
+		return
+	}
+	slog.Info("facebook_webhook_subscribed", "page_id", maskAccountID(pageID))
+}
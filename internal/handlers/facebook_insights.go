@@ -0,0 +1,467 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// pageInsightMetrics are the daily page-level insights stored in each snapshot.
+var pageInsightMetrics = []string{"page_impressions", "page_impressions_unique", "page_engaged_users", "page_post_engagements", "page_views_total"}
+
+// postInsightMetrics are the lifetime post-level insights stored in each snapshot.
+var postInsightMetrics = []string{"post_impressions", "post_impressions_unique", "post_reactions_by_type_total", "post_clicks"}
+
+const (
+	// fbPostInsightWindow is how long after publishing a post's insights are captured.
+	fbPostInsightWindow = 28 * 24 * time.Hour
+
+	// fbInsightsMaxRange caps the report's date range, in days.
+	fbInsightsMaxRange = 366
+)
+
+// GetFacebookInsightsReport returns page and post insights over a date range from the daily snapshots.
+// @Summary Obter insights da Página do Facebook
+// @Description Retorna fãs, alcance, impressões, usuários engajados e visualizações da página no período (com comparação ao período anterior e série diária), e os insights dos posts publicados pelos agendamentos
+// @Tags facebook
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Início (YYYY-MM-DD, padrão: 30 dias atrás)"
+// @Param to query string false "Fim, inclusivo (YYYY-MM-DD, padrão: ontem)"
+// @Success 200 {object} models.FacebookInsightsReport
+// @Failure 400 {string} string "Invalid date range"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error loading snapshots"
+// @Router /admin/facebook/analytics [get]
+func GetFacebookInsightsReport(w http.ResponseWriter, r *http.Request) {
+	_, creds, ok := requireFacebookCreds(w, r)
+	if !ok {
+		return
+	}
+	orgID := middleware.GetOrgID(r)
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			http.Error(w, "to must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -29)
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			http.Error(w, "from must be YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		from = t
+	}
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days < 1 || days > fbInsightsMaxRange {
+		http.Error(w, "Invalid date range (from must be before to, max "+strconv.Itoa(fbInsightsMaxRange)+" days)", http.StatusBadRequest)
+		return
+	}
+	fromStr, toStr := from.Format("2006-01-02"), to.Format("2006-01-02")
+	previousFrom := from.AddDate(0, 0, -days).Format("2006-01-02")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := database.FBPageSnapshots().Find(ctx, bson.M{
+		"org_id":  orgID,
+		"page_id": creds.PageID,
+		"date":    bson.M{"$gte": previousFrom, "$lte": toStr},
+	}, opts)
+	if err != nil {
+		http.Error(w, "Error loading snapshots", http.StatusInternalServerError)
+		return
+	}
+	var snapshots []models.FBPageSnapshot
+	cursor.All(ctx, &snapshots)
+	cursor.Close(ctx)
+
+	var previous, current []models.FBPageSnapshot
+	for _, s := range snapshots {
+		if s.Date >= fromStr {
+			current = append(current, s)
+		} else {
+			previous = append(previous, s)
+		}
+	}
+	if current == nil {
+		current = []models.FBPageSnapshot{}
+	}
+
+	sum := func(list []models.FBPageSnapshot, field func(models.FBPageSnapshot) int64) int64 {
+		var total int64
+		for _, s := range list {
+			total += field(s)
+		}
+		return total
+	}
+	// growth is the change of a counter across a period, measured from the end of the previous one when available
+	growth := func(list, before []models.FBPageSnapshot, field func(models.FBPageSnapshot) int64) int64 {
+		if len(list) == 0 {
+			return 0
+		}
+		base := list[0]
+		if len(before) > 0 {
+			base = before[len(before)-1]
+		}
+		return field(list[len(list)-1]) - field(base)
+	}
+	delta := func(field func(models.FBPageSnapshot) int64) models.MetricDelta {
+		return newMetricDelta(sum(current, field), sum(previous, field))
+	}
+
+	fans := func(s models.FBPageSnapshot) int64 { return s.Fans }
+	report := models.FacebookInsightsReport{
+		PageID:          creds.PageID,
+		PageName:        creds.PageName,
+		From:            fromStr,
+		To:              toStr,
+		FansGained:      newMetricDelta(growth(current, previous, fans), growth(previous, nil, fans)),
+		Reach:           delta(func(s models.FBPageSnapshot) int64 { return s.Reach }),
+		Impressions:     delta(func(s models.FBPageSnapshot) int64 { return s.Impressions }),
+		EngagedUsers:    delta(func(s models.FBPageSnapshot) int64 { return s.EngagedUsers }),
+		PostEngagements: delta(func(s models.FBPageSnapshot) int64 { return s.PostEngagements }),
+		PageViews:       delta(func(s models.FBPageSnapshot) int64 { return s.PageViews }),
+		Series:          current,
+		Posts:           loadFacebookPostInsights(ctx, orgID, creds.PageID, from, to.AddDate(0, 0, 1)),
+	}
+	if len(current) > 0 {
+		report.Fans = current[len(current)-1].Fans
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+// loadFacebookPostInsights returns the latest snapshot of every post published in [from, until),
+// with the message and media type of its schedule.
+func loadFacebookPostInsights(ctx context.Context, orgID primitive.ObjectID, pageID string, from, until time.Time) []models.FBPostInsightSummary {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"org_id":       orgID,
+			"page_id":      pageID,
+			"published_at": bson.M{"$gte": from, "$lt": until},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "date", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$post_id", "doc": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$doc"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "published_at", Value: -1}}}},
+		{{Key: "$limit", Value: 200}},
+	}
+	posts := []models.FBPostInsightSummary{}
+	cursor, err := database.FBPostInsights().Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("facebook_insights_posts", "error", err)
+		return posts
+	}
+	cursor.All(ctx, &posts)
+	cursor.Close(ctx)
+	if len(posts) == 0 {
+		return posts
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ScheduleID)
+	}
+	var schedules []models.FacebookSchedule
+	cursor, err = database.FacebookSchedules().Find(ctx,
+		bson.M{"_id": bson.M{"$in": ids}, "org_id": orgID},
+		options.Find().SetProjection(bson.M{"message": 1, "media_type": 1}),
+	)
+	if err == nil {
+		cursor.All(ctx, &schedules)
+		cursor.Close(ctx)
+	}
+	byID := make(map[primitive.ObjectID]models.FacebookSchedule, len(schedules))
+	for _, s := range schedules {
+		byID[s.ID] = s
+	}
+	for i := range posts {
+		if s, ok := byID[posts[i].ScheduleID]; ok {
+			posts[i].Message = s.Message
+			posts[i].MediaType = s.MediaType
+		}
+	}
+	return posts
+}
+
+// ─── Snapshot job ────────────────────────────────────────────────────
+
+// CaptureFacebookInsights stores yesterday's snapshot for every connected Facebook Page
+// and today's snapshot of the posts its schedules published in the last 28 days.
+// Snapshots already captured are skipped, so the job can run several times a day.
+func CaptureFacebookInsights() {
+	if !crypto.Available() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	date := yesterday.Format("2006-01-02")
+
+	cursor, err := database.FacebookConfigs().Find(ctx, bson.M{})
+	if err != nil {
+		slog.Error("facebook_insights_configs", "error", err)
+		return
+	}
+	var cfgs []models.FacebookConfig
+	cursor.All(ctx, &cfgs)
+	cursor.Close(ctx)
+
+	pages, posts := 0, 0
+	for _, cfg := range cfgs {
+		token, err := crypto.Decrypt(cfg.PageAccessToken)
+		if err != nil {
+			slog.Warn("facebook_insights_decrypt", "page", maskAccountID(cfg.PageID), "error", err)
+			continue
+		}
+
+		exists, _ := database.FBPageSnapshots().CountDocuments(ctx, bson.M{"org_id": cfg.OrgID, "page_id": cfg.PageID, "date": date})
+		if exists == 0 {
+			snap, err := fetchPageSnapshot(cfg.PageID, token, yesterday, today)
+			if err != nil {
+				slog.Warn("facebook_insights_page_fetch", "page", maskAccountID(cfg.PageID), "error", err)
+			} else {
+				snap.OrgID = cfg.OrgID
+				snap.PageID = cfg.PageID
+				snap.Date = date
+				snap.CapturedAt = time.Now()
+				_, err = database.FBPageSnapshots().UpdateOne(ctx,
+					bson.M{"org_id": cfg.OrgID, "page_id": cfg.PageID, "date": date},
+					bson.M{"$setOnInsert": snap},
+					options.Update().SetUpsert(true),
+				)
+				if err != nil {
+					slog.Error("facebook_insights_page_upsert", "page", maskAccountID(cfg.PageID), "error", err)
+				} else {
+					pages++
+				}
+			}
+		}
+
+		posts += captureFacebookPostInsights(ctx, cfg, token, today)
+	}
+
+	if pages > 0 || posts > 0 {
+		slog.Info("facebook_insights_cycle_complete", "date", date, "pages", pages, "posts", posts)
+	}
+}
+
+// captureFacebookPostInsights snapshots the org's recently published schedules
+// that have no snapshot for today yet. Returns the number of snapshots stored.
+func captureFacebookPostInsights(ctx context.Context, cfg models.FacebookConfig, token string, today time.Time) int {
+	since := time.Now().Add(-fbPostInsightWindow)
+	cursor, err := database.FacebookSchedules().Find(ctx, bson.M{
+		"org_id":     cfg.OrgID,
+		"status":     "published",
+		"fb_post_id": bson.M{"$nin": []interface{}{nil, ""}},
+		"$or": []bson.M{
+			{"published_at": bson.M{"$gte": since}},
+			{"published_at": bson.M{"$exists": false}, "updated_at": bson.M{"$gte": since}},
+		},
+	})
+	if err != nil {
+		slog.Error("facebook_insights_schedules", "error", err)
+		return 0
+	}
+	var schedules []models.FacebookSchedule
+	cursor.All(ctx, &schedules)
+	cursor.Close(ctx)
+
+	date := today.Format("2006-01-02")
+	count := 0
+	for _, s := range schedules {
+		exists, _ := database.FBPostInsights().CountDocuments(ctx, bson.M{"org_id": s.OrgID, "post_id": s.FBPostID, "date": date})
+		if exists > 0 {
+			continue
+		}
+
+		snap, err := fetchPostInsightSnapshot(s.FBPostID, token)
+		if err != nil {
+			slog.Warn("facebook_insights_post_fetch", "post_id", s.FBPostID, "error", err)
+			continue
+		}
+		snap.OrgID = s.OrgID
+		snap.PageID = cfg.PageID
+		snap.ScheduleID = s.ID
+		snap.PostID = s.FBPostID
+		snap.Date = date
+		snap.PublishedAt = s.UpdatedAt
+		if s.PublishedAt != nil {
+			snap.PublishedAt = *s.PublishedAt
+		}
+		snap.CapturedAt = time.Now()
+
+		if _, err := database.FBPostInsights().InsertOne(ctx, snap); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				slog.Error("facebook_insights_post_insert", "post_id", s.FBPostID, "error", err)
+			}
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// fetchPageSnapshot reads the page counters and its insights for [since, until).
+// If the combined insights request is rejected (a metric was deprecated), metrics
+// are requested one by one and unsupported ones are left at zero.
+func fetchPageSnapshot(pageID, token string, since, until time.Time) (models.FBPageSnapshot, error) {
+	var snap models.FBPageSnapshot
+
+	fieldParams := url.Values{}
+	fieldParams.Set("fields", "name,fan_count,followers_count")
+	page, err := metaGraphGet("/"+pageID, token, fieldParams)
+	if err != nil {
+		return snap, err
+	}
+	snap.PageName, _ = page["name"].(string)
+	fans, _ := page["fan_count"].(float64)
+	followers, _ := page["followers_count"].(float64)
+	snap.Fans = int64(fans)
+	snap.Followers = int64(followers)
+
+	values, err := fetchPageInsightMetrics(pageID, token, pageInsightMetrics, since, until)
+	if err != nil {
+		values = map[string]int64{}
+		for _, m := range pageInsightMetrics {
+			single, err := fetchPageInsightMetrics(pageID, token, []string{m}, since, until)
+			if err != nil {
+				continue
+			}
+			values[m] = single[m]
+		}
+	}
+
+	snap.Impressions = values["page_impressions"]
+	snap.Reach = values["page_impressions_unique"]
+	snap.EngagedUsers = values["page_engaged_users"]
+	snap.PostEngagements = values["page_post_engagements"]
+	snap.PageViews = values["page_views_total"]
+	return snap, nil
+}
+
+// fetchPageInsightMetrics calls /{page}/insights with a daily period and returns metric name -> value.
+func fetchPageInsightMetrics(pageID, token string, metrics []string, since, until time.Time) (map[string]int64, error) {
+	params := url.Values{}
+	params.Set("metric", strings.Join(metrics, ","))
+	params.Set("period", "day")
+	params.Set("since", strconv.FormatInt(since.Unix(), 10))
+	params.Set("until", strconv.FormatInt(until.Unix(), 10))
+	result, err := metaGraphGet("/"+pageID+"/insights", token, params)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]int64{}
+	data, _ := result["data"].([]interface{})
+	for _, item := range data {
+		metric, _ := item.(map[string]interface{})
+		name, _ := metric["name"].(string)
+		vals, _ := metric["values"].([]interface{})
+		for _, val := range vals {
+			entry, _ := val.(map[string]interface{})
+			v, _ := entry["value"].(float64)
+			values[name] += int64(v)
+		}
+	}
+	return values, nil
+}
+
+// fetchPostInsightSnapshot reads a post's share and comment counters and its lifetime insights.
+func fetchPostInsightSnapshot(postID, token string) (models.FBPostInsightSnapshot, error) {
+	snap := models.FBPostInsightSnapshot{Reactions: map[string]int64{}}
+
+	fieldParams := url.Values{}
+	fieldParams.Set("fields", "shares,comments.limit(0).summary(true)")
+	post, err := metaGraphGet("/"+postID, token, fieldParams)
+	if err != nil {
+		return snap, err
+	}
+	if shares, ok := post["shares"].(map[string]interface{}); ok {
+		count, _ := shares["count"].(float64)
+		snap.Shares = int64(count)
+	}
+	if comments, ok := post["comments"].(map[string]interface{}); ok {
+		summary, _ := comments["summary"].(map[string]interface{})
+		total, _ := summary["total_count"].(float64)
+		snap.Comments = int64(total)
+	}
+
+	values, err := fetchPostInsightMetrics(postID, token, postInsightMetrics)
+	if err != nil {
+		values = map[string]interface{}{}
+		for _, m := range postInsightMetrics {
+			single, err := fetchPostInsightMetrics(postID, token, []string{m})
+			if err != nil {
+				continue
+			}
+			values[m] = single[m]
+		}
+	}
+
+	number := func(name string) int64 {
+		v, _ := values[name].(float64)
+		return int64(v)
+	}
+	snap.Impressions = number("post_impressions")
+	snap.Reach = number("post_impressions_unique")
+	snap.Clicks = number("post_clicks")
+	if byType, ok := values["post_reactions_by_type_total"].(map[string]interface{}); ok {
+		for reaction, v := range byType {
+			n, _ := v.(float64)
+			snap.Reactions[reaction] = int64(n)
+			snap.TotalReactions += int64(n)
+		}
+	}
+	return snap, nil
+}
+
+// fetchPostInsightMetrics calls /{post}/insights and returns metric name -> lifetime value.
+// Values are numbers, except post_reactions_by_type_total which maps reaction type -> count.
+func fetchPostInsightMetrics(postID, token string, metrics []string) (map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("metric", strings.Join(metrics, ","))
+	params.Set("period", "lifetime")
+	result, err := metaGraphGet("/"+postID+"/insights", token, params)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	data, _ := result["data"].([]interface{})
+	for _, item := range data {
+		metric, _ := item.(map[string]interface{})
+		name, _ := metric["name"].(string)
+		vals, _ := metric["values"].([]interface{})
+		if len(vals) > 0 {
+			first, _ := vals[0].(map[string]interface{})
+			values[name] = first["value"]
+		}
+	}
+	return values, nil
+}
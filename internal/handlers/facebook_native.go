@@ -0,0 +1,358 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// Facebook accepts scheduled_publish_time between 10 minutes and 30 days
+	// ahead; the minimum keeps a margin for the hand-off request itself.
+	facebookNativeMinLead = 12 * time.Minute
+	facebookNativeMaxLead = 30 * 24 * time.Hour
+
+	// facebookNativeGrace is how long after the scheduled time Facebook has to
+	// publish before the post is taken back and published by the local job.
+	facebookNativeGrace = 15 * time.Minute
+)
+
+// supportsNativeFacebookSchedule reports whether Facebook can schedule this
+// media type natively. Videos and Reels are uploaded at publish time, so they
+// stay on the local job.
+func supportsNativeFacebookSchedule(mediaType string) bool {
+	switch mediaType {
+	case "text", "link", "image", "carousel":
+		return true
+	}
+	return false
+}
+
+// nativeFacebookEligible reports whether s should currently be held by
+// Facebook's native scheduler.
+func nativeFacebookEligible(s models.FacebookSchedule) bool {
+	if !s.Native || s.Status != "scheduled" || !supportsNativeFacebookSchedule(s.MediaType) {
+		return false
+	}
+	lead := time.Until(s.ScheduledAt)
+	return lead >= facebookNativeMinLead && lead <= facebookNativeMaxLead
+}
+
+// scheduleNativeFacebookPost creates s on the page as an unpublished post with
+// scheduled_publish_time and returns its post ID.
+func scheduleNativeFacebookPost(creds *facebookCredentials, s models.FacebookSchedule) (string, error) {
+	params := url.Values{}
+	params.Set("published", "false")
+	params.Set("scheduled_publish_time", strconv.FormatInt(s.ScheduledAt.Unix(), 10))
+	if s.Message != "" {
+		params.Set("message", s.Message)
+	}
+
+	endpoint := "/" + creds.PageID + "/feed"
+	switch s.MediaType {
+	case "text":
+	case "link":
+		params.Set("link", s.LinkURL)
+	case "image":
+		endpoint = "/" + creds.PageID + "/photos"
+		params.Set("url", getPublicImageURL(s.ImageIDs[0]))
+	case "carousel":
+		for i, imgID := range s.ImageIDs {
+			photo, err := metaGraphPost("/"+creds.PageID+"/photos", creds.Token, url.Values{
+				"url":       {getPublicImageURL(imgID)},
+				"published": {"false"},
+				"temporary": {"true"},
+			})
+			if err != nil {
+				return "", fmt.Errorf("upload photo failed: %w", err)
+			}
+			params.Set(fmt.Sprintf("attached_media[%d]", i), fmt.Sprintf(`{"media_fbid":"%v"}`, photo["id"]))
+		}
+	default:
+		return "", fmt.Errorf("native scheduling not supported for %s", s.MediaType)
+	}
+
+	result, err := metaGraphPost(endpoint, creds.Token, params)
+	if err != nil {
+		return "", err
+	}
+	// Photo uploads return the photo ID plus the page post ID
+	if postID, _ := result["post_id"].(string); postID != "" {
+		return postID, nil
+	}
+	postID, _ := result["id"].(string)
+	if postID == "" {
+		return "", fmt.Errorf("facebook returned no post ID")
+	}
+	return postID, nil
+}
+
+// sameNativeContent reports whether only the message or time changed between
+// prev and s, which Facebook can update in place on the scheduled post.
+func sameNativeContent(prev, s models.FacebookSchedule) bool {
+	if prev.MediaType != s.MediaType || prev.LinkURL != s.LinkURL || len(prev.ImageIDs) != len(s.ImageIDs) {
+		return false
+	}
+	for i := range prev.ImageIDs {
+		if prev.ImageIDs[i] != s.ImageIDs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cancelNativeFacebookPost deletes the unpublished scheduled post from the page.
+// Published posts are left on the page.
+func cancelNativeFacebookPost(ctx context.Context, s models.FacebookSchedule) error {
+	if s.NativePostID == "" || s.Status == "published" {
+		return nil
+	}
+	creds, err := getFacebookCredentials(ctx, s.UserID, s.OrgID)
+	if err != nil || creds == nil {
+		return fmt.Errorf("facebook not configured")
+	}
+	_, err = metaGraphDelete("/"+s.NativePostID, creds.Token)
+	return err
+}
+
+// cancelNativeFacebookPosts cancels the posts Facebook holds among the
+// schedules matching filter, ahead of a bulk delete.
+func cancelNativeFacebookPosts(ctx context.Context, filter bson.M) {
+	held := bson.M{"native_post_id": bson.M{"$nin": []interface{}{nil, ""}}}
+	for k, v := range filter {
+		held[k] = v
+	}
+	cursor, err := database.FacebookSchedules().Find(ctx, held)
+	if err != nil {
+		return
+	}
+	var schedules []models.FacebookSchedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return
+	}
+	for _, s := range schedules {
+		if err := cancelNativeFacebookPost(ctx, s); err != nil {
+			slog.Warn("facebook_native_cancel_error", "schedule_id", s.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// syncNativeFacebookSchedule brings Facebook's copy of s in line with its
+// stored state after a create or edit: it hands the post off, updates it in
+// place, or cancels it. prev is the schedule before the change (zero on
+// create). A failed hand-off is recorded in native_error and leaves the post to
+// the local job; the returned error means Facebook still holds an outdated post.
+func syncNativeFacebookSchedule(ctx context.Context, prev, s models.FacebookSchedule) (models.FacebookSchedule, error) {
+	s.NativePostID, s.NativeError = prev.NativePostID, ""
+	eligible := nativeFacebookEligible(s)
+
+	var syncErr error
+	if s.NativePostID != "" || eligible {
+		creds, err := getFacebookCredentials(ctx, s.UserID, s.OrgID)
+		if err == nil && creds == nil {
+			err = fmt.Errorf("facebook not configured")
+		}
+		switch {
+		case err != nil && s.NativePostID != "":
+			syncErr = err
+		case err != nil:
+			s.NativeError = err.Error()
+		default:
+			syncErr = reconcileNativeFacebookPost(creds, prev, &s, eligible)
+		}
+	}
+	if syncErr != nil {
+		s.NativeError = syncErr.Error()
+	}
+	if s.NativePostID == prev.NativePostID && s.NativeError == prev.NativeError {
+		return s, syncErr
+	}
+
+	// Graph calls can outlast the request context; persist with a fresh one.
+	// The write only lands if no concurrent sync replaced the post meanwhile.
+	pctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var prevPostID interface{} = prev.NativePostID
+	if prev.NativePostID == "" {
+		prevPostID = bson.M{"$in": []interface{}{nil, ""}}
+	}
+	res, err := database.FacebookSchedules().UpdateOne(pctx, bson.M{"_id": s.ID, "native_post_id": prevPostID}, bson.M{
+		"$set": bson.M{"native_post_id": s.NativePostID, "native_error": s.NativeError},
+	})
+	if err != nil {
+		slog.Error("facebook_native_save_error", "schedule_id", s.ID.Hex(), "error", err)
+	} else if res.MatchedCount == 0 {
+		// Lost the race: drop the post created here so Facebook holds only one
+		if s.NativePostID != "" && s.NativePostID != prev.NativePostID {
+			if creds, err := getFacebookCredentials(pctx, s.UserID, s.OrgID); err == nil && creds != nil {
+				if _, err := metaGraphDelete("/"+s.NativePostID, creds.Token); err != nil {
+					slog.Warn("facebook_native_cancel_error", "schedule_id", s.ID.Hex(), "error", err)
+				}
+			}
+		}
+		slog.Warn("facebook_native_sync_conflict", "schedule_id", s.ID.Hex())
+		var current models.FacebookSchedule
+		if err := database.FacebookSchedules().FindOne(pctx, bson.M{"_id": s.ID}).Decode(&current); err == nil {
+			return current, nil
+		}
+		return s, syncErr
+	}
+
+	if s.NativeError != "" {
+		slog.Warn("facebook_native_sync_error", "schedule_id", s.ID.Hex(), "error", s.NativeError)
+	} else if s.NativePostID != "" {
+		slog.Info("facebook_native_scheduled", "schedule_id", s.ID.Hex(), "native_post_id", s.NativePostID)
+	}
+	return s, syncErr
+}
+
+// reconcileNativeFacebookPost updates or cancels the post Facebook holds for s
+// and hands s off again when it is still eligible. Only a failed cancellation
+// is returned; a failed hand-off is recorded on s.
+func reconcileNativeFacebookPost(creds *facebookCredentials, prev models.FacebookSchedule, s *models.FacebookSchedule, eligible bool) error {
+	if s.NativePostID != "" {
+		if eligible && sameNativeContent(prev, *s) {
+			_, err := metaGraphPost("/"+s.NativePostID, creds.Token, url.Values{
+				"message":                {s.Message},
+				"scheduled_publish_time": {strconv.FormatInt(s.ScheduledAt.Unix(), 10)},
+			})
+			if err == nil {
+				return nil
+			}
+		}
+		if _, err := metaGraphDelete("/"+s.NativePostID, creds.Token); err != nil {
+			return fmt.Errorf("cancel scheduled post: %w", err)
+		}
+		s.NativePostID = ""
+	}
+
+	if eligible {
+		postID, err := scheduleNativeFacebookPost(creds, *s)
+		if err != nil {
+			s.NativeError = err.Error()
+			return nil
+		}
+		s.NativePostID = postID
+	}
+	return nil
+}
+
+// SyncNativeFacebookSchedules hands native schedules to Facebook once they are
+// approved and inside Facebook's scheduling window, and records the outcome of
+// posts whose time has passed. Posts Facebook did not publish within the grace
+// period are cancelled there and left to ProcessScheduledFacebookPosts.
+func SyncNativeFacebookSchedules() {
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	empty := bson.M{"$in": []interface{}{nil, ""}}
+
+	// Hand-offs: approved posts that entered the window or were created outside it
+	cursor, err := database.FacebookSchedules().Find(ctx, bson.M{
+		"status":         "scheduled",
+		"native":         true,
+		"media_type":     bson.M{"$in": []string{"text", "link", "image", "carousel"}},
+		"native_post_id": empty,
+		"native_error":   empty,
+		"scheduled_at":   bson.M{"$gte": now.Add(facebookNativeMinLead), "$lte": now.Add(facebookNativeMaxLead)},
+	})
+	if err != nil {
+		slog.Error("facebook_native_query_error", "error", err)
+		return
+	}
+	var pending []models.FacebookSchedule
+	if err := cursor.All(ctx, &pending); err != nil {
+		slog.Error("facebook_native_decode_error", "error", err)
+		return
+	}
+	for _, s := range pending {
+		syncNativeFacebookSchedule(ctx, s, s)
+	}
+
+	// Outcomes: posts held by Facebook whose time has passed
+	cursor, err = database.FacebookSchedules().Find(ctx, bson.M{
+		"status":         "scheduled",
+		"native_post_id": bson.M{"$nin": []interface{}{nil, ""}},
+		"scheduled_at":   bson.M{"$lte": now},
+	})
+	if err != nil {
+		slog.Error("facebook_native_query_error", "error", err)
+		return
+	}
+	var due []models.FacebookSchedule
+	if err := cursor.All(ctx, &due); err != nil {
+		slog.Error("facebook_native_decode_error", "error", err)
+		return
+	}
+
+	for _, s := range due {
+		creds, err := getFacebookCredentials(ctx, s.UserID, s.OrgID)
+		if err != nil || creds == nil {
+			slog.Warn("facebook_native_no_credentials", "schedule_id", s.ID.Hex(), "error", err)
+			continue
+		}
+		overdue := now.After(s.ScheduledAt.Add(facebookNativeGrace))
+
+		status, err := facebookPublisher{creds: creds}.FetchStatus(ctx, facebookPublishContent(s), s.NativePostID)
+		switch {
+		case err == nil && status.State == "published":
+			database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": s.ID}, bson.M{
+				"$set": bson.M{
+					"status":       "published",
+					"fb_post_id":   s.NativePostID,
+					"published_at": s.ScheduledAt,
+					"updated_at":   time.Now(),
+				},
+			})
+			slog.Info("facebook_native_published", "schedule_id", s.ID.Hex(), "fb_post_id", s.NativePostID)
+		case !overdue:
+			if err != nil {
+				slog.Warn("facebook_native_status_error", "schedule_id", s.ID.Hex(), "error", err)
+			}
+		case err != nil && !isFacebookNotFoundError(err):
+			// Transient failure: retry on the next run rather than failing the post
+			slog.Warn("facebook_native_status_error", "schedule_id", s.ID.Hex(), "error", err)
+		case err != nil:
+			// The post is gone from the page (deleted there)
+			database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": s.ID}, bson.M{
+				"$set": bson.M{
+					"status":         "failed",
+					"native_post_id": "",
+					"error_message":  "Scheduled post not found on Facebook: " + err.Error(),
+					"updated_at":     time.Now(),
+				},
+			})
+			slog.Warn("facebook_native_missing", "schedule_id", s.ID.Hex(), "error", err)
+		default:
+			// Take the post back so the local job publishes it exactly once
+			if _, err := metaGraphDelete("/"+s.NativePostID, creds.Token); err != nil {
+				slog.Warn("facebook_native_cancel_error", "schedule_id", s.ID.Hex(), "error", err)
+				continue
+			}
+			database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": s.ID}, bson.M{
+				"$set": bson.M{
+					"native_post_id": "",
+					"native_error":   "Facebook did not publish at the scheduled time; publishing locally",
+					"updated_at":     time.Now(),
+				},
+			})
+			slog.Warn("facebook_native_fallback", "schedule_id", s.ID.Hex())
+		}
+	}
+}
+
+// isFacebookNotFoundError reports whether a Graph API error says the object
+// no longer exists (code 100, subcode 33), as opposed to a transient failure.
+func isFacebookNotFoundError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "error_subcode:33") || strings.Contains(msg, "does not exist")
+}
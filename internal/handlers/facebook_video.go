@@ -0,0 +1,604 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	facebookVideoMaxSize = 1 << 30 // 1GB
+
+	// facebookVideoUploadTimeout bounds the transfer of one video to the page.
+	facebookVideoUploadTimeout = 10 * time.Minute
+
+	// facebookVideoProcessingTimeout fails posts Facebook never finishes processing.
+	facebookVideoProcessingTimeout = 2 * time.Hour
+
+	// Video uploads go to the graph-video host; Reels bytes go to rupload.
+	facebookVideoAPIBase  = "https://graph-video.facebook.com/v21.0"
+	facebookReelUploadURL = "https://rupload.facebook.com/video-upload/v21.0/"
+)
+
+// facebookVideoTypes are the media types published as a video.
+var facebookVideoTypes = []string{"video", "reel"}
+
+// isFacebookVideoType reports whether the media type is published as a video.
+func isFacebookVideoType(mediaType string) bool {
+	return mediaType == "video" || mediaType == "reel"
+}
+
+// facebookVideoExists reports whether an uploaded video file exists in the org.
+func facebookVideoExists(ctx context.Context, orgID primitive.ObjectID, videoID string) bool {
+	oid, err := primitive.ObjectIDFromHex(videoID)
+	if err != nil {
+		return false
+	}
+	bucket, err := database.Videos()
+	if err != nil {
+		return false
+	}
+	count, err := bucket.GetFilesCollection().CountDocuments(ctx, bson.M{"_id": oid, "metadata.org_id": orgID})
+	return err == nil && count > 0
+}
+
+// checkFacebookVideoRefs checks that the video (uploaded by the org) and the
+// thumbnail exist, when set. Returns an error message, or "" when valid.
+func checkFacebookVideoRefs(ctx context.Context, orgID primitive.ObjectID, videoID, thumbnailID string) string {
+	if videoID != "" && !facebookVideoExists(ctx, orgID, videoID) {
+		return "Video not found: " + videoID
+	}
+	if thumbnailID != "" {
+		oid, err := primitive.ObjectIDFromHex(thumbnailID)
+		if err != nil {
+			return "Invalid thumbnail ID: " + thumbnailID
+		}
+		count, err := database.Images().CountDocuments(ctx, bson.M{"_id": oid})
+		if err != nil || count == 0 {
+			return "Thumbnail not found: " + thumbnailID
+		}
+	}
+	return ""
+}
+
+// UploadFacebookVideo stores a video for a Facebook video or Reel post
+// @Summary Upload de vídeo para Facebook
+// @Description Armazena um vídeo para posts de vídeo ou Reels do Facebook (máx 1GB). O arquivo é enviado à página na publicação.
+// @Tags facebook
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param video formData file true "Arquivo de vídeo (MP4 ou MOV)"
+// @Success 200 {object} models.FacebookVideoUploadResponse
+// @Failure 400 {string} string "No video provided"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 413 {string} string "Video too large"
+// @Failure 500 {string} string "Error saving video"
+// @Router /admin/facebook/upload/video [post]
+func UploadFacebookVideo(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+	if userID == primitive.NilObjectID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Stream the file part into GridFS instead of buffering it in memory
+	r.Body = http.MaxBytesReader(w, r.Body, facebookVideoMaxSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected multipart/form-data", http.StatusBadRequest)
+		return
+	}
+
+	var part *multipart.Part
+	for {
+		p, err := reader.NextPart()
+		if err != nil {
+			break
+		}
+		if p.FormName() == "video" {
+			part = p
+			break
+		}
+		p.Close()
+	}
+	if part == nil {
+		http.Error(w, "No video provided. Use field name 'video'", http.StatusBadRequest)
+		return
+	}
+	defer part.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		http.Error(w, "Failed to read video", http.StatusBadRequest)
+		return
+	}
+	head = head[:n]
+
+	// MOV files are not recognized by content sniffing; fall back to the declared type
+	contentType := http.DetectContentType(head)
+	if !strings.HasPrefix(contentType, "video/") {
+		contentType = part.Header.Get("Content-Type")
+	}
+	if !strings.HasPrefix(contentType, "video/") {
+		http.Error(w, "Only video files are allowed", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), facebookVideoUploadTimeout)
+	defer cancel()
+
+	bucket, err := database.Videos()
+	if err != nil {
+		slog.Error("facebook_video_bucket_error", "error", err)
+		http.Error(w, "Error saving video", http.StatusInternalServerError)
+		return
+	}
+
+	filename := part.FileName()
+	if filename == "" {
+		filename = "video"
+	}
+	uploadOpts := options.GridFSUpload().SetMetadata(bson.M{
+		"org_id":       orgID,
+		"uploader_id":  userID,
+		"content_type": contentType,
+	})
+	fileID := primitive.NewObjectID()
+	stream, err := bucket.OpenUploadStreamWithID(fileID, filename, uploadOpts)
+	if err != nil {
+		slog.Error("facebook_video_upload_open_error", "error", err)
+		http.Error(w, "Error saving video", http.StatusInternalServerError)
+		return
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetWriteDeadline(deadline)
+	}
+
+	size, copyErr := io.Copy(stream, io.MultiReader(bytes.NewReader(head), part))
+	if copyErr != nil {
+		stream.Abort()
+		var maxErr *http.MaxBytesError
+		if errors.As(copyErr, &maxErr) {
+			http.Error(w, "Video too large (max 1GB)", http.StatusRequestEntityTooLarge)
+			return
+		}
+		slog.Error("facebook_video_upload_error", "error", copyErr)
+		http.Error(w, "Error saving video", http.StatusInternalServerError)
+		return
+	}
+	if err := stream.Close(); err != nil {
+		slog.Error("facebook_video_upload_close_error", "error", err)
+		http.Error(w, "Error saving video", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("facebook_video_uploaded_file",
+		"video_id", fileID.Hex(),
+		"user_id", userID.Hex(),
+		"size", size,
+	)
+
+	json.NewEncoder(w).Encode(models.FacebookVideoUploadResponse{
+		ID:          fileID.Hex(),
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+	})
+}
+
+// publishFacebookVideo uploads the schedule's video to the page as a regular
+// video or a Reel and returns the Facebook video ID. Processing continues on
+// Facebook's side; PollFacebookVideoProcessing tracks it to completion.
+func publishFacebookVideo(ctx context.Context, creds *facebookCredentials, schedule models.FacebookSchedule) (string, error) {
+	oid, err := primitive.ObjectIDFromHex(schedule.VideoID)
+	if err != nil {
+		return "", fmt.Errorf("invalid video ID: %s", schedule.VideoID)
+	}
+	bucket, err := database.Videos()
+	if err != nil {
+		return "", fmt.Errorf("video storage: %w", err)
+	}
+	stream, err := bucket.OpenDownloadStream(oid)
+	if err != nil {
+		return "", fmt.Errorf("open video: %w", err)
+	}
+	defer stream.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetReadDeadline(deadline)
+	}
+	size := stream.GetFile().Length
+	if size == 0 {
+		return "", fmt.Errorf("video file is empty")
+	}
+
+	var videoID string
+	if schedule.MediaType == "reel" {
+		videoID, err = uploadFacebookReel(ctx, creds, stream, size, schedule.Title, schedule.Message)
+	} else {
+		videoID, err = uploadFacebookPageVideo(ctx, creds, stream, size, schedule.Title, schedule.Message)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The cover is cosmetic: a failure here doesn't fail the post
+	if schedule.ThumbnailID != "" {
+		if err := setFacebookVideoThumbnail(ctx, creds.Token, videoID, schedule.ThumbnailID); err != nil {
+			slog.Warn("facebook_video_thumbnail_failed", "fb_video_id", videoID, "error", err)
+		}
+	}
+	return videoID, nil
+}
+
+// uploadFacebookPageVideo sends a video with the resumable upload protocol:
+// a start phase opens a session, transfer phases send the chunks Facebook
+// asks for, and the finish phase publishes it with its title and description.
+func uploadFacebookPageVideo(ctx context.Context, creds *facebookCredentials, video io.Reader, size int64, title, description string) (string, error) {
+	endpoint := facebookVideoAPIBase + "/" + creds.PageID + "/videos"
+
+	start, err := postFacebookVideoForm(ctx, endpoint, url.Values{
+		"upload_phase": {"start"},
+		"file_size":    {strconv.FormatInt(size, 10)},
+		"access_token": {creds.Token},
+	})
+	if err != nil {
+		return "", fmt.Errorf("start upload: %w", err)
+	}
+	sessionID, _ := start["upload_session_id"].(string)
+	videoID, _ := start["video_id"].(string)
+	if sessionID == "" || videoID == "" {
+		return "", fmt.Errorf("start upload: unexpected response")
+	}
+
+	var sent int64
+	startOffset, endOffset := graphInt64(start["start_offset"]), graphInt64(start["end_offset"])
+	for startOffset < endOffset {
+		if startOffset != sent {
+			return "", fmt.Errorf("transfer: unexpected offset %d (sent %d)", startOffset, sent)
+		}
+		chunk := make([]byte, endOffset-startOffset)
+		if _, err := io.ReadFull(video, chunk); err != nil {
+			return "", fmt.Errorf("read video: %w", err)
+		}
+		sent += int64(len(chunk))
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		writer.WriteField("upload_phase", "transfer")
+		writer.WriteField("upload_session_id", sessionID)
+		writer.WriteField("start_offset", strconv.FormatInt(startOffset, 10))
+		writer.WriteField("access_token", creds.Token)
+		part, err := writer.CreateFormFile("video_file_chunk", "chunk")
+		if err != nil {
+			return "", err
+		}
+		part.Write(chunk)
+		writer.Close()
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, &body)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		result, err := doFacebookVideoRequest(req)
+		if err != nil {
+			return "", fmt.Errorf("transfer: %w", err)
+		}
+		startOffset, endOffset = graphInt64(result["start_offset"]), graphInt64(result["end_offset"])
+	}
+
+	finish := url.Values{
+		"upload_phase":      {"finish"},
+		"upload_session_id": {sessionID},
+		"published":         {"true"},
+		"access_token":      {creds.Token},
+	}
+	if title != "" {
+		finish.Set("title", title)
+	}
+	if description != "" {
+		finish.Set("description", description)
+	}
+	if _, err := postFacebookVideoForm(ctx, endpoint, finish); err != nil {
+		return "", fmt.Errorf("finish upload: %w", err)
+	}
+	return videoID, nil
+}
+
+// uploadFacebookReel publishes a Reel: the start phase returns an upload
+// URL that receives the whole file, then the finish phase publishes it.
+func uploadFacebookReel(ctx context.Context, creds *facebookCredentials, video io.Reader, size int64, title, description string) (string, error) {
+	start, err := metaGraphPost("/"+creds.PageID+"/video_reels", creds.Token, url.Values{"upload_phase": {"start"}})
+	if err != nil {
+		return "", fmt.Errorf("start reel upload: %w", err)
+	}
+	videoID, _ := start["video_id"].(string)
+	if videoID == "" {
+		return "", fmt.Errorf("start reel upload: unexpected response")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", facebookReelUploadURL+videoID, video)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+	req.Header.Set("Authorization", "OAuth "+creds.Token)
+	req.Header.Set("offset", "0")
+	req.Header.Set("file_size", strconv.FormatInt(size, 10))
+	if _, err := doFacebookVideoRequest(req); err != nil {
+		return "", fmt.Errorf("upload reel: %w", err)
+	}
+
+	finish := url.Values{
+		"upload_phase": {"finish"},
+		"video_id":     {videoID},
+		"video_state":  {"PUBLISHED"},
+	}
+	if title != "" {
+		finish.Set("title", title)
+	}
+	if description != "" {
+		finish.Set("description", description)
+	}
+	if _, err := metaGraphPost("/"+creds.PageID+"/video_reels", creds.Token, finish); err != nil {
+		return "", fmt.Errorf("finish reel upload: %w", err)
+	}
+	return videoID, nil
+}
+
+// setFacebookVideoThumbnail uploads an image from the images collection as
+// the video's preferred thumbnail.
+func setFacebookVideoThumbnail(ctx context.Context, token, videoID, imageID string) error {
+	oid, err := primitive.ObjectIDFromHex(imageID)
+	if err != nil {
+		return fmt.Errorf("invalid thumbnail ID: %s", imageID)
+	}
+	var img models.BlogImage
+	if err := database.Images().FindOne(ctx, bson.M{"_id": oid}).Decode(&img); err != nil {
+		return fmt.Errorf("thumbnail not found: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(img.Data)
+	if err != nil {
+		return fmt.Errorf("decode thumbnail: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("is_preferred", "true")
+	writer.WriteField("access_token", token)
+	part, err := writer.CreateFormFile("source", "thumbnail.jpg")
+	if err != nil {
+		return err
+	}
+	part.Write(data)
+	writer.Close()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", metaAdsAPIBase+"/"+videoID+"/thumbnails", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	_, err = doFacebookVideoRequest(req)
+	return err
+}
+
+// postFacebookVideoForm posts a form-encoded request to a video endpoint.
+func postFacebookVideoForm(ctx context.Context, endpoint string, form url.Values) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doFacebookVideoRequest(req)
+}
+
+// doFacebookVideoRequest sends a video API request and decodes the JSON
+// response, turning Graph API errors and non-2xx statuses into errors.
+func doFacebookVideoRequest(req *http.Request) (map[string]interface{}, error) {
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var result map[string]interface{}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("facebook API error %d: %s", resp.StatusCode, string(respBody))
+		}
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+	if errObj, ok := result["error"]; ok {
+		return nil, fmt.Errorf("facebook API error: %v", errObj)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("facebook API error %d: %s", resp.StatusCode, string(respBody))
+	}
+	return result, nil
+}
+
+// graphInt64 reads a Graph API number that may be encoded as a string.
+func graphInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+// facebookVideoStatus is the processing state of an uploaded video.
+type facebookVideoStatus struct {
+	PostID string `json:"post_id"`
+	Status struct {
+		VideoStatus     string              `json:"video_status"` // "processing", "ready", "error", ...
+		ProcessingPhase *facebookVideoPhase `json:"processing_phase"`
+		PublishingPhase *facebookVideoPhase `json:"publishing_phase"`
+	} `json:"status"`
+}
+
+type facebookVideoPhase struct {
+	Status string `json:"status"` // "not_started", "in_progress", "complete", "error"
+	Errors []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// fetchFacebookVideoStatus reads a video's processing and publishing phases.
+func fetchFacebookVideoStatus(ctx context.Context, token, videoID string) (*facebookVideoStatus, error) {
+	params := url.Values{"fields": {"status,post_id"}, "access_token": {token}}
+	req, err := http.NewRequestWithContext(ctx, "GET", metaAdsAPIBase+"/"+videoID+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		facebookVideoStatus
+		Error *struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("facebook API error %d: %s", result.Error.Code, result.Error.Message)
+	}
+	return &result.facebookVideoStatus, nil
+}
+
+// videoOutcome reports whether the video finished ("published"), failed
+// ("failed", with a reason) or is still being processed ("").
+func (s *facebookVideoStatus) videoOutcome() (string, string) {
+	for _, phase := range []*facebookVideoPhase{s.Status.ProcessingPhase, s.Status.PublishingPhase} {
+		if phase != nil && phase.Status == "error" {
+			if len(phase.Errors) > 0 {
+				return "failed", phase.Errors[0].Message
+			}
+			return "failed", "Facebook could not process the video"
+		}
+	}
+	if s.Status.VideoStatus == "error" {
+		return "failed", "Facebook could not process the video"
+	}
+	if s.Status.VideoStatus != "ready" {
+		return "", ""
+	}
+	if s.Status.PublishingPhase != nil && s.Status.PublishingPhase.Status != "complete" {
+		return "", ""
+	}
+	return "published", ""
+}
+
+// PollFacebookVideoProcessing checks videos and Reels Facebook is still
+// processing and marks their posts published or failed once it finishes.
+func PollFacebookVideoProcessing() {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cursor, err := database.FacebookSchedules().Find(ctx, bson.M{"status": "processing"})
+	if err != nil {
+		slog.Error("facebook_video_poll_query_error", "error", err)
+		return
+	}
+	var schedules []models.FacebookSchedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		slog.Error("facebook_video_poll_decode_error", "error", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		outcome, reason := "", ""
+
+		p, err := newPublisher(ctx, "facebook", schedule.UserID, schedule.OrgID)
+		switch {
+		case err != nil:
+			slog.Warn("facebook_video_poll_no_credentials", "schedule_id", schedule.ID.Hex(), "error", err)
+		case schedule.FBVideoID == "":
+			outcome, reason = "failed", "missing Facebook video ID"
+		default:
+			status, err := p.FetchStatus(ctx, facebookPublishContent(schedule), schedule.FBVideoID)
+			if err != nil {
+				slog.Warn("facebook_video_poll_error", "schedule_id", schedule.ID.Hex(), "error", err)
+				break
+			}
+			if status.State != "processing" {
+				outcome, reason = status.State, status.Reason
+			}
+			if outcome == "published" {
+				postID := status.PostID
+				if postID == "" {
+					postID = schedule.FBVideoID
+				}
+				database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
+					"$set": bson.M{
+						"status":       "published",
+						"fb_post_id":   postID,
+						"published_at": time.Now(),
+						"updated_at":   time.Now(),
+					},
+				})
+				middleware.IncFacebookPublished()
+				slog.Info("facebook_published",
+					"schedule_id", schedule.ID.Hex(),
+					"fb_post_id", postID,
+					"fb_video_id", schedule.FBVideoID,
+				)
+				continue
+			}
+		}
+
+		if outcome == "" && time.Since(schedule.UpdatedAt) > facebookVideoProcessingTimeout {
+			outcome, reason = "failed", "video processing timed out"
+		}
+		if outcome != "failed" {
+			continue
+		}
+
+		slog.Error("facebook_publish_failed",
+			"schedule_id", schedule.ID.Hex(),
+			"fb_video_id", schedule.FBVideoID,
+			"error", reason,
+		)
+		database.FacebookSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
+			"$set": bson.M{
+				"status":        "failed",
+				"error_message": reason,
+				"updated_at":    time.Now(),
+			},
+		})
+	}
+}
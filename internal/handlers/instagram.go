@@ -3,11 +3,9 @@ package handlers
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"image"
-	"image/jpeg"
 	_ "image/png"
 	"io"
 	"log/slog"
@@ -31,10 +29,11 @@ import (
 )
 
 // ExchangeForLongLivedToken exchanges a short-lived Meta token for a long-lived one (~60 days).
-func ExchangeForLongLivedToken(shortToken string) (string, error) {
+// It also returns the token lifetime in seconds (0 when Meta does not report one).
+func ExchangeForLongLivedToken(shortToken string) (string, int, error) {
 	cfg := config.Get()
 	if cfg.MetaAppID == "" || cfg.MetaAppSecret == "" {
-		return "", fmt.Errorf("META_APP_ID or META_APP_SECRET not configured")
+		return "", 0, fmt.Errorf("META_APP_ID or META_APP_SECRET not configured")
 	}
 
 	url := fmt.Sprintf(
@@ -44,7 +43,7 @@ func ExchangeForLongLivedToken(shortToken string) (string, error) {
 
 	resp, err := http.Get(url)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return "", 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -59,19 +58,19 @@ func ExchangeForLongLivedToken(shortToken string) (string, error) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode failed: %w", err)
+		return "", 0, fmt.Errorf("decode failed: %w", err)
 	}
 
 	if result.Error != nil {
-		return "", fmt.Errorf("meta error %d: %s", result.Error.Code, result.Error.Message)
+		return "", 0, fmt.Errorf("meta error %d: %s", result.Error.Code, result.Error.Message)
 	}
 
 	if result.AccessToken == "" {
-		return "", fmt.Errorf("empty access_token in response")
+		return "", 0, fmt.Errorf("empty access_token in response")
 	}
 
 	slog.Info("long_lived_token_obtained", "expires_in_seconds", result.ExpiresIn)
-	return result.AccessToken, nil
+	return result.AccessToken, result.ExpiresIn, nil
 }
 
 // instagramCredentials holds resolved Instagram API credentials.
@@ -247,6 +246,12 @@ func GetInstagramConfig(w http.ResponseWriter, r *http.Request) {
 		resp.AccountID = maskAccountID(creds.AccountID)
 		resp.Source = creds.Source
 	}
+	if creds != nil && creds.Source == "user" {
+		var cfg models.InstagramConfig
+		if database.InstagramConfigs().FindOne(ctx, bson.M{"org_id": orgID, "instagram_account_id": creds.AccountID}).Decode(&cfg) == nil {
+			resp.Token = tokenHealth(cfg.TokenMeta)
+		}
+	}
 
 	// If source is "user", also load Meta Ads fields from the primary config
 	if creds != nil && creds.Source == "user" && orgID != primitive.NilObjectID {
@@ -371,7 +376,7 @@ func SaveInstagramConfig(w http.ResponseWriter, r *http.Request) {
 
 		// Handle manual token input (manual config flow)
 		if req.AccessToken != "" {
-			longToken, err := ExchangeForLongLivedToken(req.AccessToken)
+			longToken, expiresIn, err := ExchangeForLongLivedToken(req.AccessToken)
 			if err != nil {
 				slog.Warn("long_lived_token_exchange_failed, using original token", "error", err)
 				longToken = req.AccessToken
@@ -382,6 +387,7 @@ func SaveInstagramConfig(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			setFields["access_token_enc"] = encToken
+			setFields["token_meta"] = newTokenMeta(longToken, expiresIn)
 		}
 
 		update := bson.M{
@@ -706,7 +712,7 @@ func GetInstagramFeed(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Param body body models.CreateInstagramScheduleRequest true "Dados do agendamento"
 // @Success 201 {object} models.InstagramScheduleResponse
-// @Failure 400 {string} string "Invalid request"
+// @Failure 400 {object} models.PostValidationResult "Validation failed"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Error creating schedule"
 // @Router /admin/instagram/schedules [post]
@@ -724,30 +730,15 @@ func CreateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(req.ImageIDs) == 0 {
-		http.Error(w, "At least one image is required", http.StatusBadRequest)
-		return
-	}
-
-	if req.MediaType != "image" && req.MediaType != "carousel" {
-		http.Error(w, "media_type must be 'image' or 'carousel'", http.StatusBadRequest)
-		return
-	}
-
-	if req.MediaType == "image" && len(req.ImageIDs) > 1 {
-		http.Error(w, "image type allows only one image; use 'carousel' for multiple", http.StatusBadRequest)
-		return
-	}
-
-	if req.MediaType == "carousel" && len(req.ImageIDs) < 2 {
-		http.Error(w, "carousel requires at least 2 images", http.StatusBadRequest)
-		return
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	if len(req.Caption) > 2200 {
-		http.Error(w, "Caption must be 2200 characters or less", http.StatusBadRequest)
+	caption, msg := appendHashtagSets(ctx, orgID, req.Caption, req.HashtagSetIDs)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
 		return
 	}
+	req.Caption = caption
 
 	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
 	if err != nil {
@@ -755,23 +746,19 @@ func CreateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	// Verify all images exist
-	for _, imgID := range req.ImageIDs {
-		oid, err := primitive.ObjectIDFromHex(imgID)
-		if err != nil {
-			http.Error(w, "Invalid image ID: "+imgID, http.StatusBadRequest)
-			return
-		}
-		count, err := database.Images().CountDocuments(ctx, bson.M{"_id": oid})
-		if err != nil || count == 0 {
-			http.Error(w, "Image not found: "+imgID, http.StatusBadRequest)
+	if res := validateInstagramPost(ctx, req.Caption, req.MediaType, req.ImageIDs); !res.Valid {
+		writeValidationErrors(w, res)
+		return
+	}
+	if req.PostToThreads {
+		if msg := validateThreadsCrosspost(ctx, orgID, req.Caption, req.ImageIDs); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
 	}
 
+	status, approvals := initialApprovalState(ctx, orgID, userID)
+
 	now := time.Now()
 	schedule := models.InstagramSchedule{
 		ID:             primitive.NewObjectID(),
@@ -781,8 +768,10 @@ func CreateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 		MediaType:      req.MediaType,
 		ImageIDs:       req.ImageIDs,
 		ScheduledAt:    scheduledAt,
-		Status:         "scheduled",
+		Status:         status,
 		PostToFacebook: req.PostToFacebook,
+		PostToThreads:  req.PostToThreads,
+		Approvals:      approvals,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 	}
@@ -792,6 +781,9 @@ func CreateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error creating schedule", http.StatusInternalServerError)
 		return
 	}
+	if status == "pending_approval" {
+		notifyReviewers(ctx, orgID, 1, schedule.Caption)
+	}
 
 	slog.Info("instagram_schedule_created",
 		"schedule_id", schedule.ID.Hex(),
@@ -800,6 +792,7 @@ func CreateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 	)
 
 	resp := buildScheduleResponse(schedule)
+	resp.Warnings = publishingQuotaWarnings(ctx, userID, orgID, scheduledAt, schedule.ID)
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(resp)
 }
@@ -862,9 +855,16 @@ func ListInstagramSchedules(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]primitive.ObjectID, len(schedules))
+	for i, s := range schedules {
+		ids[i] = s.ID
+	}
+	counts := contentCommentCounts(ctx, orgID, "instagram_schedule", ids)
+
 	responses := make([]models.InstagramScheduleResponse, len(schedules))
 	for i, s := range schedules {
 		responses[i] = buildScheduleResponse(s)
+		responses[i].CommentCount = counts[s.ID]
 	}
 
 	json.NewEncoder(w).Encode(models.InstagramScheduleListResponse{
@@ -920,7 +920,7 @@ func GetInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 // @Param id path string true "ID do agendamento"
 // @Param body body models.UpdateInstagramScheduleRequest true "Dados para atualizar"
 // @Success 200 {object} models.InstagramScheduleResponse
-// @Failure 400 {string} string "Invalid request"
+// @Failure 400 {object} models.PostValidationResult "Validation failed"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 404 {string} string "Schedule not found"
 // @Failure 500 {string} string "Error updating schedule"
@@ -951,8 +951,8 @@ func UpdateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if schedule.Status != "scheduled" && schedule.Status != "failed" {
-		http.Error(w, "Can only edit scheduled or failed posts", http.StatusBadRequest)
+	if schedule.Status != "scheduled" && schedule.Status != "failed" && schedule.Status != "pending_approval" && schedule.Status != "rejected" {
+		http.Error(w, "Can only edit scheduled, failed or in-review posts", http.StatusBadRequest)
 		return
 	}
 
@@ -960,40 +960,33 @@ func UpdateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 	setFields := update["$set"].(bson.M)
 
 	if req.Caption != nil {
-		if len(*req.Caption) > 2200 {
-			http.Error(w, "Caption must be 2200 characters or less", http.StatusBadRequest)
-			return
-		}
+		schedule.Caption = *req.Caption
 		setFields["caption"] = *req.Caption
 	}
 
 	if req.MediaType != nil {
-		if *req.MediaType != "image" && *req.MediaType != "carousel" {
-			http.Error(w, "media_type must be 'image' or 'carousel'", http.StatusBadRequest)
-			return
-		}
+		schedule.MediaType = *req.MediaType
 		setFields["media_type"] = *req.MediaType
 	}
 
 	if req.ImageIDs != nil {
-		if len(req.ImageIDs) == 0 {
-			http.Error(w, "At least one image is required", http.StatusBadRequest)
+		schedule.ImageIDs = req.ImageIDs
+		setFields["image_ids"] = req.ImageIDs
+	}
+
+	// Validate the post as it will be after the update
+	if res := validateInstagramPost(ctx, schedule.Caption, schedule.MediaType, schedule.ImageIDs); !res.Valid {
+		writeValidationErrors(w, res)
+		return
+	}
+	if req.PostToThreads != nil {
+		schedule.PostToThreads = *req.PostToThreads
+	}
+	if schedule.PostToThreads {
+		if msg := validateThreadsCrosspost(ctx, orgID, schedule.Caption, schedule.ImageIDs); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
 			return
 		}
-		// Verify images exist
-		for _, imgID := range req.ImageIDs {
-			imgOID, err := primitive.ObjectIDFromHex(imgID)
-			if err != nil {
-				http.Error(w, "Invalid image ID: "+imgID, http.StatusBadRequest)
-				return
-			}
-			count, err := database.Images().CountDocuments(ctx, bson.M{"_id": imgOID})
-			if err != nil || count == 0 {
-				http.Error(w, "Image not found: "+imgID, http.StatusBadRequest)
-				return
-			}
-		}
-		setFields["image_ids"] = req.ImageIDs
 	}
 
 	if req.ScheduledAt != nil {
@@ -1003,6 +996,17 @@ func UpdateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		setFields["scheduled_at"] = scheduledAt
+		// A new time replaces any quota deferral
+		if !scheduledAt.Equal(schedule.ScheduledAt) {
+			update["$unset"] = bson.M{"deferred_until": "", "defer_reason": ""}
+		}
+	}
+
+	if req.PostToFacebook != nil {
+		setFields["post_to_facebook"] = *req.PostToFacebook
+	}
+	if req.PostToThreads != nil {
+		setFields["post_to_threads"] = *req.PostToThreads
 	}
 
 	// If re-scheduling a failed post, reset status
@@ -1011,6 +1015,18 @@ func UpdateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 		setFields["error_message"] = ""
 	}
 
+	// Editing a single occurrence detaches it from later series edits
+	if schedule.SeriesID != nil {
+		setFields["detached"] = true
+	}
+
+	// Under the approval workflow, edits send the post back to review
+	resubmitted := resubmitOnEdit(ctx, orgID, middleware.GetUserID(r), schedule.Status)
+	if resubmitted != nil {
+		setFields["status"] = "pending_approval"
+		update["$push"] = bson.M{"approvals": *resubmitted}
+	}
+
 	_, err = database.InstagramSchedules().UpdateOne(ctx, bson.M{"_id": oid, "org_id": orgID}, update)
 	if err != nil {
 		http.Error(w, "Error updating schedule", http.StatusInternalServerError)
@@ -1019,12 +1035,19 @@ func UpdateInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 
 	var updated models.InstagramSchedule
 	database.InstagramSchedules().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&updated)
+	if resubmitted != nil {
+		notifyReviewers(ctx, orgID, 1, updated.Caption)
+	}
 
 	slog.Info("instagram_schedule_updated",
 		"schedule_id", oid.Hex(),
 	)
 
-	json.NewEncoder(w).Encode(buildScheduleResponse(updated))
+	resp := buildScheduleResponse(updated)
+	if req.ScheduledAt != nil && updated.Status == "scheduled" {
+		resp.Warnings = publishingQuotaWarnings(ctx, updated.UserID, orgID, updated.ScheduledAt, updated.ID)
+	}
+	json.NewEncoder(w).Encode(resp)
 }
 
 // DeleteInstagramSchedule deletes a scheduled post
@@ -1070,6 +1093,8 @@ func DeleteInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error deleting schedule", http.StatusInternalServerError)
 		return
 	}
+	skipSeriesOccurrence(ctx, schedule.SeriesID, schedule.OccurrenceAt)
+	deleteContentComments(ctx, orgID, "instagram_schedule", oid)
 
 	slog.Info("instagram_schedule_deleted",
 		"schedule_id", oid.Hex(),
@@ -1080,13 +1105,18 @@ func DeleteInstagramSchedule(w http.ResponseWriter, r *http.Request) {
 
 // UploadInstagramImage uploads an image for Instagram, resized to max 1080x1080
 // @Summary Upload de imagem para Instagram
-// @Description Faz upload de imagem redimensionada para max 1080px (JPEG, PNG, WebP)
+// @Description Faz upload de imagem redimensionada para max 1080px (JPEG, PNG, WebP), opcionalmente recortada para uma proporção em torno de um ponto focal ou com faixas de cor. O original é mantido para permitir refazer o recorte
 // @Tags instagram
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
 // @Param image formData file true "Arquivo de imagem (max 10MB)"
-// @Success 200 {object} map[string]string
+// @Param aspect_ratio formData string false "Proporção alvo: 1:1, 4:5 ou 1.91:1"
+// @Param mode formData string false "crop (padrão) ou letterbox"
+// @Param focal_x formData number false "Ponto focal horizontal, 0-1 (padrão 0.5)"
+// @Param focal_y formData number false "Ponto focal vertical, 0-1 (padrão 0.5)"
+// @Param background formData string false "Cor das faixas no letterbox, #RRGGBB (padrão #FFFFFF)"
+// @Success 200 {object} models.InstagramImageResponse
 // @Failure 400 {string} string "Invalid image"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 413 {string} string "Image too large"
@@ -1107,6 +1137,21 @@ func UploadInstagramImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var crop *models.ImageCrop
+	cropReq, msg := cropRequestFromForm(r)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if cropReq != nil {
+		c, msg := normalizeImageCrop(*cropReq)
+		if msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		crop = &c
+	}
+
 	file, _, err := r.FormFile("image")
 	if err != nil {
 		http.Error(w, "No image provided. Use field name 'image'", http.StatusBadRequest)
@@ -1132,33 +1177,20 @@ func UploadInstagramImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Resize to max 1080px width (Instagram requirement)
-	resized := resizeInstagramImage(img, 1080)
-	bounds := resized.Bounds()
-
-	var buf bytes.Buffer
-	if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85}); err != nil {
+	originalDoc, imgDoc, err := instagramImageDocs(img, crop, userID, orgID)
+	if err != nil {
 		http.Error(w, "Failed to process image", http.StatusInternalServerError)
 		return
 	}
 
-	base64Img := base64.StdEncoding.EncodeToString(buf.Bytes())
-
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	imgDoc := models.BlogImage{
-		ID:         primitive.NewObjectID(),
-		UploaderID: userID,
-		OrgID:      orgID,
-		Width:      bounds.Dx(),
-		Data:       base64Img,
-		Size:       buf.Len(),
-		CreatedAt:  time.Now(),
+	if _, err := database.Images().InsertOne(ctx, originalDoc); err != nil {
+		http.Error(w, "Error saving image", http.StatusInternalServerError)
+		return
 	}
-
-	_, err = database.Images().InsertOne(ctx, imgDoc)
-	if err != nil {
+	if _, err := database.Images().InsertOne(ctx, imgDoc); err != nil {
 		http.Error(w, "Error saving image", http.StatusInternalServerError)
 		return
 	}
@@ -1166,16 +1198,70 @@ func UploadInstagramImage(w http.ResponseWriter, r *http.Request) {
 	slog.Info("instagram_image_uploaded",
 		"image_id", imgDoc.ID.Hex(),
 		"user_id", userID.Hex(),
-		"width", bounds.Dx(),
-		"height", bounds.Dy(),
+		"width", imgDoc.Width,
+		"height", imgDoc.Height,
 	)
 
-	json.NewEncoder(w).Encode(map[string]string{
-		"id":  imgDoc.ID.Hex(),
-		"url": "/api/v1/blog/images/" + imgDoc.ID.Hex(),
+	json.NewEncoder(w).Encode(models.InstagramImageResponse{
+		ID:     imgDoc.ID.Hex(),
+		URL:    "/api/v1/blog/images/" + imgDoc.ID.Hex(),
+		Width:  imgDoc.Width,
+		Height: imgDoc.Height,
+		Crop:   crop,
 	})
 }
 
+// instagramImageDocs runs the upload pipeline on a decoded image: it keeps a bounded
+// copy of the source (so the crop can be edited later) and crops or resizes it for
+// the feed. Returns both documents unsaved; the feed image points to the original.
+func instagramImageDocs(img image.Image, crop *models.ImageCrop, userID, orgID primitive.ObjectID) (models.BlogImage, models.BlogImage, error) {
+	original := limitImageSize(img, originalMaxSide)
+	originalData, originalSize, err := encodeJPEGBase64(original, 90)
+	if err != nil {
+		return models.BlogImage{}, models.BlogImage{}, err
+	}
+
+	// Crop to the requested ratio, or resize to max 1080px width within Instagram's feed limits
+	var resized image.Image
+	if crop != nil {
+		resized = applyImageCrop(original, *crop, instagramMaxWidth)
+	} else {
+		resized = resizeInstagramImage(img, instagramMaxWidth)
+	}
+	bounds := resized.Bounds()
+
+	base64Img, size, err := encodeJPEGBase64(resized, 85)
+	if err != nil {
+		return models.BlogImage{}, models.BlogImage{}, err
+	}
+
+	now := time.Now()
+	originalDoc := models.BlogImage{
+		ID:         primitive.NewObjectID(),
+		UploaderID: userID,
+		OrgID:      orgID,
+		SizeLabel:  "original",
+		Width:      original.Bounds().Dx(),
+		Height:     original.Bounds().Dy(),
+		Data:       originalData,
+		Size:       originalSize,
+		CreatedAt:  now,
+	}
+	imgDoc := models.BlogImage{
+		ID:         primitive.NewObjectID(),
+		UploaderID: userID,
+		OrgID:      orgID,
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		OriginalID: &originalDoc.ID,
+		Crop:       crop,
+		Data:       base64Img,
+		Size:       size,
+		CreatedAt:  now,
+	}
+	return originalDoc, imgDoc, nil
+}
+
 // resizeInstagramImage resizes image to max width and enforces Instagram feed
 // aspect ratio limits (4:5 portrait to 1.91:1 landscape). Images taller than
 // 4:5 are center-cropped before resizing.
@@ -1195,7 +1281,6 @@ func resizeInstagramImage(img image.Image, maxWidth int) image.Image {
 		cropped := image.NewRGBA(image.Rect(0, 0, srcW, newH))
 		draw.CatmullRom.Scale(cropped, cropped.Bounds(), img, cropRect, draw.Over, nil)
 		img = cropped
-		srcW = srcW
 		srcH = newH
 	} else if ratio > 1.91 {
 		// Too wide — crop to 1.91:1 from center
@@ -1206,7 +1291,6 @@ func resizeInstagramImage(img image.Image, maxWidth int) image.Image {
 		draw.CatmullRom.Scale(cropped, cropped.Bounds(), img, cropRect, draw.Over, nil)
 		img = cropped
 		srcW = newW
-		srcH = srcH
 	}
 
 	if srcW <= maxWidth {
@@ -1222,16 +1306,20 @@ func resizeInstagramImage(img image.Image, maxWidth int) image.Image {
 	return dst
 }
 
-// getPublicImageURL builds the public URL for serving an image
-func getPublicImageURL(imageID string) string {
+// publicAPIBaseURL returns the externally reachable base URL of the API
+func publicAPIBaseURL() string {
 	// Use RENDER_EXTERNAL_URL (deployed) or FRONTEND_URL as base
 	baseURL := os.Getenv("RENDER_EXTERNAL_URL")
 	if baseURL == "" {
 		baseURL = config.Get().FrontendURL
 	}
 	// Remove trailing slash
-	baseURL = strings.TrimRight(baseURL, "/")
-	return baseURL + "/api/v1/blog/images/" + imageID
+	return strings.TrimRight(baseURL, "/")
+}
+
+// getPublicImageURL builds the public URL for serving an image
+func getPublicImageURL(imageID string) string {
+	return publicAPIBaseURL() + "/api/v1/blog/images/" + imageID
 }
 
 // publishToInstagram publishes a scheduled post to Instagram via Graph API
@@ -1239,60 +1327,11 @@ func publishToInstagram(schedule models.InstagramSchedule) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
 	defer cancel()
 
-	creds, err := getInstagramCredentials(ctx, schedule.UserID, schedule.OrgID)
-	if err != nil {
-		return "", fmt.Errorf("get credentials: %w", err)
-	}
-	if creds == nil {
-		return "", fmt.Errorf("instagram not configured")
-	}
-
-	accountID := creds.AccountID
-	token := creds.Token
-
-	if schedule.MediaType == "image" {
-		// Single image post
-		imageURL := getPublicImageURL(schedule.ImageIDs[0])
-
-		// Step 1: Create media container
-		containerID, err := createMediaContainer(accountID, token, imageURL, schedule.Caption, false)
-		if err != nil {
-			return "", fmt.Errorf("create container: %w", err)
-		}
-
-		// Step 2: Publish
-		mediaID, err := publishMediaContainer(accountID, token, containerID)
-		if err != nil {
-			return "", fmt.Errorf("publish: %w", err)
-		}
-
-		return mediaID, nil
-	}
-
-	// Carousel post
-	var childIDs []string
-	for _, imgID := range schedule.ImageIDs {
-		imageURL := getPublicImageURL(imgID)
-		childID, err := createMediaContainer(accountID, token, imageURL, "", true)
-		if err != nil {
-			return "", fmt.Errorf("create carousel item: %w", err)
-		}
-		childIDs = append(childIDs, childID)
-	}
-
-	// Create carousel container
-	carouselID, err := createCarouselContainer(accountID, token, childIDs, schedule.Caption)
+	p, err := newPublisher(ctx, "instagram", schedule.UserID, schedule.OrgID)
 	if err != nil {
-		return "", fmt.Errorf("create carousel container: %w", err)
-	}
-
-	// Publish carousel
-	mediaID, err := publishMediaContainer(accountID, token, carouselID)
-	if err != nil {
-		return "", fmt.Errorf("publish carousel: %w", err)
+		return "", err
 	}
-
-	return mediaID, nil
+	return publishWith(ctx, p, instagramPublishContent(schedule))
 }
 
 // createMediaContainer creates an IG media container for a single image or carousel item.
@@ -1461,6 +1500,10 @@ func ProcessScheduledInstagramPosts() {
 	filter := bson.M{
 		"status":       "scheduled",
 		"scheduled_at": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"deferred_until": bson.M{"$exists": false}},
+			{"deferred_until": bson.M{"$lte": now}},
+		},
 	}
 
 	cursor, err := database.InstagramSchedules().Find(ctx, filter)
@@ -1476,6 +1519,7 @@ func ProcessScheduledInstagramPosts() {
 		return
 	}
 
+	quotas := publishingQuotaTracker{}
 	for _, schedule := range schedules {
 		// Skip posts without org context — safety guard
 		if schedule.OrgID == primitive.NilObjectID {
@@ -1486,6 +1530,28 @@ func ProcessScheduledInstagramPosts() {
 			continue
 		}
 
+		// Defer instead of failing when the account's publishing quota is used up
+		creds, _ := getInstagramCredentials(ctx, schedule.UserID, schedule.OrgID)
+		if creds != nil {
+			if limit, ok := quotas.allow(creds); !ok {
+				until := time.Now().Add(igQuotaDeferral)
+				database.InstagramSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
+					"$set": bson.M{
+						"deferred_until": until,
+						"defer_reason":   fmt.Sprintf("publishing quota reached (%d/%d in 24h)", limit.QuotaUsage, limit.QuotaTotal),
+						"updated_at":     time.Now(),
+					},
+				})
+				slog.Warn("instagram_scheduler_quota_deferred",
+					"schedule_id", schedule.ID.Hex(),
+					"org_id", schedule.OrgID.Hex(),
+					"quota_usage", limit.QuotaUsage,
+					"quota_total", limit.QuotaTotal,
+				)
+				continue
+			}
+		}
+
 		// Set status to publishing
 		database.InstagramSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
 			"$set": bson.M{"status": "publishing", "updated_at": time.Now()},
@@ -1509,14 +1575,19 @@ func ProcessScheduledInstagramPosts() {
 					"error_message": err.Error(),
 					"updated_at":    time.Now(),
 				},
+				"$unset": bson.M{"deferred_until": "", "defer_reason": ""},
 			})
 			continue
 		}
+		if creds != nil {
+			quotas.consume(creds.AccountID)
+		}
 
 		updateFields := bson.M{
-			"status":      "published",
-			"ig_media_id": mediaID,
-			"updated_at":  time.Now(),
+			"status":       "published",
+			"ig_media_id":  mediaID,
+			"published_at": time.Now(),
+			"updated_at":   time.Now(),
 		}
 
 		// Crosspost to Facebook if enabled
@@ -1540,8 +1611,30 @@ func ProcessScheduledInstagramPosts() {
 			}
 		}
 
+		// Crosspost to Threads if enabled
+		if schedule.PostToThreads {
+			threadsMediaID, thErr := crosspostToThreads(schedule)
+			if thErr != nil {
+				slog.Warn("threads_crosspost_failed",
+					"schedule_id", schedule.ID.Hex(),
+					"error", thErr,
+				)
+				updateFields["threads_status"] = "failed"
+				updateFields["threads_error"] = thErr.Error()
+			} else {
+				updateFields["threads_status"] = "published"
+				updateFields["threads_media_id"] = threadsMediaID
+				middleware.IncThreadsPublished()
+				slog.Info("threads_crosspost_success",
+					"schedule_id", schedule.ID.Hex(),
+					"threads_media_id", threadsMediaID,
+				)
+			}
+		}
+
 		database.InstagramSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID}, bson.M{
-			"$set": updateFields,
+			"$set":   updateFields,
+			"$unset": bson.M{"deferred_until": "", "defer_reason": ""},
 		})
 
 		middleware.IncInstagramPublished()
@@ -1553,32 +1646,44 @@ func ProcessScheduledInstagramPosts() {
 	}
 }
 
-// crosspostToFacebook publishes the same content to Facebook Page
+// crosspostToFacebook publishes the same photos and caption to the Facebook Page
 func crosspostToFacebook(schedule models.InstagramSchedule) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	creds, err := getFacebookCredentials(ctx, schedule.UserID, schedule.OrgID)
+	fb, err := crosspostFacebookSchedule(schedule)
 	if err != nil {
-		return "", fmt.Errorf("get facebook credentials: %w", err)
-	}
-	if creds == nil {
-		return "", fmt.Errorf("facebook not configured for this organization")
+		return "", err
 	}
-
-	// Determine post type based on number of images
-	if len(schedule.ImageIDs) == 1 {
-		// Single image post
-		imageURL := getPublicImageURL(schedule.ImageIDs[0])
-		return publishFacebookPhotoPost(creds.PageID, creds.Token, schedule.Caption, imageURL)
+	p, err := newPublisher(ctx, "facebook", schedule.UserID, schedule.OrgID)
+	if err != nil {
+		return "", err
 	}
+	return publishWith(ctx, p, facebookPublishContent(fb))
+}
 
-	// Multiple images - carousel/multi-photo post
-	var imageURLs []string
-	for _, imgID := range schedule.ImageIDs {
-		imageURLs = append(imageURLs, getPublicImageURL(imgID))
-	}
-	return publishFacebookMultiPhotoPost(creds.PageID, creds.Token, schedule.Caption, imageURLs)
+// crosspostFacebookSchedule maps an Instagram schedule to the equivalent
+// Facebook post. Instagram schedules hold images only, so crossposts are photo
+// or multi-photo posts, published synchronously; Facebook videos and Reels are
+// scheduled on their own (see facebook_video.go).
+func crosspostFacebookSchedule(schedule models.InstagramSchedule) (models.FacebookSchedule, error) {
+	var mediaType string
+	switch {
+	case schedule.MediaType == "carousel" || schedule.MediaType == "image" && len(schedule.ImageIDs) > 1:
+		mediaType = "carousel"
+	case schedule.MediaType == "image":
+		mediaType = "image"
+	default:
+		return models.FacebookSchedule{}, fmt.Errorf("crosspost to facebook supports image and carousel posts only, got %s", schedule.MediaType)
+	}
+	return models.FacebookSchedule{
+		ID:        schedule.ID,
+		UserID:    schedule.UserID,
+		OrgID:     schedule.OrgID,
+		Message:   schedule.Caption,
+		MediaType: mediaType,
+		ImageIDs:  schedule.ImageIDs,
+	}, nil
 }
 
 // ListAllOrgInstagramProfiles returns IG profiles across all orgs the user belongs to.
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// accountInsightMetrics are the daily account-level insights stored in each snapshot.
+var accountInsightMetrics = []string{"reach", "impressions", "profile_views", "website_clicks"}
+
+// GetAccountGrowthReport returns period-over-period account growth from the daily snapshots.
+// @Summary Obter crescimento da conta
+// @Description Retorna a variação de seguidores, alcance, impressões, visitas ao perfil e cliques no site em relação ao período anterior, com a série diária para gráficos
+// @Tags instagram-analytics
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Período: 7, 30 ou 90 (padrão 30)"
+// @Param instagram_account_id query string false "Conta do Instagram (padrão: conta principal)"
+// @Success 200 {object} models.AccountGrowthReport
+// @Failure 400 {string} string "Instagram not configured"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/analytics/account [get]
+func GetAccountGrowthReport(w http.ResponseWriter, r *http.Request) {
+	_, creds, ok := requireInstagramCreds(w, r)
+	if !ok {
+		return
+	}
+	orgID := middleware.GetOrgID(r)
+
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days != 7 && days != 90 {
+		days = 30
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Snapshots are dated by capture day, so the current period ends today
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	currentStart := today.AddDate(0, 0, 1-days).Format("2006-01-02")
+	previousStart := today.AddDate(0, 0, 1-2*days).Format("2006-01-02")
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := database.IGAccountSnapshots().Find(ctx, bson.M{
+		"org_id":     orgID,
+		"account_id": creds.AccountID,
+		"date":       bson.M{"$gte": previousStart},
+	}, opts)
+	if err != nil {
+		http.Error(w, "Error loading snapshots", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []models.IGAccountSnapshot
+	cursor.All(ctx, &snapshots)
+
+	var previous, current []models.IGAccountSnapshot
+	for _, s := range snapshots {
+		if s.Date >= currentStart {
+			current = append(current, s)
+		} else {
+			previous = append(previous, s)
+		}
+	}
+	if current == nil {
+		current = []models.IGAccountSnapshot{}
+	}
+
+	sum := func(list []models.IGAccountSnapshot, field func(models.IGAccountSnapshot) int64) int64 {
+		var total int64
+		for _, s := range list {
+			total += field(s)
+		}
+		return total
+	}
+	// growth is the change of a counter across a period, measured from the end of the previous one when available
+	growth := func(list, before []models.IGAccountSnapshot, field func(models.IGAccountSnapshot) int64) int64 {
+		if len(list) == 0 {
+			return 0
+		}
+		base := list[0]
+		if len(before) > 0 {
+			base = before[len(before)-1]
+		}
+		return field(list[len(list)-1]) - field(base)
+	}
+
+	followers := func(s models.IGAccountSnapshot) int64 { return s.Followers }
+	media := func(s models.IGAccountSnapshot) int64 { return s.MediaCount }
+
+	json.NewEncoder(w).Encode(models.AccountGrowthReport{
+		AccountID:       creds.AccountID,
+		Days:            days,
+		FollowersGained: newMetricDelta(growth(current, previous, followers), growth(previous, nil, followers)),
+		MediaPublished:  newMetricDelta(growth(current, previous, media), growth(previous, nil, media)),
+		Reach: newMetricDelta(
+			sum(current, func(s models.IGAccountSnapshot) int64 { return s.Reach }),
+			sum(previous, func(s models.IGAccountSnapshot) int64 { return s.Reach }),
+		),
+		Impressions: newMetricDelta(
+			sum(current, func(s models.IGAccountSnapshot) int64 { return s.Impressions }),
+			sum(previous, func(s models.IGAccountSnapshot) int64 { return s.Impressions }),
+		),
+		ProfileViews: newMetricDelta(
+			sum(current, func(s models.IGAccountSnapshot) int64 { return s.ProfileViews }),
+			sum(previous, func(s models.IGAccountSnapshot) int64 { return s.ProfileViews }),
+		),
+		WebsiteClicks: newMetricDelta(
+			sum(current, func(s models.IGAccountSnapshot) int64 { return s.WebsiteClicks }),
+			sum(previous, func(s models.IGAccountSnapshot) int64 { return s.WebsiteClicks }),
+		),
+		Series: current,
+	})
+}
+
+func newMetricDelta(current, previous int64) models.MetricDelta {
+	d := models.MetricDelta{Current: current, Previous: previous, Change: current - previous}
+	if previous != 0 {
+		pct := float64(current-previous) / float64(previous) * 100
+		d.ChangePct = &pct
+	}
+	return d
+}
+
+// ─── Snapshot job ────────────────────────────────────────────────────
+
+// CaptureAccountSnapshots stores today's snapshot for every connected Instagram
+// account: the current counters plus yesterday's daily insights. Accounts already
+// captured today are skipped, so the job can run several times a day.
+func CaptureAccountSnapshots() {
+	if !crypto.Available() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	date := today.Format("2006-01-02")
+
+	cursor, err := database.InstagramConfigs().Find(ctx, bson.M{})
+	if err != nil {
+		slog.Error("account_snapshots_configs", "error", err)
+		return
+	}
+	var cfgs []models.InstagramConfig
+	cursor.All(ctx, &cfgs)
+	cursor.Close(ctx)
+
+	count := 0
+	for _, cfg := range cfgs {
+		exists, _ := database.IGAccountSnapshots().CountDocuments(ctx, bson.M{
+			"org_id":     cfg.OrgID,
+			"account_id": cfg.InstagramAccountID,
+			"date":       date,
+		})
+		if exists > 0 {
+			continue
+		}
+
+		token, err := crypto.Decrypt(cfg.AccessTokenEnc)
+		if err != nil {
+			slog.Warn("account_snapshots_decrypt", "account", maskAccountID(cfg.InstagramAccountID), "error", err)
+			continue
+		}
+
+		snap, err := fetchAccountSnapshot(cfg.InstagramAccountID, token, yesterday, today)
+		if err != nil {
+			slog.Warn("account_snapshots_fetch", "account", maskAccountID(cfg.InstagramAccountID), "error", err)
+			continue
+		}
+		snap.OrgID = cfg.OrgID
+		snap.AccountID = cfg.InstagramAccountID
+		snap.Date = date
+		snap.CapturedAt = time.Now()
+
+		_, err = database.IGAccountSnapshots().UpdateOne(ctx,
+			bson.M{"org_id": cfg.OrgID, "account_id": cfg.InstagramAccountID, "date": date},
+			bson.M{"$setOnInsert": snap},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			slog.Error("account_snapshots_upsert", "account", maskAccountID(cfg.InstagramAccountID), "error", err)
+			continue
+		}
+		count++
+	}
+
+	if count > 0 {
+		slog.Info("account_snapshots_cycle_complete", "date", date, "accounts", count)
+	}
+}
+
+// fetchAccountSnapshot reads the account counters and its insights for [since, until).
+// If the combined insights request is rejected, metrics are requested one by one.
+func fetchAccountSnapshot(accountID, token string, since, until time.Time) (models.IGAccountSnapshot, error) {
+	var snap models.IGAccountSnapshot
+
+	fieldParams := url.Values{}
+	fieldParams.Set("fields", "username,followers_count,follows_count,media_count")
+	profile, err := metaGraphGet("/"+accountID, token, fieldParams)
+	if err != nil {
+		return snap, err
+	}
+	snap.Username, _ = profile["username"].(string)
+	followers, _ := profile["followers_count"].(float64)
+	follows, _ := profile["follows_count"].(float64)
+	mediaCount, _ := profile["media_count"].(float64)
+	snap.Followers = int64(followers)
+	snap.Follows = int64(follows)
+	snap.MediaCount = int64(mediaCount)
+
+	values, err := fetchAccountInsightMetrics(accountID, token, accountInsightMetrics, since, until)
+	if err != nil {
+		values = map[string]int64{}
+		for _, m := range accountInsightMetrics {
+			single, err := fetchAccountInsightMetrics(accountID, token, []string{m}, since, until)
+			if err != nil {
+				continue
+			}
+			values[m] = single[m]
+		}
+	}
+
+	snap.Reach = values["reach"]
+	snap.Impressions = values["impressions"]
+	snap.ProfileViews = values["profile_views"]
+	snap.WebsiteClicks = values["website_clicks"]
+	return snap, nil
+}
+
+// fetchAccountInsightMetrics calls /{account}/insights for a single day and returns metric name -> value.
+func fetchAccountInsightMetrics(accountID, token string, metrics []string, since, until time.Time) (map[string]int64, error) {
+	params := url.Values{}
+	params.Set("metric", strings.Join(metrics, ","))
+	params.Set("period", "day")
+	params.Set("metric_type", "total_value")
+	params.Set("since", strconv.FormatInt(since.Unix(), 10))
+	params.Set("until", strconv.FormatInt(until.Unix(), 10))
+	result, err := metaGraphGet("/"+accountID+"/insights", token, params)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]int64{}
+	data, _ := result["data"].([]interface{})
+	for _, item := range data {
+		metric, _ := item.(map[string]interface{})
+		name, _ := metric["name"].(string)
+		if total, ok := metric["total_value"].(map[string]interface{}); ok {
+			v, _ := total["value"].(float64)
+			values[name] = int64(v)
+			continue
+		}
+		vals, _ := metric["values"].([]interface{})
+		for _, val := range vals {
+			entry, _ := val.(map[string]interface{})
+			v, _ := entry["value"].(float64)
+			values[name] += int64(v)
+		}
+	}
+	return values, nil
+}
@@ -18,7 +18,7 @@ import (
 
 // CreateAutoReplyRule creates a new auto-reply rule.
 // @Summary Criar regra de auto-resposta
-// @Description Cria uma nova regra de auto-resposta para Instagram
+// @Description Cria uma nova regra de auto-resposta para Instagram e/ou Facebook (canal)
 // @Tags instagram-autoreply
 // @Accept json
 // @Produce json
@@ -44,12 +44,11 @@ func CreateAutoReplyRule(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, `{"message":"Nome é obrigatório"}`, http.StatusBadRequest)
 		return
 	}
-	if req.TriggerType != "comment" && req.TriggerType != "dm" && req.TriggerType != "both" {
-		http.Error(w, `{"message":"Tipo de trigger inválido (comment, dm, both)"}`, http.StatusBadRequest)
-		return
+	if req.Channel == "" {
+		req.Channel = "instagram"
 	}
-	if len(req.Keywords) == 0 {
-		http.Error(w, `{"message":"Pelo menos uma keyword é obrigatória"}`, http.StatusBadRequest)
+	if msg := validateAutoReplyRule(req.TriggerType, req.Channel, req.Keywords); msg != "" {
+		http.Error(w, `{"message":"`+msg+`"}`, http.StatusBadRequest)
 		return
 	}
 	if req.ResponseMessage == "" {
@@ -63,6 +62,7 @@ func CreateAutoReplyRule(w http.ResponseWriter, r *http.Request) {
 		OrgID:           orgID,
 		Name:            req.Name,
 		TriggerType:     req.TriggerType,
+		Channel:         req.Channel,
 		Keywords:        req.Keywords,
 		ResponseMessage: req.ResponseMessage,
 		CommentReply:    req.CommentReply,
@@ -170,20 +170,42 @@ func UpdateAutoReplyRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": ruleID, "org_id": orgID}
+	var rule models.AutoReplyRule
+	if err := database.AutoReplyRules().FindOne(ctx, filter).Decode(&rule); err != nil {
+		http.Error(w, `{"message":"Regra não encontrada"}`, http.StatusNotFound)
+		return
+	}
+
 	update := bson.M{"updated_at": time.Now()}
 	if req.Name != nil {
 		update["name"] = *req.Name
 	}
 	if req.TriggerType != nil {
-		if *req.TriggerType != "comment" && *req.TriggerType != "dm" && *req.TriggerType != "both" {
-			http.Error(w, `{"message":"Tipo de trigger inválido"}`, http.StatusBadRequest)
-			return
-		}
+		rule.TriggerType = *req.TriggerType
 		update["trigger_type"] = *req.TriggerType
 	}
+	if req.Channel != nil {
+		rule.Channel = *req.Channel
+		update["channel"] = *req.Channel
+	}
 	if req.Keywords != nil {
+		rule.Keywords = req.Keywords
 		update["keywords"] = req.Keywords
 	}
+
+	// Validate the rule as it will be stored (rules from before Facebook support have no channel)
+	channel := rule.Channel
+	if channel == "" {
+		channel = "instagram"
+	}
+	if msg := validateAutoReplyRule(rule.TriggerType, channel, rule.Keywords); msg != "" {
+		http.Error(w, `{"message":"`+msg+`"}`, http.StatusBadRequest)
+		return
+	}
 	if req.ResponseMessage != nil {
 		update["response_message"] = *req.ResponseMessage
 	}
@@ -194,10 +216,6 @@ func UpdateAutoReplyRule(w http.ResponseWriter, r *http.Request) {
 		update["post_ids"] = req.PostIDs
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	filter := bson.M{"_id": ruleID, "org_id": orgID}
 	result, err := database.AutoReplyRules().UpdateOne(ctx, filter, bson.M{"$set": update})
 	if err != nil {
 		slog.Error("update_autoreply_rule_error", "error", err)
@@ -320,6 +338,7 @@ func DeleteAutoReplyRule(w http.ResponseWriter, r *http.Request) {
 // @Param status query string false "Filtrar por status"
 // @Param rule_id query string false "Filtrar por ID da regra"
 // @Param trigger_type query string false "Filtrar por tipo de trigger"
+// @Param channel query string false "Filtrar por canal (instagram, facebook)"
 // @Success 200 {object} models.AutoReplyLogListResponse
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Erro ao listar logs"
@@ -353,6 +372,12 @@ func ListAutoReplyLogs(w http.ResponseWriter, r *http.Request) {
 	if triggerType := r.URL.Query().Get("trigger_type"); triggerType != "" {
 		filter["trigger_type"] = triggerType
 	}
+	switch channel := r.URL.Query().Get("channel"); channel {
+	case "instagram":
+		filter["channel"] = bson.M{"$in": []interface{}{nil, "instagram"}} // logs before Facebook support have no channel
+	case "facebook":
+		filter["channel"] = channel
+	}
 
 	total, err := database.AutoReplyLogs().CountDocuments(ctx, filter)
 	if err != nil {
@@ -394,3 +419,30 @@ func ListAutoReplyLogs(w http.ResponseWriter, r *http.Request) {
 		Limit: limit,
 	})
 }
+
+// validTriggerType reports whether t is a supported auto-reply trigger type.
+func validTriggerType(t string) bool {
+	return t == "comment" || t == "dm" || t == "both" || t == "mention"
+}
+
+// validRuleChannel reports whether c is a supported auto-reply channel.
+func validRuleChannel(c string) bool {
+	return c == "instagram" || c == "facebook" || c == "all"
+}
+
+// validateAutoReplyRule checks a rule's trigger, channel and keywords together
+// and returns the error message, or "" when valid.
+func validateAutoReplyRule(triggerType, channel string, keywords []string) string {
+	switch {
+	case !validTriggerType(triggerType):
+		return "Tipo de trigger inválido (comment, dm, both, mention)"
+	case !validRuleChannel(channel):
+		return "Canal inválido (instagram, facebook, all)"
+	case triggerType == "mention" && channel != "instagram":
+		return "Regras de menção são apenas para o Instagram"
+	case len(keywords) == 0 && triggerType != "mention":
+		// Mention rules may omit keywords to answer every mention (story mentions carry no text)
+		return "Pelo menos uma keyword é obrigatória"
+	}
+	return ""
+}
@@ -0,0 +1,680 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxCompetitorsPerOrg  = 10
+	competitorRecentPosts = 12
+	// viralPostMaxAge limits viral alerts to fresh posts.
+	viralPostMaxAge = 7 * 24 * time.Hour
+)
+
+var igUsernameRe = regexp.MustCompile(`^[A-Za-z0-9._]{1,30}$`)
+
+// businessDiscoveryProfile is a public account as returned by the Business Discovery API.
+type businessDiscoveryProfile struct {
+	Username          string
+	Name              string
+	ProfilePictureURL string
+	Snapshot          models.CompetitorSnapshot
+}
+
+// ListCompetitors returns the org's tracked competitors.
+// @Summary Listar concorrentes
+// @Description Lista as contas concorrentes monitoradas pela organização
+// @Tags instagram-competitors
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Competitor
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/competitors [get]
+func ListCompetitors(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "username", Value: 1}})
+	cursor, err := database.Competitors().Find(ctx, bson.M{"org_id": orgID}, opts)
+	if err != nil {
+		http.Error(w, "Error listing competitors", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var competitors []models.Competitor
+	cursor.All(ctx, &competitors)
+	if competitors == nil {
+		competitors = []models.Competitor{}
+	}
+	json.NewEncoder(w).Encode(competitors)
+}
+
+// CreateCompetitor starts tracking a public account and stores its first snapshot.
+// @Summary Adicionar concorrente
+// @Description Passa a monitorar uma conta pública (Business ou Creator) via Business Discovery e salva o primeiro snapshot
+// @Tags instagram-competitors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateCompetitorRequest true "Username e alertas"
+// @Success 201 {object} models.Competitor
+// @Failure 400 {string} string "Invalid username"
+// @Failure 409 {string} string "Competitor already tracked"
+// @Failure 502 {string} string "Business Discovery error"
+// @Router /admin/instagram/competitors [post]
+func CreateCompetitor(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+
+	var req models.CreateCompetitorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	username := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(req.Username), "@"))
+	if !igUsernameRe.MatchString(username) {
+		http.Error(w, "Invalid username", http.StatusBadRequest)
+		return
+	}
+	if req.Alerts.FollowerSpikePct < 0 || req.Alerts.ViralMultiplier < 0 {
+		http.Error(w, "Alert thresholds must be >= 0", http.StatusBadRequest)
+		return
+	}
+
+	_, creds, ok := requireInstagramCreds(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	count, _ := database.Competitors().CountDocuments(ctx, bson.M{"org_id": orgID})
+	if count >= maxCompetitorsPerOrg {
+		http.Error(w, fmt.Sprintf("Competitor limit reached (%d)", maxCompetitorsPerOrg), http.StatusBadRequest)
+		return
+	}
+
+	profile, err := fetchBusinessDiscovery(creds.AccountID, creds.Token, username)
+	if err != nil {
+		http.Error(w, "Business Discovery error (the account must be a public Business or Creator account): "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	now := time.Now()
+	competitor := models.Competitor{
+		ID:                primitive.NewObjectID(),
+		OrgID:             orgID,
+		Username:          username,
+		Name:              profile.Name,
+		ProfilePictureURL: profile.ProfilePictureURL,
+		Alerts:            req.Alerts,
+		LastSnapshotAt:    &now,
+		CreatedBy:         userID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if _, err := database.Competitors().InsertOne(ctx, competitor); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			http.Error(w, "Competitor already tracked", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error saving competitor", http.StatusInternalServerError)
+		return
+	}
+
+	storeCompetitorSnapshot(ctx, competitor, profile.Snapshot)
+
+	slog.Info("competitor_created", "org_id", orgID.Hex(), "username", username)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(competitor)
+}
+
+// UpdateCompetitor updates a competitor's alert configuration.
+// @Summary Atualizar alertas do concorrente
+// @Description Configura os alertas de pico de seguidores e post viral
+// @Tags instagram-competitors
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do concorrente"
+// @Param body body models.UpdateCompetitorRequest true "Alertas"
+// @Success 200 {object} models.Competitor
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Competitor not found"
+// @Router /admin/instagram/competitors/{id} [put]
+func UpdateCompetitor(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	competitor, ok := findCompetitor(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCompetitorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Alerts != nil {
+		if req.Alerts.FollowerSpikePct < 0 || req.Alerts.ViralMultiplier < 0 {
+			http.Error(w, "Alert thresholds must be >= 0", http.StatusBadRequest)
+			return
+		}
+		competitor.Alerts = *req.Alerts
+	}
+	competitor.UpdatedAt = time.Now()
+
+	_, err := database.Competitors().UpdateOne(ctx, bson.M{"_id": competitor.ID}, bson.M{"$set": bson.M{
+		"alerts":     competitor.Alerts,
+		"updated_at": competitor.UpdatedAt,
+	}})
+	if err != nil {
+		http.Error(w, "Error updating competitor", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(competitor)
+}
+
+// DeleteCompetitor stops tracking a competitor and removes its history.
+// @Summary Remover concorrente
+// @Description Para de monitorar o concorrente e remove o histórico
+// @Tags instagram-competitors
+// @Security BearerAuth
+// @Param id path string true "ID do concorrente"
+// @Success 204
+// @Failure 404 {string} string "Competitor not found"
+// @Router /admin/instagram/competitors/{id} [delete]
+func DeleteCompetitor(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	competitor, ok := findCompetitor(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := database.Competitors().DeleteOne(ctx, bson.M{"_id": competitor.ID}); err != nil {
+		http.Error(w, "Error deleting competitor", http.StatusInternalServerError)
+		return
+	}
+	database.CompetitorSnapshots().DeleteMany(ctx, bson.M{"competitor_id": competitor.ID})
+	database.CompetitorAlerts().DeleteMany(ctx, bson.M{"competitor_id": competitor.ID})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CompareCompetitors returns the org's account side by side with its competitors.
+// @Summary Comparar com concorrentes
+// @Description Compara seguidores, posts, médias de curtidas/comentários e taxa de engajamento da conta da organização com os concorrentes
+// @Tags instagram-competitors
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.CompetitorComparisonResponse
+// @Failure 400 {string} string "Instagram not configured"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/competitors/compare [get]
+func CompareCompetitors(w http.ResponseWriter, r *http.Request) {
+	_, creds, ok := requireInstagramCreds(w, r)
+	if !ok {
+		return
+	}
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	weekAgo := time.Now().UTC().AddDate(0, 0, -7).Format("2006-01-02")
+	accounts := []models.CompetitorSummary{}
+
+	// Own account: live, through Business Discovery so metrics are computed the same way
+	ownParams := url.Values{}
+	ownParams.Set("fields", "username")
+	if own, err := metaGraphGet("/"+creds.AccountID, creds.Token, ownParams); err == nil {
+		if username, _ := own["username"].(string); username != "" {
+			if profile, err := fetchBusinessDiscovery(creds.AccountID, creds.Token, username); err == nil {
+				summary := competitorSummary(profile.Snapshot)
+				summary.Username = username
+				summary.IsOwn = true
+				var past models.IGAccountSnapshot
+				err := database.IGAccountSnapshots().FindOne(ctx,
+					bson.M{"org_id": orgID, "account_id": creds.AccountID, "date": bson.M{"$lte": weekAgo}},
+					options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}),
+				).Decode(&past)
+				if err == nil {
+					change := profile.Snapshot.Followers - past.Followers
+					summary.FollowersChange = &change
+				}
+				accounts = append(accounts, summary)
+			} else {
+				slog.Warn("competitor_compare_own", "error", err)
+			}
+		}
+	}
+
+	cursor, err := database.Competitors().Find(ctx, bson.M{"org_id": orgID}, options.Find().SetSort(bson.D{{Key: "username", Value: 1}}))
+	if err != nil {
+		http.Error(w, "Error listing competitors", http.StatusInternalServerError)
+		return
+	}
+	var competitors []models.Competitor
+	cursor.All(ctx, &competitors)
+	cursor.Close(ctx)
+
+	for _, c := range competitors {
+		var latest models.CompetitorSnapshot
+		err := database.CompetitorSnapshots().FindOne(ctx,
+			bson.M{"competitor_id": c.ID},
+			options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}),
+		).Decode(&latest)
+		if err != nil {
+			continue
+		}
+
+		summary := competitorSummary(latest)
+		id := c.ID
+		summary.CompetitorID = &id
+		summary.Username = c.Username
+		captured := latest.CapturedAt
+		summary.CapturedAt = &captured
+
+		var past models.CompetitorSnapshot
+		err = database.CompetitorSnapshots().FindOne(ctx,
+			bson.M{"competitor_id": c.ID, "date": bson.M{"$lte": weekAgo}},
+			options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}),
+		).Decode(&past)
+		if err == nil {
+			change := latest.Followers - past.Followers
+			summary.FollowersChange = &change
+		}
+		accounts = append(accounts, summary)
+	}
+
+	json.NewEncoder(w).Encode(models.CompetitorComparisonResponse{Accounts: accounts})
+}
+
+// GetCompetitorTrends returns a competitor's daily snapshots.
+// @Summary Histórico do concorrente
+// @Description Retorna os snapshots diários do concorrente para gráficos de tendência
+// @Tags instagram-competitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do concorrente"
+// @Param days query int false "Período em dias (padrão 30, máx 365)"
+// @Success 200 {object} models.CompetitorTrendResponse
+// @Failure 404 {string} string "Competitor not found"
+// @Router /admin/instagram/competitors/{id}/trends [get]
+func GetCompetitorTrends(w http.ResponseWriter, r *http.Request) {
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days < 1 || days > 365 {
+		days = 30
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	competitor, ok := findCompetitor(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02")
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: 1}}).
+		SetProjection(bson.M{"recent_posts": 0})
+	cursor, err := database.CompetitorSnapshots().Find(ctx, bson.M{"competitor_id": competitor.ID, "date": bson.M{"$gte": since}}, opts)
+	if err != nil {
+		http.Error(w, "Error loading snapshots", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var snapshots []models.CompetitorSnapshot
+	cursor.All(ctx, &snapshots)
+	if snapshots == nil {
+		snapshots = []models.CompetitorSnapshot{}
+	}
+
+	json.NewEncoder(w).Encode(models.CompetitorTrendResponse{
+		Competitor: competitor,
+		Days:       days,
+		Snapshots:  snapshots,
+	})
+}
+
+// ListCompetitorAlerts returns fired competitor alerts, newest first.
+// @Summary Listar alertas de concorrentes
+// @Description Lista os alertas disparados de pico de seguidores e post viral
+// @Tags instagram-competitors
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} models.CompetitorAlertListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/competitors/alerts [get]
+func ListCompetitorAlerts(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"org_id": orgID}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.CompetitorAlerts().CountDocuments(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error listing alerts", http.StatusInternalServerError)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := database.CompetitorAlerts().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error listing alerts", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var alerts []models.CompetitorAlert
+	cursor.All(ctx, &alerts)
+	if alerts == nil {
+		alerts = []models.CompetitorAlert{}
+	}
+
+	json.NewEncoder(w).Encode(models.CompetitorAlertListResponse{
+		Alerts: alerts,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	})
+}
+
+// findCompetitor loads the competitor from the {id} path value, scoped to the caller's org.
+func findCompetitor(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.Competitor, bool) {
+	var competitor models.Competitor
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return competitor, false
+	}
+
+	err = database.Competitors().FindOne(ctx, bson.M{"_id": id, "org_id": middleware.GetOrgID(r)}).Decode(&competitor)
+	if err == mongo.ErrNoDocuments {
+		http.Error(w, "Competitor not found", http.StatusNotFound)
+		return competitor, false
+	}
+	if err != nil {
+		http.Error(w, "Error loading competitor", http.StatusInternalServerError)
+		return competitor, false
+	}
+	return competitor, true
+}
+
+func competitorSummary(s models.CompetitorSnapshot) models.CompetitorSummary {
+	return models.CompetitorSummary{
+		Followers:      s.Followers,
+		MediaCount:     s.MediaCount,
+		AvgLikes:       s.AvgLikes,
+		AvgComments:    s.AvgComments,
+		EngagementRate: s.EngagementRate,
+	}
+}
+
+// ─── Business Discovery ──────────────────────────────────────────────
+
+// fetchBusinessDiscovery reads a public account's profile and recent posts through the
+// org's own IG account. Only Business and Creator accounts are discoverable.
+func fetchBusinessDiscovery(accountID, token, username string) (businessDiscoveryProfile, error) {
+	var profile businessDiscoveryProfile
+
+	params := url.Values{}
+	params.Set("fields", fmt.Sprintf(
+		"business_discovery.username(%s){username,name,profile_picture_url,followers_count,follows_count,media_count,media.limit(%d){id,caption,media_type,permalink,like_count,comments_count,timestamp}}",
+		username, competitorRecentPosts,
+	))
+	result, err := metaGraphGet("/"+accountID, token, params)
+	if err != nil {
+		return profile, err
+	}
+
+	bd, ok := result["business_discovery"].(map[string]interface{})
+	if !ok {
+		return profile, fmt.Errorf("account not found")
+	}
+	profile.Username, _ = bd["username"].(string)
+	profile.Name, _ = bd["name"].(string)
+	profile.ProfilePictureURL, _ = bd["profile_picture_url"].(string)
+
+	snap := &profile.Snapshot
+	followers, _ := bd["followers_count"].(float64)
+	follows, _ := bd["follows_count"].(float64)
+	mediaCount, _ := bd["media_count"].(float64)
+	snap.Followers = int64(followers)
+	snap.Follows = int64(follows)
+	snap.MediaCount = int64(mediaCount)
+
+	media, _ := bd["media"].(map[string]interface{})
+	data, _ := media["data"].([]interface{})
+	var totalLikes, totalComments int64
+	for _, item := range data {
+		m, _ := item.(map[string]interface{})
+		post := models.CompetitorPost{}
+		post.ID, _ = m["id"].(string)
+		post.Caption, _ = m["caption"].(string)
+		post.MediaType, _ = m["media_type"].(string)
+		post.Permalink, _ = m["permalink"].(string)
+		post.Timestamp, _ = m["timestamp"].(string)
+		likes, _ := m["like_count"].(float64) // hidden like counts come back absent
+		comments, _ := m["comments_count"].(float64)
+		post.LikeCount = int64(likes)
+		post.CommentsCount = int64(comments)
+		totalLikes += post.LikeCount
+		totalComments += post.CommentsCount
+		snap.RecentPosts = append(snap.RecentPosts, post)
+	}
+
+	if n := len(snap.RecentPosts); n > 0 {
+		snap.AvgLikes = float64(totalLikes) / float64(n)
+		snap.AvgComments = float64(totalComments) / float64(n)
+	}
+	if snap.Followers > 0 {
+		snap.EngagementRate = (snap.AvgLikes + snap.AvgComments) / float64(snap.Followers) * 100
+	}
+	return profile, nil
+}
+
+// storeCompetitorSnapshot upserts today's snapshot for a competitor.
+func storeCompetitorSnapshot(ctx context.Context, c models.Competitor, snap models.CompetitorSnapshot) {
+	snap.OrgID = c.OrgID
+	snap.CompetitorID = c.ID
+	snap.Date = time.Now().UTC().Format("2006-01-02")
+	snap.CapturedAt = time.Now()
+	if snap.RecentPosts == nil {
+		snap.RecentPosts = []models.CompetitorPost{}
+	}
+
+	_, err := database.CompetitorSnapshots().UpdateOne(ctx,
+		bson.M{"competitor_id": c.ID, "date": snap.Date},
+		bson.M{"$set": snap},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		slog.Error("competitor_snapshot_upsert", "competitor_id", c.ID.Hex(), "error", err)
+	}
+}
+
+// ─── Snapshot job ────────────────────────────────────────────────────
+
+// SnapshotCompetitors refreshes every competitor not captured today and evaluates its alerts.
+func SnapshotCompetitors() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	cursor, err := database.Competitors().Find(ctx, bson.M{
+		"$or": []bson.M{
+			{"last_snapshot_at": bson.M{"$exists": false}},
+			{"last_snapshot_at": bson.M{"$lt": today}},
+		},
+	})
+	if err != nil {
+		slog.Error("competitor_snapshot_find", "error", err)
+		return
+	}
+	var competitors []models.Competitor
+	cursor.All(ctx, &competitors)
+	cursor.Close(ctx)
+
+	credsCache := map[primitive.ObjectID]*instagramCredentials{}
+	count := 0
+	for _, c := range competitors {
+		creds, ok := credsCache[c.OrgID]
+		if !ok {
+			creds, err = getInstagramCredentials(ctx, c.CreatedBy, c.OrgID)
+			if err != nil {
+				slog.Warn("competitor_snapshot_creds", "org_id", c.OrgID.Hex(), "error", err)
+			}
+			credsCache[c.OrgID] = creds
+		}
+		if creds == nil {
+			continue
+		}
+
+		now := time.Now()
+		profile, err := fetchBusinessDiscovery(creds.AccountID, creds.Token, c.Username)
+		if err != nil {
+			slog.Warn("competitor_snapshot_fetch", "username", c.Username, "error", err)
+			database.Competitors().UpdateOne(ctx, bson.M{"_id": c.ID}, bson.M{"$set": bson.M{
+				"last_error": err.Error(), "updated_at": now,
+			}})
+			continue
+		}
+
+		var previous models.CompetitorSnapshot
+		hasPrevious := database.CompetitorSnapshots().FindOne(ctx,
+			bson.M{"competitor_id": c.ID},
+			options.FindOne().SetSort(bson.D{{Key: "date", Value: -1}}),
+		).Decode(&previous) == nil
+
+		storeCompetitorSnapshot(ctx, c, profile.Snapshot)
+		database.Competitors().UpdateOne(ctx, bson.M{"_id": c.ID}, bson.M{
+			"$set": bson.M{
+				"name":                profile.Name,
+				"profile_picture_url": profile.ProfilePictureURL,
+				"last_snapshot_at":    now,
+				"updated_at":          now,
+			},
+			"$unset": bson.M{"last_error": ""},
+		})
+
+		if hasPrevious {
+			evaluateCompetitorAlerts(ctx, c, previous, profile.Snapshot)
+		}
+		count++
+	}
+
+	if count > 0 {
+		slog.Info("competitor_snapshot_cycle_complete", "competitors", count)
+	}
+}
+
+// evaluateCompetitorAlerts fires follower-spike and viral-post alerts for a new snapshot.
+func evaluateCompetitorAlerts(ctx context.Context, c models.Competitor, previous, current models.CompetitorSnapshot) {
+	if c.Alerts.FollowerSpikePct > 0 && previous.Followers > 0 {
+		pct := float64(current.Followers-previous.Followers) / float64(previous.Followers) * 100
+		if pct >= c.Alerts.FollowerSpikePct {
+			fireCompetitorAlert(ctx, models.CompetitorAlert{
+				OrgID:        c.OrgID,
+				CompetitorID: c.ID,
+				Username:     c.Username,
+				Type:         "follower_spike",
+				Message:      fmt.Sprintf("@%s gained %d followers (+%.1f%%) since %s", c.Username, current.Followers-previous.Followers, pct, previous.Date),
+				Value:        pct,
+				Threshold:    c.Alerts.FollowerSpikePct,
+			})
+		}
+	}
+
+	avg := current.AvgLikes + current.AvgComments
+	if c.Alerts.ViralMultiplier > 0 && avg > 0 {
+		for _, post := range current.RecentPosts {
+			ts, err := time.Parse("2006-01-02T15:04:05-0700", post.Timestamp)
+			if err != nil || time.Since(ts) > viralPostMaxAge {
+				continue
+			}
+			engagement := float64(post.LikeCount + post.CommentsCount)
+			ratio := engagement / avg
+			if ratio < c.Alerts.ViralMultiplier {
+				continue
+			}
+			// One viral alert per post
+			exists, _ := database.CompetitorAlerts().CountDocuments(ctx, bson.M{
+				"competitor_id": c.ID, "type": "viral_post", "media_id": post.ID,
+			})
+			if exists > 0 {
+				continue
+			}
+			fireCompetitorAlert(ctx, models.CompetitorAlert{
+				OrgID:        c.OrgID,
+				CompetitorID: c.ID,
+				Username:     c.Username,
+				Type:         "viral_post",
+				Message:      fmt.Sprintf("@%s post has %.1fx their average engagement (%d likes, %d comments)", c.Username, ratio, post.LikeCount, post.CommentsCount),
+				Value:        ratio,
+				Threshold:    c.Alerts.ViralMultiplier,
+				MediaID:      post.ID,
+				Permalink:    post.Permalink,
+			})
+		}
+	}
+}
+
+func fireCompetitorAlert(ctx context.Context, alert models.CompetitorAlert) {
+	alert.ID = primitive.NewObjectID()
+	alert.CreatedAt = time.Now()
+	if _, err := database.CompetitorAlerts().InsertOne(ctx, alert); err != nil {
+		slog.Error("competitor_alert_insert", "error", err)
+		return
+	}
+	slog.Warn("competitor_alert_triggered",
+		"org_id", alert.OrgID.Hex(),
+		"username", alert.Username,
+		"type", alert.Type,
+		"value", alert.Value,
+		"threshold", alert.Threshold,
+	)
+}
@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/image/draw"
+)
+
+// instagramAspectRatios maps the supported target ratios to width/height. Only
+// feed ratios (4:5 to 1.91:1) are offered: there is no stories or reels path.
+var instagramAspectRatios = map[string]float64{
+	"1:1":    1,
+	"4:5":    0.8,
+	"1.91:1": 1.91,
+}
+
+const (
+	instagramMaxWidth = 1080
+	// originalMaxSide bounds the stored source image so it fits comfortably in a document.
+	originalMaxSide = 2160
+)
+
+// normalizeImageCrop validates a crop request and fills in defaults.
+// Returns an error message, or "" when valid.
+func normalizeImageCrop(req models.ImageCropRequest) (models.ImageCrop, string) {
+	crop := models.ImageCrop{
+		AspectRatio: req.AspectRatio,
+		Mode:        req.Mode,
+		FocalX:      0.5,
+		FocalY:      0.5,
+		Background:  strings.ToUpper(req.Background),
+	}
+	if _, ok := instagramAspectRatios[crop.AspectRatio]; !ok {
+		return crop, "aspect_ratio must be one of 1:1, 4:5, 1.91:1"
+	}
+	if crop.Mode == "" {
+		crop.Mode = "crop"
+	}
+	if crop.Mode != "crop" && crop.Mode != "letterbox" {
+		return crop, "mode must be 'crop' or 'letterbox'"
+	}
+	if req.FocalX != nil {
+		crop.FocalX = *req.FocalX
+	}
+	if req.FocalY != nil {
+		crop.FocalY = *req.FocalY
+	}
+	if crop.FocalX < 0 || crop.FocalX > 1 || crop.FocalY < 0 || crop.FocalY > 1 {
+		return crop, "focal_x and focal_y must be between 0 and 1"
+	}
+	if crop.Mode == "letterbox" {
+		if crop.Background == "" {
+			crop.Background = "#FFFFFF"
+		}
+		if !strings.HasPrefix(crop.Background, "#") {
+			crop.Background = "#" + crop.Background
+		}
+		if _, err := parseHexColor(crop.Background); err != nil {
+			return crop, "background must be a #RRGGBB color"
+		}
+	} else {
+		crop.Background = ""
+	}
+	return crop, ""
+}
+
+// cropRequestFromForm reads the optional crop fields of the upload form.
+// Returns nil when no aspect_ratio was given.
+func cropRequestFromForm(r *http.Request) (*models.ImageCropRequest, string) {
+	ratio := r.FormValue("aspect_ratio")
+	if ratio == "" {
+		return nil, ""
+	}
+	req := &models.ImageCropRequest{
+		AspectRatio: ratio,
+		Mode:        r.FormValue("mode"),
+		Background:  r.FormValue("background"),
+	}
+	for field, dst := range map[string]**float64{"focal_x": &req.FocalX, "focal_y": &req.FocalY} {
+		raw := r.FormValue(field)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, field + " must be a number between 0 and 1"
+		}
+		*dst = &v
+	}
+	return req, ""
+}
+
+// applyImageCrop fits img to the crop's aspect ratio and scales it down to maxWidth.
+// In crop mode the kept window is centered on the focal point, clamped to the image;
+// in letterbox mode the whole image is kept and padded with the background color.
+func applyImageCrop(img image.Image, crop models.ImageCrop, maxWidth int) image.Image {
+	target := instagramAspectRatios[crop.AspectRatio]
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	ratio := float64(srcW) / float64(srcH)
+
+	if crop.Mode == "letterbox" {
+		canvasW := srcW
+		if ratio < target {
+			canvasW = int(float64(srcH) * target)
+		}
+		if canvasW > maxWidth {
+			canvasW = maxWidth
+		}
+		canvasH := int(float64(canvasW) / target)
+
+		contentW, contentH := canvasW, int(float64(canvasW)/ratio)
+		if ratio < target {
+			contentW, contentH = int(float64(canvasH)*ratio), canvasH
+		}
+		left := (canvasW - contentW) / 2
+		top := (canvasH - contentH) / 2
+
+		bg, _ := parseHexColor(crop.Background)
+		dst := image.NewRGBA(image.Rect(0, 0, canvasW, canvasH))
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+		draw.CatmullRom.Scale(dst, image.Rect(left, top, left+contentW, top+contentH), img, bounds, draw.Over, nil)
+		return dst
+	}
+
+	cropW, cropH := srcW, srcH
+	if ratio > target {
+		cropW = int(float64(srcH) * target)
+	} else {
+		cropH = int(float64(srcW) / target)
+	}
+	left := clampInt(int(crop.FocalX*float64(srcW))-cropW/2, 0, srcW-cropW)
+	top := clampInt(int(crop.FocalY*float64(srcH))-cropH/2, 0, srcH-cropH)
+	cropRect := image.Rect(bounds.Min.X+left, bounds.Min.Y+top, bounds.Min.X+left+cropW, bounds.Min.Y+top+cropH)
+
+	outW := cropW
+	if outW > maxWidth {
+		outW = maxWidth
+	}
+	outH := int(float64(outW) / target)
+
+	dst := image.NewRGBA(image.Rect(0, 0, outW, outH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, cropRect, draw.Over, nil)
+	return dst
+}
+
+// limitImageSize scales img down so its longest side is at most maxSide.
+func limitImageSize(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	if w >= h {
+		w, h = maxSide, int(float64(h)*float64(maxSide)/float64(w))
+	} else {
+		w, h = int(float64(w)*float64(maxSide)/float64(h)), maxSide
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func parseHexColor(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color")
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+// encodeJPEGBase64 encodes img as JPEG and returns its base64 form and byte size.
+func encodeJPEGBase64(img image.Image, quality int) (string, int, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return "", 0, err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), buf.Len(), nil
+}
+
+// UpdateInstagramImageCrop re-renders an uploaded image from its stored original with new crop settings.
+// Images are served as immutable and fetched by Meta by URL, so the crop is saved as a new
+// image and the org's unpublished posts and series are repointed to it.
+// @Summary Recortar imagem do Instagram
+// @Description Refaz o recorte de uma imagem enviada (proporção, ponto focal ou faixas de cor) a partir do original salvo, sem novo upload. O recorte gera uma nova imagem (novo ID) e os agendamentos ainda não publicados e as séries da organização passam a usá-la
+// @Tags instagram
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da imagem"
+// @Param body body models.ImageCropRequest true "Configuração do recorte"
+// @Success 200 {object} models.InstagramImageResponse
+// @Failure 400 {string} string "Invalid crop"
+// @Failure 404 {string} string "Image not found"
+// @Failure 409 {string} string "Image has no stored original"
+// @Router /admin/instagram/images/{id}/crop [put]
+func UpdateInstagramImageCrop(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid image ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ImageCropRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	crop, msg := normalizeImageCrop(req)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var imgDoc models.BlogImage
+	err = database.Images().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&imgDoc)
+	if err != nil {
+		http.Error(w, "Image not found", http.StatusNotFound)
+		return
+	}
+	if imgDoc.OriginalID == nil {
+		http.Error(w, "Image has no stored original; upload it again", http.StatusConflict)
+		return
+	}
+
+	var original models.BlogImage
+	if err := database.Images().FindOne(ctx, bson.M{"_id": *imgDoc.OriginalID}).Decode(&original); err != nil {
+		http.Error(w, "Image has no stored original; upload it again", http.StatusConflict)
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(original.Data)
+	if err != nil {
+		http.Error(w, "Error decoding original image", http.StatusInternalServerError)
+		return
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		http.Error(w, "Error decoding original image", http.StatusInternalServerError)
+		return
+	}
+
+	out := applyImageCrop(src, crop, instagramMaxWidth)
+	encoded, size, err := encodeJPEGBase64(out, 85)
+	if err != nil {
+		http.Error(w, "Failed to process image", http.StatusInternalServerError)
+		return
+	}
+	bounds := out.Bounds()
+
+	cropped := models.BlogImage{
+		ID:         primitive.NewObjectID(),
+		UploaderID: imgDoc.UploaderID,
+		OrgID:      imgDoc.OrgID,
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		OriginalID: imgDoc.OriginalID,
+		Crop:       &crop,
+		Data:       encoded,
+		Size:       size,
+		CreatedAt:  time.Now(),
+	}
+	if _, err := database.Images().InsertOne(ctx, cropped); err != nil {
+		http.Error(w, "Error saving image", http.StatusInternalServerError)
+		return
+	}
+	repointImage(ctx, orgID, oid.Hex(), cropped.ID.Hex())
+
+	slog.Info("instagram_image_recropped",
+		"image_id", oid.Hex(),
+		"new_image_id", cropped.ID.Hex(),
+		"aspect_ratio", crop.AspectRatio,
+		"mode", crop.Mode,
+		"width", bounds.Dx(),
+		"height", bounds.Dy(),
+	)
+
+	json.NewEncoder(w).Encode(models.InstagramImageResponse{
+		ID:     cropped.ID.Hex(),
+		URL:    "/api/v1/blog/images/" + cropped.ID.Hex(),
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+		Crop:   &crop,
+	})
+}
+
+// repointImage replaces oldID with newID in the org's posts that are not
+// published yet and in its series. Published posts keep the image they went out with.
+func repointImage(ctx context.Context, orgID primitive.ObjectID, oldID, newID string) {
+	unpublished := []string{"scheduled", "pending_approval", "rejected", "failed"}
+	targets := []struct {
+		coll     *mongo.Collection
+		statuses []string
+	}{
+		{database.InstagramSchedules(), unpublished},
+		{database.FacebookSchedules(), unpublished},
+		{database.ThreadsSchedules(), unpublished},
+		{database.IntegratedPublishes(), unpublished},
+		{database.QueuedPosts(), []string{"queued"}},
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{bson.M{"img": oldID}}})
+	for _, t := range targets {
+		_, err := t.coll.UpdateMany(ctx,
+			bson.M{"org_id": orgID, "image_ids": oldID, "status": bson.M{"$in": t.statuses}},
+			bson.M{"$set": bson.M{"image_ids.$[img]": newID}},
+			opts,
+		)
+		if err != nil {
+			slog.Error("image_repoint_error", "collection", t.coll.Name(), "image_id", oldID, "error", err)
+		}
+	}
+
+	// Series content and items
+	if _, err := database.PostSeries().UpdateMany(ctx,
+		bson.M{"org_id": orgID, "content.image_ids": oldID},
+		bson.M{"$set": bson.M{"content.image_ids.$[img]": newID}},
+		opts,
+	); err != nil {
+		slog.Error("image_repoint_error", "collection", "post_series", "image_id", oldID, "error", err)
+	}
+	itemOpts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"item.image_ids": oldID},
+		bson.M{"img": oldID},
+	}})
+	if _, err := database.PostSeries().UpdateMany(ctx,
+		bson.M{"org_id": orgID, "items.image_ids": oldID},
+		bson.M{"$set": bson.M{"items.$[item].image_ids.$[img]": newID}},
+		itemOpts,
+	); err != nil {
+		slog.Error("image_repoint_error", "collection", "post_series", "image_id", oldID, "error", err)
+	}
+}
@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dmMessagingWindow is how long after the user's last message the business may reply.
+const dmMessagingWindow = 24 * time.Hour
+
+// ListDMConversations returns the org's DM threads, most recent first.
+// @Summary Listar conversas de DM
+// @Description Lista as conversas de DM do Instagram com contagem de não lidas e janela de 24h
+// @Tags instagram-dm
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Param unread query bool false "Somente conversas com mensagens não lidas"
+// @Param assigned_to query string false "ID do membro ou 'me'"
+// @Param search query string false "Buscar por username"
+// @Success 200 {object} models.DMConversationListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error listing conversations"
+// @Router /admin/instagram/conversations [get]
+func ListDMConversations(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	q := r.URL.Query()
+
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"org_id": orgID}
+	if q.Get("unread") == "true" {
+		filter["unread_count"] = bson.M{"$gt": 0}
+	}
+	if assigned := q.Get("assigned_to"); assigned != "" {
+		if assigned == "me" {
+			filter["assigned_to"] = middleware.GetUserID(r)
+		} else if oid, err := primitive.ObjectIDFromHex(assigned); err == nil {
+			filter["assigned_to"] = oid
+		}
+	}
+	if search := q.Get("search"); search != "" {
+		filter["participant_username"] = bson.M{"$regex": regexp.QuoteMeta(search), "$options": "i"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.DMConversations().CountDocuments(ctx, filter)
+	if err != nil {
+		slog.Error("list_dm_conversations_count", "error", err)
+		http.Error(w, "Error listing conversations", http.StatusInternalServerError)
+		return
+	}
+
+	unreadTotal, _ := database.DMConversations().CountDocuments(ctx, bson.M{"org_id": orgID, "unread_count": bson.M{"$gt": 0}})
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "last_message_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.DMConversations().Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("list_dm_conversations_find", "error", err)
+		http.Error(w, "Error listing conversations", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var conversations []models.DMConversation
+	if err := cursor.All(ctx, &conversations); err != nil {
+		slog.Error("list_dm_conversations_decode", "error", err)
+		http.Error(w, "Error listing conversations", http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]models.DMConversationResponse, 0, len(conversations))
+	for _, c := range conversations {
+		items = append(items, buildDMConversationResponse(c))
+	}
+
+	json.NewEncoder(w).Encode(models.DMConversationListResponse{
+		Conversations: items,
+		Total:         total,
+		UnreadTotal:   unreadTotal,
+		Page:          page,
+		Limit:         limit,
+	})
+}
+
+// GetDMThread returns a conversation with its messages and marks it as read.
+// @Summary Ler conversa de DM
+// @Description Retorna as mensagens da conversa (mais antigas primeiro) e zera a contagem de não lidas
+// @Tags instagram-dm
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da conversa"
+// @Param page query int false "Página (padrão 1 = mais recentes)"
+// @Param limit query int false "Itens por página (padrão 50, máx 200)"
+// @Success 200 {object} models.DMThreadResponse
+// @Failure 400 {string} string "Invalid conversation ID"
+// @Failure 404 {string} string "Conversation not found"
+// @Router /admin/instagram/conversations/{id} [get]
+func GetDMThread(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conv, ok := findDMConversation(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{"conversation_id": conv.ID}
+	total, _ := database.DMMessages().CountDocuments(ctx, filter)
+
+	// Page 1 is the most recent slice; reversed below so the thread reads oldest-first.
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.DMMessages().Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("get_dm_thread_find", "error", err)
+		http.Error(w, "Error loading messages", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var messages []models.DMMessage
+	if err := cursor.All(ctx, &messages); err != nil {
+		slog.Error("get_dm_thread_decode", "error", err)
+		http.Error(w, "Error loading messages", http.StatusInternalServerError)
+		return
+	}
+	if messages == nil {
+		messages = []models.DMMessage{}
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	if conv.UnreadCount > 0 {
+		database.DMConversations().UpdateOne(ctx, bson.M{"_id": conv.ID}, bson.M{
+			"$set": bson.M{"unread_count": 0},
+		})
+		conv.UnreadCount = 0
+	}
+
+	json.NewEncoder(w).Encode(models.DMThreadResponse{
+		Conversation: buildDMConversationResponse(conv),
+		Messages:     messages,
+		Total:        total,
+		Page:         page,
+		Limit:        limit,
+	})
+}
+
+// SendDMReply sends a manual DM reply inside the 24h messaging window.
+// @Summary Responder DM manualmente
+// @Description Envia uma resposta manual na conversa (somente dentro da janela de 24h desde a última mensagem do usuário)
+// @Tags instagram-dm
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da conversa"
+// @Param body body models.SendDMRequest true "Mensagem"
+// @Success 201 {object} models.DMMessage
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Messaging window closed"
+// @Failure 404 {string} string "Conversation not found"
+// @Failure 502 {string} string "Instagram API error"
+// @Router /admin/instagram/conversations/{id}/messages [post]
+func SendDMReply(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+
+	var req models.SendDMRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		http.Error(w, "Text is required", http.StatusBadRequest)
+		return
+	}
+	if len([]rune(req.Text)) > 1000 {
+		http.Error(w, "Text exceeds 1000 characters", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	conv, ok := findDMConversation(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	if conv.LastInboundAt.IsZero() || time.Since(conv.LastInboundAt) > dmMessagingWindow {
+		http.Error(w, "Messaging window closed: the user has not messaged in the last 24h", http.StatusForbidden)
+		return
+	}
+
+	creds, err := resolveCredsByAccountID(ctx, conv.AccountID)
+	if err != nil || creds == nil {
+		slog.Error("dm_reply_creds", "error", err, "account", maskAccountID(conv.AccountID))
+		http.Error(w, "Instagram not configured for this account", http.StatusBadRequest)
+		return
+	}
+
+	msg := models.DMMessage{
+		Direction: "outbound",
+		Text:      req.Text,
+		Source:    "manual",
+		SentBy:    &userID,
+		Status:    "sent",
+	}
+
+	mid, sendErr := sendInstagramDM(creds.AccountID, creds.Token, conv.ParticipantIGID, req.Text)
+	msg.MID = mid
+	if sendErr != nil {
+		msg.Status = "failed"
+		msg.ErrorMessage = sendErr.Error()
+	}
+
+	msg = recordDMMessage(ctx, conv.OrgID, conv.AccountID, conv.ParticipantIGID, "", msg)
+
+	if sendErr != nil {
+		slog.Error("dm_reply_failed", "error", sendErr, "conversation_id", conv.ID.Hex())
+		http.Error(w, "Instagram API error: "+sendErr.Error(), http.StatusBadGateway)
+		return
+	}
+
+	slog.Info("dm_reply_sent", "conversation_id", conv.ID.Hex(), "user_id", userID.Hex())
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(msg)
+}
+
+// AssignDMConversation assigns a conversation to an org member (or clears it).
+// @Summary Atribuir conversa de DM
+// @Description Atribui a conversa a um membro da organização (user_id vazio remove a atribuição)
+// @Tags instagram-dm
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da conversa"
+// @Param body body models.AssignDMConversationRequest true "Membro"
+// @Success 200 {object} models.DMConversationResponse
+// @Failure 400 {string} string "Invalid member"
+// @Failure 404 {string} string "Conversation not found"
+// @Router /admin/instagram/conversations/{id}/assign [put]
+func AssignDMConversation(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	var req models.AssignDMConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conv, ok := findDMConversation(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if req.UserID == "" {
+		update["$unset"] = bson.M{"assigned_to": ""}
+	} else {
+		assignee, ok := requireOrgMember(ctx, w, orgID, req.UserID)
+		if !ok {
+			return
+		}
+		update["$set"].(bson.M)["assigned_to"] = assignee
+	}
+
+	var updated models.DMConversation
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err := database.DMConversations().FindOneAndUpdate(ctx, bson.M{"_id": conv.ID}, update, opts).Decode(&updated); err != nil {
+		slog.Error("assign_dm_conversation", "error", err)
+		http.Error(w, "Error updating conversation", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(buildDMConversationResponse(updated))
+}
+
+// ── Helpers ──────────────────────────────────────────────────────────
+
+// findDMConversation loads the conversation from the {id} path value, scoped to the org.
+func findDMConversation(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.DMConversation, bool) {
+	var conv models.DMConversation
+
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid conversation ID", http.StatusBadRequest)
+		return conv, false
+	}
+
+	err = database.DMConversations().FindOne(ctx, bson.M{"_id": oid, "org_id": middleware.GetOrgID(r)}).Decode(&conv)
+	if err != nil {
+		http.Error(w, "Conversation not found", http.StatusNotFound)
+		return conv, false
+	}
+	return conv, true
+}
+
+func buildDMConversationResponse(c models.DMConversation) models.DMConversationResponse {
+	resp := models.DMConversationResponse{DMConversation: c}
+	if !c.LastInboundAt.IsZero() {
+		ends := c.LastInboundAt.Add(dmMessagingWindow)
+		resp.WindowEndsAt = &ends
+		resp.WindowOpen = time.Now().Before(ends)
+	}
+	return resp
+}
+
+// recordDMMessage persists a message and then bumps its conversation (creating
+// it on first contact). Inbound messages increment the unread count and reopen
+// the 24h window; replies sent from here or from the Instagram app clear it.
+// Duplicates (same MID, e.g. webhook retries or echoes of our own sends) are
+// not counted again. Returns the stored message.
+func recordDMMessage(ctx context.Context, orgID primitive.ObjectID, accountID, participantID, username string, msg models.DMMessage) models.DMMessage {
+	if orgID == primitive.NilObjectID || participantID == "" {
+		return msg
+	}
+
+	now := time.Now()
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = now
+	}
+
+	// Find or create the thread; its counters move only once the message is stored
+	var conv models.DMConversation
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	err := database.DMConversations().FindOneAndUpdate(ctx,
+		bson.M{"org_id": orgID, "participant_ig_id": participantID},
+		bson.M{"$setOnInsert": bson.M{
+			"account_id":        accountID,
+			"last_message_text": "",
+			"last_message_at":   msg.CreatedAt,
+			"unread_count":      0,
+			"created_at":        now,
+			"updated_at":        now,
+		}}, opts,
+	).Decode(&conv)
+	if err != nil {
+		slog.Error("dm_conversation_upsert_error", "error", err, "participant", participantID)
+		return msg
+	}
+
+	msg.OrgID = orgID
+	msg.ConversationID = conv.ID
+	result, err := database.DMMessages().InsertOne(ctx, msg)
+	if mongo.IsDuplicateKeyError(err) {
+		// A webhook retry, or the echo of a message sent from here arriving
+		// before its send returned: keep who sent it on the stored copy.
+		if msg.Direction == "outbound" && msg.Source != "external" {
+			set := bson.M{"source": msg.Source}
+			if msg.SentBy != nil {
+				set["sent_by"] = msg.SentBy
+			}
+			if msg.RuleName != "" {
+				set["rule_name"] = msg.RuleName
+			}
+			var stored models.DMMessage
+			err := database.DMMessages().FindOneAndUpdate(ctx, bson.M{"mid": msg.MID}, bson.M{"$set": set},
+				options.FindOneAndUpdate().SetReturnDocument(options.After),
+			).Decode(&stored)
+			if err == nil {
+				return stored
+			}
+		}
+		return msg
+	}
+	if err != nil {
+		slog.Error("dm_message_insert_error", "error", err, "conversation_id", conv.ID.Hex())
+		return msg
+	}
+	msg.ID = result.InsertedID.(primitive.ObjectID)
+
+	set := bson.M{
+		"account_id":        accountID,
+		"last_message_text": dmPreviewText(msg),
+		"last_message_at":   msg.CreatedAt,
+		"updated_at":        now,
+	}
+	if username != "" {
+		set["participant_username"] = username
+	}
+	update := bson.M{"$set": set}
+
+	switch {
+	case msg.Direction == "inbound":
+		set["last_inbound_at"] = msg.CreatedAt
+		update["$inc"] = bson.M{"unread_count": 1}
+	case msg.Source == "manual" || msg.Source == "external":
+		set["unread_count"] = 0
+	}
+
+	if _, err := database.DMConversations().UpdateOne(ctx, bson.M{"_id": conv.ID}, update); err != nil {
+		slog.Error("dm_conversation_update_error", "error", err, "conversation_id", conv.ID.Hex())
+	}
+	return msg
+}
+
+// dmPreviewText is the conversation preview of a message: its text, or the
+// type of its first attachment for attachment-only messages.
+func dmPreviewText(msg models.DMMessage) string {
+	if msg.Text != "" || len(msg.Attachments) == 0 {
+		return msg.Text
+	}
+	return "[" + msg.Attachments[0].Type + "]"
+}
+
+// recordAutoReplyDM adds a DM sent by an auto-reply rule to the participant's thread.
+func recordAutoReplyDM(ctx context.Context, orgID primitive.ObjectID, accountID, participantID, username, ruleName, text, mid string, sendErr error) {
+	msg := models.DMMessage{
+		MID:       mid,
+		Direction: "outbound",
+		Text:      text,
+		Source:    "autoreply",
+		RuleName:  ruleName,
+		Status:    "sent",
+	}
+	if sendErr != nil {
+		msg.Status = "failed"
+		msg.ErrorMessage = sendErr.Error()
+	}
+	recordDMMessage(ctx, orgID, accountID, participantID, username, msg)
+}
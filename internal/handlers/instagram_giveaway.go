@@ -0,0 +1,683 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	giveawayMaxWinners      = 100
+	giveawayMaxCommentPages = 200 // 50 comments per page
+
+	// giveawayDrawTimeout is how long a draw may stay "drawing" before it is
+	// considered abandoned (crash or lost request) and can be run again.
+	giveawayDrawTimeout = 5 * time.Minute
+)
+
+// errGiveawayTooManyComments means the post has more comments than a draw reads,
+// so the entry list would be incomplete.
+var errGiveawayTooManyComments = errors.New("post has too many comments to draw from")
+
+var giveawayMentionRe = regexp.MustCompile(`@([A-Za-z0-9._]+)`)
+
+// giveawayComment is a top-level comment fetched from the Graph API.
+type giveawayComment struct {
+	ID       string
+	Username string
+	Text     string
+}
+
+// CreateGiveawayDraw commits a draw: the seed is generated here and only its hash is
+// published, before any comment is fetched. Winners are drawn by RunGiveawayDraw.
+// @Summary Registrar sorteio
+// @Description Registra o sorteio com as regras e gera a seed no servidor, publicando apenas o hash (compromisso) antes de buscar os comentários. A seed é revelada junto com o resultado.
+// @Tags instagram-giveaways
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateGiveawayDrawRequest true "Post, quantidade de ganhadores e regras"
+// @Success 201 {object} models.GiveawayDraw
+// @Failure 400 {string} string "Invalid request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 502 {string} string "Instagram API error"
+// @Router /admin/instagram/giveaways [post]
+func CreateGiveawayDraw(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+
+	var req models.CreateGiveawayDrawRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.MediaID = strings.TrimSpace(req.MediaID)
+	if req.MediaID == "" {
+		http.Error(w, "media_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.Winners < 1 || req.Winners > giveawayMaxWinners {
+		http.Error(w, fmt.Sprintf("winners must be between 1 and %d", giveawayMaxWinners), http.StatusBadRequest)
+		return
+	}
+	if req.Rules.MinMentions < 0 {
+		http.Error(w, "min_mentions must be >= 0", http.StatusBadRequest)
+		return
+	}
+	switch req.Notify {
+	case "":
+	case "dm", "reply":
+		if strings.TrimSpace(req.NotifyMessage) == "" {
+			http.Error(w, "notify_message is required when notify is set", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "notify must be empty, dm or reply", http.StatusBadRequest)
+		return
+	}
+
+	_, creds, ok := requireInstagramCreds(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	mediaParams := url.Values{}
+	mediaParams.Set("fields", "id,permalink")
+	media, err := metaGraphGet("/"+req.MediaID, creds.Token, mediaParams)
+	if err != nil {
+		http.Error(w, "Instagram API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	permalink, _ := media["permalink"].(string)
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		http.Error(w, "Error generating seed", http.StatusInternalServerError)
+		return
+	}
+	seed := hex.EncodeToString(b)
+	seedSum := sha256.Sum256([]byte(seed))
+
+	draw := models.GiveawayDraw{
+		ID:            primitive.NewObjectID(),
+		OrgID:         orgID,
+		AccountID:     creds.AccountID,
+		MediaID:       req.MediaID,
+		Permalink:     permalink,
+		Rules:         req.Rules,
+		WinnersCount:  req.Winners,
+		Status:        "committed",
+		Seed:          seed,
+		SeedHash:      hex.EncodeToString(seedSum[:]),
+		Winners:       []models.GiveawayWinner{},
+		Notify:        req.Notify,
+		NotifyMessage: req.NotifyMessage,
+		CreatedBy:     userID,
+		CreatedAt:     time.Now(),
+	}
+
+	if _, err := database.GiveawayDraws().InsertOne(ctx, draw); err != nil {
+		slog.Error("giveaway_commit_insert", "error", err)
+		http.Error(w, "Error saving draw", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("giveaway_committed", "draw_id", draw.ID.Hex(), "media_id", req.MediaID, "seed_hash", draw.SeedHash)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hideUndrawnSeed(draw))
+}
+
+// RunGiveawayDraw fetches all comments of a committed draw's post, applies the
+// eligibility rules, draws the winners with the committed seed and reveals it.
+// @Summary Sortear ganhadores
+// @Description Busca todos os comentários do post de um sorteio registrado, aplica as regras de elegibilidade e sorteia os ganhadores com a seed comprometida, que é revelada no resultado
+// @Tags instagram-giveaways
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do sorteio"
+// @Success 200 {object} models.GiveawayDraw
+// @Failure 400 {string} string "Invalid ID"
+// @Failure 404 {string} string "Draw not found"
+// @Failure 409 {string} string "Draw already run"
+// @Failure 422 {string} string "Not enough eligible entries"
+// @Failure 502 {string} string "Instagram API error"
+// @Router /admin/instagram/giveaways/{id}/draw [post]
+func RunGiveawayDraw(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	_, creds, ok := requireInstagramCreds(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	// Claim the draw so it runs once; a draw left "drawing" past the timeout
+	// was abandoned and can be claimed again
+	var draw models.GiveawayDraw
+	now := time.Now()
+	err = database.GiveawayDraws().FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "org_id": orgID, "$or": []bson.M{
+			{"status": "committed"},
+			{"status": "drawing", "drawing_started_at": bson.M{"$lt": now.Add(-giveawayDrawTimeout)}},
+		}},
+		bson.M{"$set": bson.M{"status": "drawing", "drawing_started_at": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&draw)
+	if err == mongo.ErrNoDocuments {
+		count, _ := database.GiveawayDraws().CountDocuments(ctx, bson.M{"_id": id, "org_id": orgID})
+		if count == 0 {
+			http.Error(w, "Draw not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Draw already run", http.StatusConflict)
+		}
+		return
+	}
+	if err != nil {
+		http.Error(w, "Error loading draw", http.StatusInternalServerError)
+		return
+	}
+
+	// Return the draw to "committed" when it can't complete, so it can be retried
+	release := func() {
+		rctx, rcancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer rcancel()
+		database.GiveawayDraws().UpdateOne(rctx,
+			bson.M{"_id": draw.ID, "status": "drawing", "drawing_started_at": now},
+			bson.M{"$set": bson.M{"status": "committed"}, "$unset": bson.M{"drawing_started_at": ""}},
+		)
+	}
+
+	if draw.AccountID != creds.AccountID {
+		release()
+		http.Error(w, "Draw belongs to another Instagram account", http.StatusConflict)
+		return
+	}
+
+	mediaParams := url.Values{}
+	mediaParams.Set("fields", "id,username")
+	media, err := metaGraphGet("/"+draw.MediaID, creds.Token, mediaParams)
+	if err != nil {
+		release()
+		http.Error(w, "Instagram API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	ownerUsername, _ := media["username"].(string)
+
+	comments, err := fetchAllMediaComments(draw.MediaID, creds.Token)
+	if errors.Is(err, errGiveawayTooManyComments) {
+		release()
+		http.Error(w, fmt.Sprintf("Post has more than %d comments; a draw must read all of them", giveawayMaxCommentPages*50), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		release()
+		http.Error(w, "Instagram API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	excluded := giveawayExcludedUsernames(ctx, orgID, draw.Rules, ownerUsername)
+	eligible := filterGiveawayEntries(comments, draw.Rules, excluded)
+
+	winners := drawGiveawayWinners(draw.Seed, eligible, draw.WinnersCount)
+	if len(winners) < draw.WinnersCount {
+		release()
+		http.Error(w, fmt.Sprintf("Not enough eligible entries: %d eligible users for %d winners", len(winners), draw.WinnersCount), http.StatusUnprocessableEntity)
+		return
+	}
+
+	texts := make(map[string]string, len(comments))
+	for _, c := range comments {
+		texts[c.ID] = c.Text
+	}
+	for i := range winners {
+		winners[i].Text = texts[winners[i].CommentID]
+	}
+
+	// Record the result before notifying anyone
+	drawnAt := time.Now()
+	draw.Status = "drawn"
+	draw.EntriesHash = giveawayEntriesHash(eligible)
+	draw.TotalComments = len(comments)
+	draw.EligibleCount = len(eligible)
+	draw.Entries = eligible
+	draw.Winners = winners
+	draw.DrawnAt = &drawnAt
+	draw.DrawingStartedAt = nil
+	res, err := database.GiveawayDraws().UpdateOne(ctx, bson.M{"_id": draw.ID, "status": "drawing", "drawing_started_at": now}, bson.M{
+		"$set": bson.M{
+			"status":         draw.Status,
+			"entries_hash":   draw.EntriesHash,
+			"total_comments": draw.TotalComments,
+			"eligible_count": draw.EligibleCount,
+			"entries":        draw.Entries,
+			"winners":        draw.Winners,
+			"drawn_at":       draw.DrawnAt,
+		},
+		"$unset": bson.M{"drawing_started_at": ""},
+	})
+	if err != nil {
+		slog.Error("giveaway_draw_update", "error", err)
+		release()
+		http.Error(w, "Error saving draw", http.StatusInternalServerError)
+		return
+	}
+	if res.MatchedCount == 0 {
+		// Timed out and claimed by another run, which owns the result
+		http.Error(w, "Draw already run", http.StatusConflict)
+		return
+	}
+
+	if draw.Notify != "" {
+		for i := range draw.Winners {
+			msg := replaceTemplateVars(draw.NotifyMessage, draw.Winners[i].Username, draw.Rules.Keyword)
+			var sendErr error
+			if draw.Notify == "dm" {
+				_, sendErr = sendPrivateReply(creds.AccountID, creds.Token, draw.Winners[i].CommentID, msg)
+			} else {
+				sendErr = sendCommentReply(creds.Token, draw.Winners[i].CommentID, msg)
+			}
+			if sendErr != nil {
+				draw.Winners[i].NotifyError = sendErr.Error()
+				slog.Warn("giveaway_notify_failed", "comment_id", draw.Winners[i].CommentID, "error", sendErr)
+				continue
+			}
+			draw.Winners[i].Notified = true
+		}
+		database.GiveawayDraws().UpdateOne(ctx, bson.M{"_id": draw.ID}, bson.M{"$set": bson.M{"winners": draw.Winners}})
+	}
+
+	slog.Info("giveaway_drawn",
+		"draw_id", draw.ID.Hex(),
+		"media_id", draw.MediaID,
+		"comments", len(comments),
+		"eligible", len(eligible),
+		"winners", len(winners),
+	)
+
+	json.NewEncoder(w).Encode(draw)
+}
+
+// hideUndrawnSeed blanks the seed of a draw whose winners are not drawn yet; only
+// its hash is public until then.
+func hideUndrawnSeed(draw models.GiveawayDraw) models.GiveawayDraw {
+	if draw.Status == "committed" || draw.Status == "drawing" {
+		draw.Seed = ""
+	}
+	return draw
+}
+
+// ListGiveawayDraws returns the org's past draws, without the entry lists.
+// @Summary Listar sorteios
+// @Description Lista os sorteios realizados pela organização (sem a lista de participantes)
+// @Tags instagram-giveaways
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Param media_id query string false "Filtrar por post"
+// @Success 200 {object} models.GiveawayDrawListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error listing draws"
+// @Router /admin/instagram/giveaways [get]
+func ListGiveawayDraws(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"org_id": orgID}
+	if mediaID := r.URL.Query().Get("media_id"); mediaID != "" {
+		filter["media_id"] = mediaID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.GiveawayDraws().CountDocuments(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error listing draws", http.StatusInternalServerError)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"entries": 0})
+
+	cursor, err := database.GiveawayDraws().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error listing draws", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var draws []models.GiveawayDraw
+	if err := cursor.All(ctx, &draws); err != nil {
+		http.Error(w, "Error decoding draws", http.StatusInternalServerError)
+		return
+	}
+	if draws == nil {
+		draws = []models.GiveawayDraw{}
+	}
+	for i := range draws {
+		draws[i] = hideUndrawnSeed(draws[i])
+	}
+
+	json.NewEncoder(w).Encode(models.GiveawayDrawListResponse{
+		Draws: draws,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// GetGiveawayDraw returns a draw with its full list of eligible entries.
+// @Summary Obter sorteio
+// @Description Retorna o sorteio com participantes elegíveis e ganhadores; a seed só aparece depois do sorteio
+// @Tags instagram-giveaways
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do sorteio"
+// @Success 200 {object} models.GiveawayDraw
+// @Failure 400 {string} string "Invalid ID"
+// @Failure 404 {string} string "Draw not found"
+// @Router /admin/instagram/giveaways/{id} [get]
+func GetGiveawayDraw(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	draw, ok := findGiveawayDraw(ctx, w, r)
+	if !ok {
+		return
+	}
+	json.NewEncoder(w).Encode(hideUndrawnSeed(draw))
+}
+
+// VerifyGiveawayDraw checks the revealed seed against its commitment and recomputes
+// the winners from the seed and stored entries.
+// @Summary Verificar sorteio
+// @Description Confere a seed revelada com o hash publicado, recalcula os ganhadores a partir da seed e dos participantes salvos e compara com o resultado registrado
+// @Tags instagram-giveaways
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do sorteio"
+// @Success 200 {object} models.GiveawayVerifyResponse
+// @Failure 400 {string} string "Invalid ID"
+// @Failure 404 {string} string "Draw not found"
+// @Failure 409 {string} string "Draw not run yet"
+// @Router /admin/instagram/giveaways/{id}/verify [get]
+func VerifyGiveawayDraw(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	draw, ok := findGiveawayDraw(ctx, w, r)
+	if !ok {
+		return
+	}
+	if draw.Status == "committed" || draw.Status == "drawing" {
+		http.Error(w, "Draw not run yet", http.StatusConflict)
+		return
+	}
+
+	seedSum := sha256.Sum256([]byte(draw.Seed))
+	expected := drawGiveawayWinners(draw.Seed, draw.Entries, draw.WinnersCount)
+	resp := models.GiveawayVerifyResponse{
+		SeedHashOK:      hex.EncodeToString(seedSum[:]) == draw.SeedHash,
+		EntriesHashOK:   giveawayEntriesHash(draw.Entries) == draw.EntriesHash,
+		ExpectedWinners: make([]string, 0, len(expected)),
+	}
+	for _, e := range expected {
+		resp.ExpectedWinners = append(resp.ExpectedWinners, e.CommentID)
+	}
+
+	resp.Valid = resp.SeedHashOK && resp.EntriesHashOK && len(expected) == len(draw.Winners)
+	for i := 0; resp.Valid && i < len(expected); i++ {
+		if expected[i].CommentID != draw.Winners[i].CommentID {
+			resp.Valid = false
+		}
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// findGiveawayDraw loads the draw from the {id} path value, scoped to the caller's org.
+func findGiveawayDraw(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.GiveawayDraw, bool) {
+	var draw models.GiveawayDraw
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return draw, false
+	}
+
+	err = database.GiveawayDraws().FindOne(ctx, bson.M{"_id": id, "org_id": middleware.GetOrgID(r)}).Decode(&draw)
+	if err == mongo.ErrNoDocuments {
+		http.Error(w, "Draw not found", http.StatusNotFound)
+		return draw, false
+	}
+	if err != nil {
+		http.Error(w, "Error loading draw", http.StatusInternalServerError)
+		return draw, false
+	}
+	return draw, true
+}
+
+// fetchAllMediaComments pages through every top-level comment of a media. It fails
+// with errGiveawayTooManyComments rather than return a partial list.
+func fetchAllMediaComments(mediaID, token string) ([]giveawayComment, error) {
+	var comments []giveawayComment
+	after := ""
+	for page := 0; page < giveawayMaxCommentPages; page++ {
+		params := url.Values{}
+		params.Set("fields", "id,text,username,from")
+		params.Set("limit", "50")
+		if after != "" {
+			params.Set("after", after)
+		}
+
+		result, err := metaGraphGet("/"+mediaID+"/comments", token, params)
+		if err != nil {
+			return nil, err
+		}
+
+		data, _ := result["data"].([]interface{})
+		for _, item := range data {
+			c, _ := item.(map[string]interface{})
+			gc := giveawayComment{}
+			gc.ID, _ = c["id"].(string)
+			gc.Text, _ = c["text"].(string)
+			gc.Username, _ = c["username"].(string)
+			if gc.Username == "" {
+				from, _ := c["from"].(map[string]interface{})
+				gc.Username, _ = from["username"].(string)
+			}
+			if gc.ID != "" && gc.Username != "" {
+				comments = append(comments, gc)
+			}
+		}
+
+		paging, _ := result["paging"].(map[string]interface{})
+		cursors, _ := paging["cursors"].(map[string]interface{})
+		after, _ = cursors["after"].(string)
+		if _, hasNext := paging["next"]; !hasNext || after == "" {
+			return comments, nil
+		}
+	}
+
+	slog.Warn("giveaway_comments_truncated", "media_id", mediaID, "comments", len(comments))
+	return nil, errGiveawayTooManyComments
+}
+
+// giveawayExcludedUsernames builds the lowercase set of usernames that can't win.
+func giveawayExcludedUsernames(ctx context.Context, orgID primitive.ObjectID, rules models.GiveawayRules, ownerUsername string) map[string]bool {
+	excluded := map[string]bool{}
+	add := func(u string) {
+		u = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(u), "@"))
+		if u != "" {
+			excluded[u] = true
+		}
+	}
+
+	add(ownerUsername)
+	for _, u := range rules.ExcludeUsernames {
+		add(u)
+	}
+
+	if rules.ExcludeOrgMembers {
+		var cfgs []models.InstagramConfig
+		if cursor, err := database.InstagramConfigs().Find(ctx, bson.M{"org_id": orgID}); err == nil {
+			cursor.All(ctx, &cfgs)
+		}
+		for _, cfg := range cfgs {
+			add(cfg.Username)
+		}
+
+		var memberships []models.OrgMembership
+		if cursor, err := database.OrgMemberships().Find(ctx, bson.M{"org_id": orgID}); err == nil {
+			cursor.All(ctx, &memberships)
+		}
+		userIDs := make([]primitive.ObjectID, 0, len(memberships))
+		for _, m := range memberships {
+			userIDs = append(userIDs, m.UserID)
+		}
+		if len(userIDs) > 0 {
+			var profiles []models.Profile
+			opts := options.Find().SetProjection(bson.M{"social_links.instagram": 1})
+			if cursor, err := database.Profiles().Find(ctx, bson.M{"user_id": bson.M{"$in": userIDs}}, opts); err == nil {
+				cursor.All(ctx, &profiles)
+			}
+			for _, p := range profiles {
+				// Profile field may hold a handle or a profile URL
+				handle := strings.TrimSuffix(p.SocialLinks.Instagram, "/")
+				if i := strings.LastIndex(handle, "/"); i >= 0 {
+					handle = handle[i+1:]
+				}
+				add(handle)
+			}
+		}
+	}
+
+	return excluded
+}
+
+// filterGiveawayEntries applies the eligibility rules and returns entries sorted by comment ID.
+func filterGiveawayEntries(comments []giveawayComment, rules models.GiveawayRules, excluded map[string]bool) []models.GiveawayEntry {
+	keyword := strings.ToLower(strings.TrimSpace(rules.Keyword))
+	seenUsers := map[string]bool{}
+	entries := []models.GiveawayEntry{}
+
+	for _, c := range comments {
+		username := strings.ToLower(c.Username)
+		if excluded[username] {
+			continue
+		}
+		if keyword != "" && !strings.Contains(strings.ToLower(c.Text), keyword) {
+			continue
+		}
+		if rules.MinMentions > 0 {
+			mentioned := map[string]bool{}
+			for _, m := range giveawayMentionRe.FindAllStringSubmatch(c.Text, -1) {
+				handle := strings.ToLower(strings.TrimRight(m[1], "."))
+				if handle != username && !excluded[handle] {
+					mentioned[handle] = true
+				}
+			}
+			if len(mentioned) < rules.MinMentions {
+				continue
+			}
+		}
+		if rules.UniqueUsers {
+			if seenUsers[username] {
+				continue
+			}
+			seenUsers[username] = true
+		}
+		entries = append(entries, models.GiveawayEntry{CommentID: c.ID, Username: c.Username})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CommentID < entries[j].CommentID })
+	return entries
+}
+
+// drawGiveawayWinners ranks entries by sha256(seed + ":" + comment_id) and takes the
+// lowest tickets, skipping users who already won. Anyone holding the seed and the
+// entry list can reproduce the result.
+func drawGiveawayWinners(seed string, entries []models.GiveawayEntry, n int) []models.GiveawayWinner {
+	type ticket struct {
+		entry models.GiveawayEntry
+		hash  string
+	}
+	tickets := make([]ticket, 0, len(entries))
+	for _, e := range entries {
+		sum := sha256.Sum256([]byte(seed + ":" + e.CommentID))
+		tickets = append(tickets, ticket{entry: e, hash: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].hash < tickets[j].hash })
+
+	winners := []models.GiveawayWinner{}
+	won := map[string]bool{}
+	for _, t := range tickets {
+		if len(winners) == n {
+			break
+		}
+		username := strings.ToLower(t.entry.Username)
+		if won[username] {
+			continue
+		}
+		won[username] = true
+		winners = append(winners, models.GiveawayWinner{
+			Position:  len(winners) + 1,
+			CommentID: t.entry.CommentID,
+			Username:  t.entry.Username,
+			Ticket:    t.hash,
+		})
+	}
+	return winners
+}
+
+// giveawayEntriesHash fingerprints the eligible entry list so it can't be altered after the draw.
+func giveawayEntriesHash(entries []models.GiveawayEntry) string {
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.CommentID)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(ids, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,607 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxHashtagsPerSet = 30
+
+var (
+	hashtagRe     = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+	hashtagWordRe = regexp.MustCompile(`^[\p{L}\p{N}_]+$`)
+)
+
+// extractHashtags returns the distinct lowercased hashtags of a caption, in order of appearance.
+func extractHashtags(caption string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, m := range hashtagRe.FindAllStringSubmatch(caption, -1) {
+		tag := strings.ToLower(m[1])
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// normalizeHashtagList strips '#' and whitespace from user input and drops duplicates.
+// Returns an error message when a hashtag is invalid.
+func normalizeHashtagList(input []string) ([]string, string) {
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, raw := range input {
+		tag := strings.TrimPrefix(strings.TrimSpace(raw), "#")
+		if tag == "" {
+			continue
+		}
+		if !hashtagWordRe.MatchString(tag) {
+			return nil, "Invalid hashtag: " + raw
+		}
+		if seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 {
+		return nil, "hashtags is required"
+	}
+	if len(tags) > maxHashtagsPerSet {
+		return nil, "A hashtag set can have at most 30 hashtags"
+	}
+	return tags, ""
+}
+
+// appendHashtagSets appends the hashtags of the given sets to a caption, skipping tags
+// the caption already has. Returns an error message when a set does not exist.
+func appendHashtagSets(ctx context.Context, orgID primitive.ObjectID, caption string, setIDs []string) (string, string) {
+	if len(setIDs) == 0 {
+		return caption, ""
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(setIDs))
+	for _, raw := range setIDs {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			return caption, "Invalid hashtag set ID: " + raw
+		}
+		ids = append(ids, id)
+	}
+
+	cursor, err := database.HashtagSets().Find(ctx, bson.M{"_id": bson.M{"$in": ids}, "org_id": orgID})
+	if err != nil {
+		return caption, "Error loading hashtag sets"
+	}
+	var sets []models.HashtagSet
+	cursor.All(ctx, &sets)
+	cursor.Close(ctx)
+
+	byID := map[primitive.ObjectID]models.HashtagSet{}
+	for _, s := range sets {
+		byID[s.ID] = s
+	}
+
+	seen := map[string]bool{}
+	for _, tag := range extractHashtags(caption) {
+		seen[tag] = true
+	}
+	var extra []string
+	for _, id := range ids {
+		set, ok := byID[id]
+		if !ok {
+			return caption, "Hashtag set not found: " + id.Hex()
+		}
+		for _, tag := range set.Hashtags {
+			if seen[strings.ToLower(tag)] {
+				continue
+			}
+			seen[strings.ToLower(tag)] = true
+			extra = append(extra, "#"+tag)
+		}
+	}
+	if len(extra) == 0 {
+		return caption, ""
+	}
+	if strings.TrimSpace(caption) == "" {
+		return strings.Join(extra, " "), ""
+	}
+	return strings.TrimRight(caption, " \n") + "\n\n" + strings.Join(extra, " "), ""
+}
+
+// ─── Hashtag sets ────────────────────────────────────────────────────
+
+// ListHashtagSets returns the org's hashtag sets.
+// @Summary Listar conjuntos de hashtags
+// @Description Lista os conjuntos de hashtags reutilizáveis da organização
+// @Tags instagram-hashtags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.HashtagSet
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/hashtag-sets [get]
+func ListHashtagSets(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := database.HashtagSets().Find(ctx, bson.M{"org_id": orgID}, opts)
+	if err != nil {
+		http.Error(w, "Error listing hashtag sets", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var sets []models.HashtagSet
+	cursor.All(ctx, &sets)
+	if sets == nil {
+		sets = []models.HashtagSet{}
+	}
+	json.NewEncoder(w).Encode(sets)
+}
+
+// CreateHashtagSet creates a hashtag set.
+// @Summary Criar conjunto de hashtags
+// @Description Cria um conjunto de hashtags reutilizável (máx 30 hashtags), que pode ser inserido em agendamentos via hashtag_set_ids
+// @Tags instagram-hashtags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateHashtagSetRequest true "Nome e hashtags"
+// @Success 201 {object} models.HashtagSet
+// @Failure 400 {string} string "Invalid request"
+// @Failure 409 {string} string "Hashtag set name already exists"
+// @Router /admin/instagram/hashtag-sets [post]
+func CreateHashtagSet(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+
+	var req models.CreateHashtagSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	tags, msg := normalizeHashtagList(req.Hashtags)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	set := models.HashtagSet{
+		ID:        primitive.NewObjectID(),
+		OrgID:     orgID,
+		Name:      req.Name,
+		Hashtags:  tags,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := database.HashtagSets().InsertOne(ctx, set); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			http.Error(w, "Hashtag set name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error creating hashtag set", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(set)
+}
+
+// UpdateHashtagSet updates a hashtag set.
+// @Summary Atualizar conjunto de hashtags
+// @Description Atualiza o nome ou as hashtags de um conjunto
+// @Tags instagram-hashtags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do conjunto"
+// @Param body body models.UpdateHashtagSetRequest true "Campos a atualizar"
+// @Success 200 {object} models.HashtagSet
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Hashtag set not found"
+// @Router /admin/instagram/hashtag-sets/{id} [put]
+func UpdateHashtagSet(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set, ok := findHashtagSet(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateHashtagSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		set.Name = name
+	}
+	if req.Hashtags != nil {
+		tags, msg := normalizeHashtagList(req.Hashtags)
+		if msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		set.Hashtags = tags
+	}
+	set.UpdatedAt = time.Now()
+
+	_, err := database.HashtagSets().UpdateOne(ctx, bson.M{"_id": set.ID}, bson.M{"$set": bson.M{
+		"name":       set.Name,
+		"hashtags":   set.Hashtags,
+		"updated_at": set.UpdatedAt,
+	}})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			http.Error(w, "Hashtag set name already exists", http.StatusConflict)
+			return
+		}
+		http.Error(w, "Error updating hashtag set", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(set)
+}
+
+// DeleteHashtagSet deletes a hashtag set.
+// @Summary Remover conjunto de hashtags
+// @Description Remove um conjunto de hashtags
+// @Tags instagram-hashtags
+// @Security BearerAuth
+// @Param id path string true "ID do conjunto"
+// @Success 204
+// @Failure 404 {string} string "Hashtag set not found"
+// @Router /admin/instagram/hashtag-sets/{id} [delete]
+func DeleteHashtagSet(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set, ok := findHashtagSet(ctx, w, r)
+	if !ok {
+		return
+	}
+	if _, err := database.HashtagSets().DeleteOne(ctx, bson.M{"_id": set.ID}); err != nil {
+		http.Error(w, "Error deleting hashtag set", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findHashtagSet loads the hashtag set from the {id} path value, scoped to the caller's org.
+func findHashtagSet(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.HashtagSet, bool) {
+	var set models.HashtagSet
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return set, false
+	}
+
+	err = database.HashtagSets().FindOne(ctx, bson.M{"_id": id, "org_id": middleware.GetOrgID(r)}).Decode(&set)
+	if err == mongo.ErrNoDocuments {
+		http.Error(w, "Hashtag set not found", http.StatusNotFound)
+		return set, false
+	}
+	if err != nil {
+		http.Error(w, "Error loading hashtag set", http.StatusInternalServerError)
+		return set, false
+	}
+	return set, true
+}
+
+// ─── Performance ─────────────────────────────────────────────────────
+
+// GetHashtagPerformance ranks the hashtags of published posts by average reach or engagement.
+// @Summary Desempenho de hashtags
+// @Description Extrai as hashtags das legendas publicadas e atribui a cada uma o alcance e o engajamento dos insights salvos dos posts
+// @Tags instagram-hashtags
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Período em dias (padrão 90, máx 365)"
+// @Param sort query string false "Ordenação: reach ou engagement (padrão engagement)"
+// @Param min_posts query int false "Mínimo de posts por hashtag (padrão 1)"
+// @Param limit query int false "Máximo de hashtags (padrão 50, máx 200)"
+// @Success 200 {object} models.HashtagPerformanceResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/hashtags/performance [get]
+func GetHashtagPerformance(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	q := r.URL.Query()
+	days, _ := strconv.Atoi(q.Get("days"))
+	if days < 1 || days > 365 {
+		days = 90
+	}
+	sortBy := q.Get("sort")
+	if sortBy != "reach" {
+		sortBy = "engagement"
+	}
+	minPosts, _ := strconv.Atoi(q.Get("min_posts"))
+	if minPosts < 1 {
+		minPosts = 1
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	stats, totalPosts := buildHashtagStats(ctx, orgID, days)
+
+	ranked := []models.HashtagStat{}
+	for _, s := range stats {
+		if s.PostCount >= minPosts {
+			ranked = append(ranked, s)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if sortBy == "reach" && a.AvgReach != b.AvgReach {
+			return a.AvgReach > b.AvgReach
+		}
+		if sortBy == "engagement" && a.AvgEngagement != b.AvgEngagement {
+			return a.AvgEngagement > b.AvgEngagement
+		}
+		if a.PostCount != b.PostCount {
+			return a.PostCount > b.PostCount
+		}
+		return a.Hashtag < b.Hashtag
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	json.NewEncoder(w).Encode(models.HashtagPerformanceResponse{
+		Days:       days,
+		Sort:       sortBy,
+		TotalPosts: totalPosts,
+		Hashtags:   ranked,
+	})
+}
+
+// buildHashtagStats attributes the latest insights of each post published in the period to
+// every hashtag of its caption. Snapshots captured before hashtags were stored fall back to
+// the caption of the schedule or integrated publish that created the post.
+func buildHashtagStats(ctx context.Context, orgID primitive.ObjectID, days int) ([]models.HashtagStat, int) {
+	since := time.Now().AddDate(0, 0, -days)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"org_id": orgID, "published_at": bson.M{"$gte": since}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "captured_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$media_id",
+			"source":       bson.M{"$first": "$source"},
+			"source_id":    bson.M{"$first": "$source_id"},
+			"hashtags":     bson.M{"$first": "$hashtags"},
+			"reach":        bson.M{"$first": "$reach"},
+			"interactions": bson.M{"$first": bson.M{"$add": bson.A{"$likes", "$comments", "$saves", "$shares"}}},
+		}}},
+	}
+	cursor, err := database.MediaInsights().Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("hashtag_stats_aggregate", "error", err)
+		return nil, 0
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Source       string              `bson:"source"`
+		SourceID     *primitive.ObjectID `bson:"source_id"`
+		Hashtags     []string            `bson:"hashtags"`
+		Reach        int64               `bson:"reach"`
+		Interactions int64               `bson:"interactions"`
+	}
+	cursor.All(ctx, &rows)
+
+	// Resolve captions for rows captured without hashtags
+	var scheduleIDs, publishIDs []primitive.ObjectID
+	for _, row := range rows {
+		if row.Hashtags != nil || row.SourceID == nil {
+			continue
+		}
+		switch row.Source {
+		case "schedule":
+			scheduleIDs = append(scheduleIDs, *row.SourceID)
+		case "integrated_publish":
+			publishIDs = append(publishIDs, *row.SourceID)
+		}
+	}
+	captions := map[primitive.ObjectID]string{}
+	if len(scheduleIDs) > 0 {
+		var schedules []models.InstagramSchedule
+		c, err := database.InstagramSchedules().Find(ctx, bson.M{"_id": bson.M{"$in": scheduleIDs}, "org_id": orgID},
+			options.Find().SetProjection(bson.M{"caption": 1}))
+		if err == nil {
+			c.All(ctx, &schedules)
+			c.Close(ctx)
+		}
+		for _, s := range schedules {
+			captions[s.ID] = s.Caption
+		}
+	}
+	if len(publishIDs) > 0 {
+		var pubs []models.IntegratedPublish
+		c, err := database.IntegratedPublishes().Find(ctx, bson.M{"_id": bson.M{"$in": publishIDs}, "org_id": orgID},
+			options.Find().SetProjection(bson.M{"caption": 1}))
+		if err == nil {
+			c.All(ctx, &pubs)
+			c.Close(ctx)
+		}
+		for _, p := range pubs {
+			captions[p.ID] = p.Caption
+		}
+	}
+
+	type acc struct {
+		posts      int
+		reach      int64
+		engagement float64
+	}
+	byTag := map[string]*acc{}
+	total := 0
+	for _, row := range rows {
+		if row.Reach <= 0 {
+			continue // no insights for this post
+		}
+		tags := row.Hashtags
+		if tags == nil && row.SourceID != nil {
+			tags = extractHashtags(captions[*row.SourceID])
+		}
+		if len(tags) == 0 {
+			continue
+		}
+		total++
+		rate := float64(row.Interactions) / float64(row.Reach) * 100
+		for _, tag := range tags {
+			a := byTag[tag]
+			if a == nil {
+				a = &acc{}
+				byTag[tag] = a
+			}
+			a.posts++
+			a.reach += row.Reach
+			a.engagement += rate
+		}
+	}
+
+	stats := make([]models.HashtagStat, 0, len(byTag))
+	for tag, a := range byTag {
+		stats = append(stats, models.HashtagStat{
+			Hashtag:       tag,
+			PostCount:     a.posts,
+			AvgReach:      float64(a.reach) / float64(a.posts),
+			AvgEngagement: a.engagement / float64(a.posts),
+			TotalReach:    a.reach,
+		})
+	}
+	return stats, total
+}
+
+// ─── Hashtag search ──────────────────────────────────────────────────
+
+// SearchHashtag returns the top and recent public media for a hashtag.
+// @Summary Pesquisar hashtag
+// @Description Busca a hashtag na API de hashtags do Instagram e retorna as mídias em destaque e recentes (últimas 24h). O Instagram limita a 30 hashtags distintas por conta a cada 7 dias
+// @Tags instagram-hashtags
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Hashtag (com ou sem #)"
+// @Param instagram_account_id query string false "Conta do Instagram (padrão: conta principal)"
+// @Success 200 {object} models.HashtagSearchResponse
+// @Failure 400 {string} string "Invalid hashtag"
+// @Failure 404 {string} string "Hashtag not found"
+// @Failure 502 {string} string "Instagram API error"
+// @Router /admin/instagram/hashtags/search [get]
+func SearchHashtag(w http.ResponseWriter, r *http.Request) {
+	tag := strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("q")), "#")
+	if !hashtagWordRe.MatchString(tag) {
+		http.Error(w, "Invalid hashtag", http.StatusBadRequest)
+		return
+	}
+
+	_, creds, ok := requireInstagramCreds(w, r)
+	if !ok {
+		return
+	}
+
+	params := url.Values{}
+	params.Set("user_id", creds.AccountID)
+	params.Set("q", tag)
+	result, err := metaGraphGet("/ig_hashtag_search", creds.Token, params)
+	if err != nil {
+		http.Error(w, "Instagram API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	data, _ := result["data"].([]interface{})
+	if len(data) == 0 {
+		http.Error(w, "Hashtag not found", http.StatusNotFound)
+		return
+	}
+	first, _ := data[0].(map[string]interface{})
+	hashtagID, _ := first["id"].(string)
+
+	top, err := fetchHashtagMedia(hashtagID, "top_media", creds.AccountID, creds.Token)
+	if err != nil {
+		http.Error(w, "Instagram API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	recent, err := fetchHashtagMedia(hashtagID, "recent_media", creds.AccountID, creds.Token)
+	if err != nil {
+		http.Error(w, "Instagram API error: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.HashtagSearchResponse{
+		Hashtag:     strings.ToLower(tag),
+		HashtagID:   hashtagID,
+		TopMedia:    top,
+		RecentMedia: recent,
+	})
+}
+
+// fetchHashtagMedia reads /{hashtag}/top_media or /{hashtag}/recent_media.
+func fetchHashtagMedia(hashtagID, edge, accountID, token string) ([]models.HashtagMedia, error) {
+	params := url.Values{}
+	params.Set("user_id", accountID)
+	params.Set("fields", "id,caption,media_type,permalink,like_count,comments_count,timestamp")
+	params.Set("limit", "25")
+	result, err := metaGraphGet("/"+hashtagID+"/"+edge, token, params)
+	if err != nil {
+		return nil, err
+	}
+
+	media := []models.HashtagMedia{}
+	data, _ := result["data"].([]interface{})
+	for _, item := range data {
+		m, _ := item.(map[string]interface{})
+		hm := models.HashtagMedia{}
+		hm.ID, _ = m["id"].(string)
+		hm.Caption, _ = m["caption"].(string)
+		hm.MediaType, _ = m["media_type"].(string)
+		hm.Permalink, _ = m["permalink"].(string)
+		hm.Timestamp, _ = m["timestamp"].(string)
+		likes, _ := m["like_count"].(float64)
+		comments, _ := m["comments_count"].(float64)
+		hm.LikeCount = int64(likes)
+		hm.CommentsCount = int64(comments)
+		media = append(media, hm)
+	}
+	return media, nil
+}
@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListBrandMentions returns a paginated list of mentions of the org's accounts.
+// @Summary Listar menções da marca
+// @Description Lista menções em legendas, comentários e stories capturadas via webhook
+// @Tags instagram-mentions
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Param type query string false "caption, comment, story_mention ou story_reply"
+// @Success 200 {object} models.BrandMentionListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error listing mentions"
+// @Router /admin/instagram/mentions [get]
+func ListBrandMentions(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"org_id": orgID}
+	if t := r.URL.Query().Get("type"); t != "" {
+		filter["type"] = t
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.BrandMentions().CountDocuments(ctx, filter)
+	if err != nil {
+		slog.Error("list_mentions_count", "error", err)
+		http.Error(w, "Error listing mentions", http.StatusInternalServerError)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "mentioned_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.BrandMentions().Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("list_mentions_find", "error", err)
+		http.Error(w, "Error listing mentions", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var mentions []models.BrandMention
+	if err := cursor.All(ctx, &mentions); err != nil {
+		slog.Error("list_mentions_decode", "error", err)
+		http.Error(w, "Error listing mentions", http.StatusInternalServerError)
+		return
+	}
+	if mentions == nil {
+		mentions = []models.BrandMention{}
+	}
+
+	json.NewEncoder(w).Encode(models.BrandMentionListResponse{
+		Mentions: mentions,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	})
+}
+
+// ─── Webhook processing ──────────────────────────────────────────────
+
+// processMention handles a "mentions" change: the account was @mentioned in
+// someone else's caption or comment. The media is fetched via mentioned_media.
+func processMention(igAccountID string, value webhookMentionValue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	slog.Info("webhook_mention: received", "ig_account_id", igAccountID, "media_id", value.MediaID, "comment_id", value.CommentID)
+
+	creds, err := resolveCredsByAccountID(ctx, igAccountID)
+	if err != nil || creds == nil {
+		slog.Warn("webhook_mention: no credentials for account", "ig_account_id", igAccountID, "error", err)
+		return err
+	}
+
+	mention := models.BrandMention{
+		OrgID:     creds.OrgID,
+		AccountID: igAccountID,
+		Type:      "caption",
+		MediaID:   value.MediaID,
+		CommentID: value.CommentID,
+	}
+
+	params := url.Values{}
+	params.Set("fields", fmt.Sprintf("mentioned_media.media_id(%s){caption,media_type,media_url,permalink,timestamp,username,like_count,comments_count}", value.MediaID))
+	result, err := metaGraphGet("/"+igAccountID, creds.Token, params)
+	if err != nil {
+		slog.Warn("webhook_mention: fetch media failed", "media_id", value.MediaID, "error", err)
+	} else if mm, ok := result["mentioned_media"].(map[string]interface{}); ok {
+		mention.Text, _ = mm["caption"].(string)
+		mention.MediaType, _ = mm["media_type"].(string)
+		mention.MediaURL, _ = mm["media_url"].(string)
+		mention.Permalink, _ = mm["permalink"].(string)
+		mention.AuthorUsername, _ = mm["username"].(string)
+		if v, ok := mm["like_count"].(float64); ok {
+			mention.LikeCount = int(v)
+		}
+		if v, ok := mm["comments_count"].(float64); ok {
+			mention.CommentsCount = int(v)
+		}
+		if ts, _ := mm["timestamp"].(string); ts != "" {
+			mention.MentionedAt, _ = time.Parse("2006-01-02T15:04:05-0700", ts)
+		}
+	}
+
+	// Comment mention: the text and time come from the comment, and the
+	// media owner is not the author (the API doesn't expose the commenter).
+	if value.CommentID != "" {
+		mention.Type = "comment"
+		mention.AuthorUsername = ""
+		mention.MentionedAt = time.Time{}
+
+		params := url.Values{}
+		params.Set("fields", fmt.Sprintf("mentioned_comment.comment_id(%s){id,text,timestamp,like_count}", value.CommentID))
+		result, err := metaGraphGet("/"+igAccountID, creds.Token, params)
+		if err != nil {
+			slog.Warn("webhook_mention: fetch comment failed", "comment_id", value.CommentID, "error", err)
+		} else if mc, ok := result["mentioned_comment"].(map[string]interface{}); ok {
+			mention.Text, _ = mc["text"].(string)
+			if ts, _ := mc["timestamp"].(string); ts != "" {
+				mention.MentionedAt, _ = time.Parse("2006-01-02T15:04:05-0700", ts)
+			}
+		}
+	}
+
+	return handleBrandMention(ctx, creds, mention)
+}
+
+// processStoryMention handles story mentions and story replies received via messaging.
+func processStoryMention(igAccountID string, msg webhookMessaging, kind, mediaURL string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	slog.Info("webhook_story_mention: received", "ig_account_id", igAccountID, "sender", msg.Sender.ID, "type", kind)
+
+	creds, err := resolveCredsByAccountID(ctx, igAccountID)
+	if err != nil || creds == nil {
+		slog.Warn("webhook_story_mention: no credentials for account", "ig_account_id", igAccountID, "error", err)
+		return err
+	}
+
+	mention := models.BrandMention{
+		OrgID:     creds.OrgID,
+		AccountID: igAccountID,
+		Type:      kind,
+		MID:       msg.Message.MID,
+		AuthorID:  msg.Sender.ID,
+		Text:      msg.Message.Text,
+		MediaType: "STORY",
+		MediaURL:  mediaURL,
+	}
+	if msg.Message.ReplyTo != nil && msg.Message.ReplyTo.Story != nil {
+		mention.MediaID = msg.Message.ReplyTo.Story.ID
+	}
+	if msg.Timestamp > 0 {
+		mention.MentionedAt = time.UnixMilli(msg.Timestamp)
+	}
+
+	return handleBrandMention(ctx, creds, mention)
+}
+
+// storyMentionURL returns the story CDN URL if the message is a story mention.
+func storyMentionURL(msg webhookMessaging) string {
+	if msg.Message == nil {
+		return ""
+	}
+	for _, a := range msg.Message.Attachments {
+		if a.Type == "story_mention" {
+			return a.Payload.URL
+		}
+	}
+	return ""
+}
+
+// handleBrandMention stores the mention, runs "mention" auto-reply rules,
+// updates the lead and pushes everything to the live feed.
+func handleBrandMention(ctx context.Context, creds *instagramCredentials, m models.BrandMention) error {
+	if creds.OrgID == primitive.NilObjectID {
+		slog.Warn("webhook_mention: account not linked to an org", "ig_account_id", m.AccountID)
+		return nil
+	}
+
+	now := time.Now()
+	m.CreatedAt = now
+	if m.MentionedAt.IsZero() {
+		m.MentionedAt = now
+	}
+
+	result, err := database.BrandMentions().InsertOne(ctx, m)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			slog.Info("webhook_mention: duplicate ignored", "type", m.Type, "media_id", m.MediaID, "mid", m.MID)
+			return nil
+		}
+		slog.Error("webhook_mention: insert failed", "error", err)
+		return err
+	}
+
+	// Story and image CDN URLs expire — keep our own copy (only once the
+	// mention is known to be new, so redeliveries don't archive it again)
+	if m.MediaURL != "" && (m.MediaType == "IMAGE" || m.MediaType == "STORY") {
+		m.StoredImageID = archiveMentionImage(ctx, m.OrgID, m.MediaURL)
+		if m.StoredImageID != "" {
+			database.BrandMentions().UpdateOne(ctx,
+				bson.M{"_id": result.InsertedID},
+				bson.M{"$set": bson.M{"stored_image_id": m.StoredImageID}},
+			)
+		}
+	}
+
+	// Caption/comment mentions only expose the username; leads and cooldowns then
+	// go by username with an empty sender_ig_id.
+	hasAuthor := m.AuthorID != "" || m.AuthorUsername != ""
+	sender := m.AuthorUsername
+	if sender == "" {
+		sender = m.AuthorID
+	}
+	triggerText := m.Text
+	if triggerText == "" {
+		triggerText = "[" + m.Type + "]"
+	}
+
+	BroadcastWebhookEvent(WebhookSSEEvent{
+		Type: "mention", Sender: sender,
+		TriggerText: triggerText, Status: "received",
+		Response:  "Menção registrada (" + m.Type + ")",
+		Timestamp: now.Format(time.RFC3339),
+	})
+
+	rules, err := findMatchingRules(ctx, m.Text, "mention", "instagram", m.MediaID, m.OrgID)
+	if err != nil {
+		slog.Error("webhook_mention: find rules", "error", err)
+		rules = nil
+	}
+
+	sent := false
+	for _, mr := range rules {
+		rule := mr.Rule
+
+		if hasAuthor && mentionHasCooldown(ctx, m, rule.ID) {
+			logAutoReply(ctx, rule, "instagram", "mention", m.AuthorID, m.AuthorUsername, triggerText, rule.ResponseMessage, "", "skipped_cooldown", "", m.OrgID)
+			BroadcastWebhookEvent(WebhookSSEEvent{
+				Type: "mention", RuleName: rule.Name, Sender: sender,
+				TriggerText: triggerText, Response: rule.ResponseMessage,
+				Status: "skipped_cooldown", Timestamp: time.Now().Format(time.RFC3339),
+			})
+			continue
+		}
+
+		response, err := respondToMention(ctx, creds, m, rule, mr.Keyword)
+		status, errMsg := "sent", ""
+		if err != nil {
+			status, errMsg = "failed", err.Error()
+			slog.Error("webhook_mention: reply failed", "error", err, "rule", rule.Name, "type", m.Type)
+		} else {
+			sent = true
+		}
+
+		logAutoReply(ctx, rule, "instagram", "mention", m.AuthorID, m.AuthorUsername, triggerText, response, "", status, errMsg, m.OrgID)
+		BroadcastWebhookEvent(WebhookSSEEvent{
+			Type: "mention", RuleName: rule.Name, Sender: sender,
+			TriggerText: triggerText, Response: response,
+			Status: status, Timestamp: time.Now().Format(time.RFC3339),
+		})
+	}
+
+	// logAutoReply already upserted the lead when a reply was sent
+	if !sent && hasAuthor {
+		upsertInstagramLead(ctx, m.AuthorID, m.AuthorUsername, "mention", "", m.OrgID)
+	}
+	return nil
+}
+
+// mentionHasCooldown is hasCooldown for mentions, falling back to the username
+// when the mention carries no IG user ID.
+func mentionHasCooldown(ctx context.Context, m models.BrandMention, ruleID primitive.ObjectID) bool {
+	if m.AuthorID != "" {
+		return hasCooldown(ctx, m.AuthorID, ruleID)
+	}
+	cutoff := time.Now().Add(-24 * time.Hour)
+	count, err := database.AutoReplyLogs().CountDocuments(ctx, bson.M{
+		"org_id":          m.OrgID,
+		"sender_ig_id":    "",
+		"sender_username": m.AuthorUsername,
+		"rule_id":         ruleID,
+		"status":          "sent",
+		"created_at":      bson.M{"$gte": cutoff},
+	})
+	if err != nil {
+		slog.Error("cooldown_check_error", "error", err)
+		return true // fail-safe: assume cooldown active
+	}
+	return count > 0
+}
+
+// respondToMention answers a mention according to its type: stories get a DM,
+// caption/comment mentions get a public comment via the Mentions API.
+func respondToMention(ctx context.Context, creds *instagramCredentials, m models.BrandMention, rule models.AutoReplyRule, keyword string) (string, error) {
+	if m.Type == "story_mention" || m.Type == "story_reply" {
+		msg := replaceTemplateVars(rule.ResponseMessage, m.AuthorUsername, keyword)
+		mid, err := sendInstagramDM(creds.AccountID, creds.Token, m.AuthorID, msg)
+		recordAutoReplyDM(ctx, m.OrgID, m.AccountID, m.AuthorID, m.AuthorUsername, rule.Name, msg, mid, err)
+		return msg, err
+	}
+
+	template := rule.CommentReply
+	if template == "" {
+		template = rule.ResponseMessage
+	}
+	msg := replaceTemplateVars(template, m.AuthorUsername, keyword)
+
+	params := url.Values{}
+	params.Set("media_id", m.MediaID)
+	if m.CommentID != "" {
+		params.Set("comment_id", m.CommentID)
+	}
+	params.Set("message", msg)
+	_, err := metaGraphPost("/"+creds.AccountID+"/mentions", creds.Token, params)
+	return msg, err
+}
+
+// archiveMentionImage downloads a mention image into the images collection.
+// Returns the image ID, or "" if the media isn't a supported image.
+func archiveMentionImage(ctx context.Context, orgID primitive.ObjectID, mediaURL string) string {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(mediaURL)
+	if err != nil {
+		slog.Warn("mention_archive: download failed", "error", err)
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		slog.Warn("mention_archive: bad status", "status", resp.StatusCode)
+		return ""
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return ""
+	}
+
+	contentType := http.DetectContentType(data)
+	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/webp" {
+		return "" // videos are kept as URL only
+	}
+
+	imgDoc := models.BlogImage{
+		ID:        primitive.NewObjectID(),
+		OrgID:     orgID,
+		Data:      base64.StdEncoding.EncodeToString(data),
+		Size:      len(data),
+		CreatedAt: time.Now(),
+	}
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		imgDoc.Width = cfg.Width
+	}
+
+	if _, err := database.Images().InsertOne(ctx, imgDoc); err != nil {
+		slog.Error("mention_archive: insert failed", "error", err)
+		return ""
+	}
+	return imgDoc.ID.Hex()
+}
@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/config"
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mediaInsightCheckpoints are the post ages at which insights are captured.
+var mediaInsightCheckpoints = []struct {
+	Label  string
+	Offset time.Duration
+}{
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+	{"24h", 24 * time.Hour},
+	{"72h", 72 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+}
+
+// mediaInsightGrace is how long after the last checkpoint a post is still tracked.
+const mediaInsightGrace = 12 * time.Hour
+
+// insightCandidate is a published post whose insights may be due.
+type insightCandidate struct {
+	OrgID       primitive.ObjectID
+	UserID      primitive.ObjectID
+	MediaID     string
+	Source      string
+	SourceID    *primitive.ObjectID
+	PublishedAt time.Time
+}
+
+// GetInstagramScheduleInsights returns the insight time series of a published schedule.
+// @Summary Série temporal de insights do post
+// @Description Retorna os snapshots de alcance, impressões, salvamentos, compartilhamentos, plays e visitas ao perfil capturados em 1h, 6h, 24h, 72h e 7d após a publicação
+// @Tags instagram
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} models.MediaInsightSeriesResponse
+// @Failure 400 {string} string "Invalid schedule ID"
+// @Failure 404 {string} string "Schedule not found"
+// @Failure 409 {string} string "Schedule not published yet"
+// @Router /admin/instagram/schedules/{id}/insights [get]
+func GetInstagramScheduleInsights(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var schedule models.InstagramSchedule
+	err = database.InstagramSchedules().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&schedule)
+	if err != nil {
+		http.Error(w, "Schedule not found", http.StatusNotFound)
+		return
+	}
+	if schedule.IGMediaID == "" {
+		http.Error(w, "Schedule not published yet", http.StatusConflict)
+		return
+	}
+
+	publishedAt := schedule.PublishedAt
+	if publishedAt == nil {
+		publishedAt = &schedule.UpdatedAt
+	}
+
+	json.NewEncoder(w).Encode(buildMediaInsightSeries(ctx, orgID, schedule.IGMediaID, publishedAt))
+}
+
+// GetIntegratedPublishInsights returns the insight time series of an integrated publish's IG post.
+// @Summary Série temporal de insights da publicação integrada
+// @Description Retorna os snapshots de insights do post do Instagram criado pela publicação integrada
+// @Tags integrated-publish
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Integrated publish ID"
+// @Success 200 {object} models.MediaInsightSeriesResponse
+// @Failure 400 {string} string "Invalid ID"
+// @Failure 404 {string} string "Not found"
+// @Failure 409 {string} string "Not published yet"
+// @Router /admin/integrated-publish/{id}/insights [get]
+func GetIntegratedPublishInsights(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pub models.IntegratedPublish
+	err = database.IntegratedPublishes().FindOne(ctx, bson.M{"_id": oid, "org_id": orgID}).Decode(&pub)
+	if err != nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	if pub.IGMediaID == "" {
+		http.Error(w, "Not published yet", http.StatusConflict)
+		return
+	}
+
+	publishedAt := pub.IGPublishedAt
+	if publishedAt == nil {
+		publishedAt = &pub.UpdatedAt
+	}
+
+	json.NewEncoder(w).Encode(buildMediaInsightSeries(ctx, orgID, pub.IGMediaID, publishedAt))
+}
+
+// buildMediaInsightSeries loads a post's snapshots in checkpoint order and lists the missing ones.
+func buildMediaInsightSeries(ctx context.Context, orgID primitive.ObjectID, mediaID string, publishedAt *time.Time) models.MediaInsightSeriesResponse {
+	opts := options.Find().SetSort(bson.D{{Key: "captured_at", Value: 1}})
+	cursor, err := database.MediaInsights().Find(ctx, bson.M{"org_id": orgID, "media_id": mediaID}, opts)
+
+	var snapshots []models.MediaInsightSnapshot
+	if err == nil {
+		cursor.All(ctx, &snapshots)
+		cursor.Close(ctx)
+	}
+	if snapshots == nil {
+		snapshots = []models.MediaInsightSnapshot{}
+	}
+
+	captured := map[string]bool{}
+	for _, s := range snapshots {
+		captured[s.Checkpoint] = true
+	}
+	pending := []string{}
+	for _, cp := range mediaInsightCheckpoints {
+		if !captured[cp.Label] && (publishedAt == nil || time.Since(*publishedAt) < cp.Offset+mediaInsightGrace) {
+			pending = append(pending, cp.Label)
+		}
+	}
+
+	return models.MediaInsightSeriesResponse{
+		MediaID:     mediaID,
+		PublishedAt: publishedAt,
+		Snapshots:   snapshots,
+		Pending:     pending,
+	}
+}
+
+// ─── Snapshot job ────────────────────────────────────────────────────
+
+// CaptureMediaInsights snapshots insights of recently published posts whose next
+// checkpoint is due. Posts are tracked for 7 days; a checkpoint missed while the
+// job was down is skipped in favour of the latest one due.
+func CaptureMediaInsights() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	candidates := collectInsightCandidates(ctx)
+	if len(candidates) == 0 {
+		return
+	}
+
+	mediaIDs := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		mediaIDs = append(mediaIDs, c.MediaID)
+	}
+	captured := map[string]bool{} // media_id + ":" + checkpoint
+	cursor, err := database.MediaInsights().Find(ctx,
+		bson.M{"media_id": bson.M{"$in": mediaIDs}},
+		options.Find().SetProjection(bson.M{"media_id": 1, "checkpoint": 1}),
+	)
+	if err != nil {
+		slog.Error("media_insights_load", "error", err)
+		return
+	}
+	var existing []models.MediaInsightSnapshot
+	cursor.All(ctx, &existing)
+	cursor.Close(ctx)
+	for _, s := range existing {
+		captured[s.MediaID+":"+s.Checkpoint] = true
+	}
+
+	credsCache := map[primitive.ObjectID]*instagramCredentials{}
+	count := 0
+	now := time.Now()
+	for _, c := range candidates {
+		age := now.Sub(c.PublishedAt)
+		checkpoint := ""
+		for _, cp := range mediaInsightCheckpoints {
+			if age >= cp.Offset {
+				checkpoint = cp.Label
+			}
+		}
+		if checkpoint == "" || captured[c.MediaID+":"+checkpoint] {
+			continue
+		}
+
+		creds, ok := credsCache[c.OrgID]
+		if !ok {
+			creds, err = getInstagramCredentials(ctx, c.UserID, c.OrgID)
+			if err != nil {
+				slog.Warn("media_insights_creds", "org_id", c.OrgID.Hex(), "error", err)
+			}
+			credsCache[c.OrgID] = creds
+		}
+		if creds == nil {
+			continue
+		}
+
+		snap, err := fetchMediaInsightSnapshot(c.MediaID, creds.Token)
+		if err != nil {
+			slog.Warn("media_insights_fetch", "media_id", c.MediaID, "error", err)
+			continue
+		}
+		snap.OrgID = c.OrgID
+		snap.AccountID = creds.AccountID
+		snap.MediaID = c.MediaID
+		snap.Source = c.Source
+		snap.SourceID = c.SourceID
+		snap.Checkpoint = checkpoint
+		snap.HoursSincePublish = age.Hours()
+		snap.PublishedAt = c.PublishedAt
+		snap.CapturedAt = now
+
+		if _, err := database.MediaInsights().InsertOne(ctx, snap); err != nil {
+			if !mongo.IsDuplicateKeyError(err) {
+				slog.Error("media_insights_insert", "media_id", c.MediaID, "error", err)
+			}
+			continue
+		}
+		captured[c.MediaID+":"+checkpoint] = true
+		count++
+	}
+
+	if count > 0 {
+		slog.Info("media_insights_cycle_complete", "snapshots", count, "candidates", len(candidates))
+	}
+}
+
+// collectInsightCandidates lists posts published within the tracking window: schedules,
+// integrated publishes and, when IG_INSIGHTS_ALL_MEDIA is set, every account's recent media.
+func collectInsightCandidates(ctx context.Context) []insightCandidate {
+	since := time.Now().Add(-(mediaInsightCheckpoints[len(mediaInsightCheckpoints)-1].Offset + mediaInsightGrace))
+	seen := map[string]bool{}
+	var candidates []insightCandidate
+
+	var schedules []models.InstagramSchedule
+	cursor, err := database.InstagramSchedules().Find(ctx, bson.M{
+		"status":      "published",
+		"ig_media_id": bson.M{"$nin": []interface{}{nil, ""}},
+		"$or": []bson.M{
+			{"published_at": bson.M{"$gte": since}},
+			{"published_at": bson.M{"$exists": false}, "updated_at": bson.M{"$gte": since}},
+		},
+	})
+	if err != nil {
+		slog.Error("media_insights_schedules", "error", err)
+	} else {
+		cursor.All(ctx, &schedules)
+		cursor.Close(ctx)
+	}
+	for _, s := range schedules {
+		publishedAt := s.UpdatedAt
+		if s.PublishedAt != nil {
+			publishedAt = *s.PublishedAt
+		}
+		id := s.ID
+		seen[s.IGMediaID] = true
+		candidates = append(candidates, insightCandidate{
+			OrgID: s.OrgID, UserID: s.UserID, MediaID: s.IGMediaID,
+			Source: "schedule", SourceID: &id, PublishedAt: publishedAt,
+		})
+	}
+
+	var pubs []models.IntegratedPublish
+	cursor, err = database.IntegratedPublishes().Find(ctx, bson.M{
+		"ig_media_id": bson.M{"$nin": []interface{}{nil, ""}},
+		"$or": []bson.M{
+			{"ig_published_at": bson.M{"$gte": since}},
+			{"ig_published_at": bson.M{"$exists": false}, "updated_at": bson.M{"$gte": since}},
+		},
+	})
+	if err != nil {
+		slog.Error("media_insights_integrated", "error", err)
+	} else {
+		cursor.All(ctx, &pubs)
+		cursor.Close(ctx)
+	}
+	for _, p := range pubs {
+		if seen[p.IGMediaID] {
+			continue
+		}
+		publishedAt := p.UpdatedAt
+		if p.IGPublishedAt != nil {
+			publishedAt = *p.IGPublishedAt
+		}
+		id := p.ID
+		seen[p.IGMediaID] = true
+		candidates = append(candidates, insightCandidate{
+			OrgID: p.OrgID, UserID: p.UserID, MediaID: p.IGMediaID,
+			Source: "integrated_publish", SourceID: &id, PublishedAt: publishedAt,
+		})
+	}
+
+	if config.Get().IGInsightsAllMedia && crypto.Available() {
+		candidates = append(candidates, collectAccountMediaCandidates(ctx, since, seen)...)
+	}
+
+	return candidates
+}
+
+// collectAccountMediaCandidates lists recent media of every connected account not published through the app.
+func collectAccountMediaCandidates(ctx context.Context, since time.Time, seen map[string]bool) []insightCandidate {
+	cursor, err := database.InstagramConfigs().Find(ctx, bson.M{})
+	if err != nil {
+		slog.Error("media_insights_configs", "error", err)
+		return nil
+	}
+	var cfgs []models.InstagramConfig
+	cursor.All(ctx, &cfgs)
+	cursor.Close(ctx)
+
+	var candidates []insightCandidate
+	for _, cfg := range cfgs {
+		token, err := crypto.Decrypt(cfg.AccessTokenEnc)
+		if err != nil {
+			slog.Warn("media_insights_decrypt", "account", maskAccountID(cfg.InstagramAccountID), "error", err)
+			continue
+		}
+
+		params := url.Values{}
+		params.Set("fields", "id,timestamp")
+		params.Set("limit", "50")
+		result, err := metaGraphGet("/"+cfg.InstagramAccountID+"/media", token, params)
+		if err != nil {
+			slog.Warn("media_insights_account_media", "account", maskAccountID(cfg.InstagramAccountID), "error", err)
+			continue
+		}
+
+		data, _ := result["data"].([]interface{})
+		for _, item := range data {
+			m, _ := item.(map[string]interface{})
+			mediaID, _ := m["id"].(string)
+			ts, _ := m["timestamp"].(string)
+			publishedAt, err := time.Parse("2006-01-02T15:04:05-0700", ts)
+			if mediaID == "" || err != nil || seen[mediaID] {
+				continue
+			}
+			if publishedAt.Before(since) {
+				break // media is returned newest first
+			}
+			seen[mediaID] = true
+			candidates = append(candidates, insightCandidate{
+				OrgID: cfg.OrgID, UserID: cfg.UserID, MediaID: mediaID,
+				Source: "account", PublishedAt: publishedAt,
+			})
+		}
+	}
+	return candidates
+}
+
+// fetchMediaInsightSnapshot reads counters and insights of a media. Supported metrics
+// depend on the media product type; if the combined request is rejected, each metric is
+// requested on its own and unsupported ones are left at zero.
+func fetchMediaInsightSnapshot(mediaID, token string) (models.MediaInsightSnapshot, error) {
+	var snap models.MediaInsightSnapshot
+
+	fieldParams := url.Values{}
+	fieldParams.Set("fields", "caption,like_count,comments_count,media_product_type")
+	media, err := metaGraphGet("/"+mediaID, token, fieldParams)
+	if err != nil {
+		return snap, err
+	}
+	likes, _ := media["like_count"].(float64)
+	comments, _ := media["comments_count"].(float64)
+	snap.Likes = int64(likes)
+	snap.Comments = int64(comments)
+	caption, _ := media["caption"].(string)
+	snap.Hashtags = extractHashtags(caption)
+
+	var metrics []string
+	switch productType, _ := media["media_product_type"].(string); productType {
+	case "REELS":
+		metrics = []string{"reach", "saved", "shares", "plays"}
+	case "STORY":
+		metrics = []string{"reach", "impressions", "shares", "profile_visits"}
+	default:
+		metrics = []string{"reach", "impressions", "saved", "shares", "profile_visits"}
+	}
+
+	values, err := fetchMediaInsightMetrics(mediaID, token, metrics)
+	if err != nil {
+		values = map[string]int64{}
+		for _, m := range metrics {
+			single, err := fetchMediaInsightMetrics(mediaID, token, []string{m})
+			if err != nil {
+				continue
+			}
+			values[m] = single[m]
+		}
+	}
+
+	snap.Reach = values["reach"]
+	snap.Impressions = values["impressions"]
+	snap.Saves = values["saved"]
+	snap.Shares = values["shares"]
+	snap.Plays = values["plays"]
+	snap.ProfileVisits = values["profile_visits"]
+	return snap, nil
+}
+
+// fetchMediaInsightMetrics calls /{media}/insights and returns metric name -> value.
+func fetchMediaInsightMetrics(mediaID, token string, metrics []string) (map[string]int64, error) {
+	params := url.Values{}
+	params.Set("metric", strings.Join(metrics, ","))
+	result, err := metaGraphGet("/"+mediaID+"/insights", token, params)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]int64{}
+	data, _ := result["data"].([]interface{})
+	for _, item := range data {
+		metric, _ := item.(map[string]interface{})
+		name, _ := metric["name"].(string)
+		if total, ok := metric["total_value"].(map[string]interface{}); ok {
+			v, _ := total["value"].(float64)
+			values[name] = int64(v)
+			continue
+		}
+		vals, _ := metric["values"].([]interface{})
+		if len(vals) > 0 {
+			first, _ := vals[0].(map[string]interface{})
+			v, _ := first["value"].(float64)
+			values[name] = int64(v)
+		}
+	}
+	return values, nil
+}
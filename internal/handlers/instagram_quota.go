@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// igDefaultPublishingQuota is used when content_publishing_limit cannot be read.
+	igDefaultPublishingQuota = 100
+	igQuotaWindow            = 24 * time.Hour
+	// igQuotaDeferral is how long a post waits before the quota is checked again.
+	igQuotaDeferral = 15 * time.Minute
+)
+
+// fetchPublishingLimit reads the account's current publishing quota from the Graph API.
+func fetchPublishingLimit(accountID, token string) (*models.InstagramPublishingLimit, error) {
+	params := url.Values{}
+	params.Set("fields", "config,quota_usage")
+	result, err := metaGraphGet("/"+accountID+"/content_publishing_limit", token, params)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := result["data"].([]interface{})
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty content_publishing_limit response")
+	}
+	entry, _ := data[0].(map[string]interface{})
+
+	limit := &models.InstagramPublishingLimit{
+		AccountID:     accountID,
+		QuotaTotal:    igDefaultPublishingQuota,
+		QuotaDuration: int(igQuotaWindow.Seconds()),
+		Source:        "instagram",
+		CheckedAt:     time.Now(),
+	}
+	if v, ok := entry["quota_usage"].(float64); ok {
+		limit.QuotaUsage = int(v)
+	}
+	if cfg, ok := entry["config"].(map[string]interface{}); ok {
+		if v, ok := cfg["quota_total"].(float64); ok && v > 0 {
+			limit.QuotaTotal = int(v)
+		}
+		if v, ok := cfg["quota_duration"].(float64); ok && v > 0 {
+			limit.QuotaDuration = int(v)
+		}
+	}
+	limit.Remaining = limit.QuotaTotal - limit.QuotaUsage
+	if limit.Remaining < 0 {
+		limit.Remaining = 0
+	}
+	return limit, nil
+}
+
+// publishingLimitFor returns the live quota for the credentials, falling back to the
+// documented default when the Graph API call fails.
+func publishingLimitFor(creds *instagramCredentials) *models.InstagramPublishingLimit {
+	limit, err := fetchPublishingLimit(creds.AccountID, creds.Token)
+	if err == nil {
+		return limit
+	}
+	slog.Warn("instagram_publishing_limit_error", "account_id", maskAccountID(creds.AccountID), "error", err)
+	return &models.InstagramPublishingLimit{
+		AccountID:     creds.AccountID,
+		QuotaTotal:    igDefaultPublishingQuota,
+		QuotaDuration: int(igQuotaWindow.Seconds()),
+		Remaining:     igDefaultPublishingQuota,
+		Source:        "default",
+		CheckedAt:     time.Now(),
+	}
+}
+
+// plannedPublishTimes returns the times of posts that count against the quota between
+// from and to: pending schedules at their scheduled time and published ones at their publish time.
+func plannedPublishTimes(ctx context.Context, orgID primitive.ObjectID, from, to time.Time, excludeID primitive.ObjectID) []time.Time {
+	filter := bson.M{
+		"org_id": orgID,
+		"_id":    bson.M{"$ne": excludeID},
+		"$or": []bson.M{
+			{"status": bson.M{"$in": []string{"scheduled", "publishing"}}, "scheduled_at": bson.M{"$gte": from, "$lte": to}},
+			{"status": "published", "published_at": bson.M{"$gte": from, "$lte": to}},
+		},
+	}
+	cursor, err := database.InstagramSchedules().Find(ctx, filter)
+	if err != nil {
+		return nil
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []models.InstagramSchedule
+	cursor.All(ctx, &schedules)
+
+	times := make([]time.Time, 0, len(schedules))
+	for _, s := range schedules {
+		if s.Status == "published" && s.PublishedAt != nil {
+			times = append(times, *s.PublishedAt)
+		} else {
+			times = append(times, s.ScheduledAt)
+		}
+	}
+	return times
+}
+
+// maxPostsInWindow returns the highest number of posts falling in any window that contains at.
+func maxPostsInWindow(times []time.Time, at time.Time, window time.Duration) int {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	best := 0
+	for _, start := range times {
+		if start.After(at) || !start.Add(window).After(at) {
+			continue
+		}
+		count := 0
+		for _, t := range times {
+			if !t.Before(start) && t.Before(start.Add(window)) {
+				count++
+			}
+		}
+		if count > best {
+			best = count
+		}
+	}
+	return best
+}
+
+// publishingQuotaWarnings warns when a post at scheduledAt would push any rolling 24h
+// window over the account's publishing quota. Never blocks the schedule.
+func publishingQuotaWarnings(ctx context.Context, userID, orgID primitive.ObjectID, scheduledAt time.Time, excludeID primitive.ObjectID) []string {
+	total := igDefaultPublishingQuota
+	if creds, err := getInstagramCredentials(ctx, userID, orgID); err == nil && creds != nil {
+		total = publishingLimitFor(creds).QuotaTotal
+	}
+
+	times := plannedPublishTimes(ctx, orgID, scheduledAt.Add(-igQuotaWindow), scheduledAt.Add(igQuotaWindow), excludeID)
+	times = append(times, scheduledAt)
+	count := maxPostsInWindow(times, scheduledAt, igQuotaWindow)
+	if count <= total {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"%d posts are planned within 24 hours of %s but Instagram allows %d; posts over the quota will be deferred",
+		count, scheduledAt.Format(time.RFC3339), total,
+	)}
+}
+
+// publishingQuotaTracker caches quota lookups per account during one scheduler run.
+type publishingQuotaTracker map[string]*models.InstagramPublishingLimit
+
+// allow reports whether the account can publish one more post, counting posts
+// already published in this run.
+func (q publishingQuotaTracker) allow(creds *instagramCredentials) (*models.InstagramPublishingLimit, bool) {
+	limit, ok := q[creds.AccountID]
+	if !ok {
+		limit = publishingLimitFor(creds)
+		q[creds.AccountID] = limit
+	}
+	return limit, limit.Remaining > 0
+}
+
+// consume records a successful publish against the cached quota.
+func (q publishingQuotaTracker) consume(accountID string) {
+	if limit, ok := q[accountID]; ok {
+		limit.QuotaUsage++
+		if limit.Remaining > 0 {
+			limit.Remaining--
+		}
+	}
+}
+
+// GetInstagramPublishingLimit returns the account's publishing quota and planned posts.
+// @Summary Cota de publicação do Instagram
+// @Description Retorna o uso atual da cota de publicação via API (posts nas últimas 24h), o limite da conta e quantos posts estão agendados para as próximas 24h
+// @Tags instagram
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.InstagramPublishingLimit
+// @Failure 400 {string} string "Instagram not configured"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/publishing-limit [get]
+func GetInstagramPublishingLimit(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+	_, creds, ok := requireInstagramCreds(w, r)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	limit := publishingLimitFor(creds)
+	now := time.Now()
+	planned, _ := database.InstagramSchedules().CountDocuments(ctx, bson.M{
+		"org_id":       orgID,
+		"status":       "scheduled",
+		"scheduled_at": bson.M{"$gte": now, "$lt": now.Add(igQuotaWindow)},
+	})
+	limit.Planned24h = int(planned)
+	limit.AccountID = maskAccountID(limit.AccountID)
+
+	json.NewEncoder(w).Encode(limit)
+}
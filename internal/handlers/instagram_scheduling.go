@@ -0,0 +1,785 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// defaultOrgTimezone is used for orgs without settings.timezone.
+	defaultOrgTimezone = "America/Sao_Paulo"
+
+	defaultMinGapMinutes  = 120
+	defaultMaxPostsPerDay = 3
+
+	// slotLeadTime keeps suggestions far enough ahead to be edited before publishing.
+	slotLeadTime = 30 * time.Minute
+	// suggestionHorizon is how far ahead slots are considered.
+	suggestionHorizon = 14 * 24 * time.Hour
+	// queueLookahead is where the queue looks for the best slot first, so queued posts
+	// fill the coming days instead of jumping to the best slot two weeks away.
+	queueLookahead = 72 * time.Hour
+	// heatmapSmoothing pulls weekday/hour averages with few posts toward the overall mean.
+	heatmapSmoothing = 2.0
+)
+
+// orgLocation returns the org's timezone, falling back to defaultOrgTimezone.
+func orgLocation(ctx context.Context, orgID primitive.ObjectID) *time.Location {
+	var org models.Organization
+	name := defaultOrgTimezone
+	opts := options.FindOne().SetProjection(bson.M{"settings.timezone": 1})
+	if err := database.Organizations().FindOne(ctx, bson.M{"_id": orgID}, opts).Decode(&org); err == nil && org.Settings.Timezone != "" {
+		name = org.Settings.Timezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc, _ = time.LoadLocation(defaultOrgTimezone)
+	}
+	return loc
+}
+
+// loadSchedulingPreferences returns the org's preferences with defaults applied.
+func loadSchedulingPreferences(ctx context.Context, orgID primitive.ObjectID) models.SchedulingPreferences {
+	prefs := models.SchedulingPreferences{
+		OrgID:          orgID,
+		PreferredDays:  []int{},
+		BlockedHours:   []int{},
+		MinGapMinutes:  defaultMinGapMinutes,
+		MaxPostsPerDay: defaultMaxPostsPerDay,
+	}
+	database.SchedulingPreferences().FindOne(ctx, bson.M{"org_id": orgID}).Decode(&prefs)
+	return prefs
+}
+
+// ─── Preferences ─────────────────────────────────────────────────────
+
+// GetSchedulingPreferences returns the org's scheduling preferences.
+// @Summary Obter preferências de agendamento
+// @Description Retorna dias preferidos, horários bloqueados, intervalo mínimo e limite diário usados nas sugestões e na fila
+// @Tags instagram-scheduling
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SchedulingPreferences
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/scheduling/preferences [get]
+func GetSchedulingPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	json.NewEncoder(w).Encode(loadSchedulingPreferences(ctx, middleware.GetOrgID(r)))
+}
+
+// UpdateSchedulingPreferences replaces the org's scheduling preferences.
+// @Summary Atualizar preferências de agendamento
+// @Description Define dias preferidos (0=domingo..6=sábado), horários bloqueados (0-23, fuso da organização), intervalo mínimo entre posts e limite diário
+// @Tags instagram-scheduling
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.UpdateSchedulingPreferencesRequest true "Preferências"
+// @Success 200 {object} models.SchedulingPreferences
+// @Failure 400 {string} string "Invalid request"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/scheduling/preferences [put]
+func UpdateSchedulingPreferences(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	var req models.UpdateSchedulingPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	for _, d := range req.PreferredDays {
+		if d < 0 || d > 6 {
+			http.Error(w, "preferred_days must be between 0 (Sunday) and 6 (Saturday)", http.StatusBadRequest)
+			return
+		}
+	}
+	for _, h := range req.BlockedHours {
+		if h < 0 || h > 23 {
+			http.Error(w, "blocked_hours must be between 0 and 23", http.StatusBadRequest)
+			return
+		}
+	}
+	if len(req.BlockedHours) >= 24 {
+		http.Error(w, "At least one hour must remain available", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	prefs := loadSchedulingPreferences(ctx, orgID)
+	if req.PreferredDays != nil {
+		prefs.PreferredDays = req.PreferredDays
+	}
+	if req.BlockedHours != nil {
+		prefs.BlockedHours = req.BlockedHours
+	}
+	if req.MinGapMinutes != nil {
+		if *req.MinGapMinutes < 0 || *req.MinGapMinutes > 24*60 {
+			http.Error(w, "min_gap_minutes must be between 0 and 1440", http.StatusBadRequest)
+			return
+		}
+		prefs.MinGapMinutes = *req.MinGapMinutes
+	}
+	if req.MaxPostsPerDay != nil {
+		if *req.MaxPostsPerDay < 0 {
+			http.Error(w, "max_posts_per_day must be >= 0", http.StatusBadRequest)
+			return
+		}
+		prefs.MaxPostsPerDay = *req.MaxPostsPerDay
+	}
+	prefs.UpdatedAt = time.Now()
+
+	_, err := database.SchedulingPreferences().UpdateOne(ctx,
+		bson.M{"org_id": orgID},
+		bson.M{"$set": bson.M{
+			"preferred_days":    prefs.PreferredDays,
+			"blocked_hours":     prefs.BlockedHours,
+			"min_gap_minutes":   prefs.MinGapMinutes,
+			"max_posts_per_day": prefs.MaxPostsPerDay,
+			"updated_at":        prefs.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		http.Error(w, "Error saving preferences", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// ─── Heatmap & suggestions ───────────────────────────────────────────
+
+// GetEngagementHeatmap returns the 7x24 weekday-by-hour engagement heatmap.
+// @Summary Mapa de calor de engajamento
+// @Description Engajamento médio por dia da semana e hora, no fuso da organização, a partir do histórico de insights salvo
+// @Tags instagram-scheduling
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Histórico considerado em dias (padrão 90, máx 365)"
+// @Success 200 {object} models.EngagementHeatmap
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/scheduling/heatmap [get]
+func GetEngagementHeatmap(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days < 1 || days > 365 {
+		days = 90
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	loc := orgLocation(ctx, orgID)
+	cells, total := buildEngagementHeatmap(ctx, orgID, loc, days)
+
+	json.NewEncoder(w).Encode(models.EngagementHeatmap{
+		Timezone:   loc.String(),
+		Days:       days,
+		TotalPosts: total,
+		Cells:      cells,
+	})
+}
+
+// GetSlotSuggestions returns the best upcoming posting slots.
+// @Summary Sugestões de horário
+// @Description Próximos melhores horários para publicar, respeitando dias preferidos, horários bloqueados, intervalo mínimo e posts já agendados
+// @Tags instagram-scheduling
+// @Produce json
+// @Security BearerAuth
+// @Param count query int false "Quantidade de sugestões (padrão 5, máx 20)"
+// @Success 200 {object} models.SlotSuggestionsResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/scheduling/suggestions [get]
+func GetSlotSuggestions(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+	if count < 1 || count > 20 {
+		count = 5
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	planner := newSlotPlanner(ctx, orgID)
+	suggestions := planner.suggest(count, suggestionHorizon)
+
+	json.NewEncoder(w).Encode(models.SlotSuggestionsResponse{
+		Timezone:    planner.loc.String(),
+		Suggestions: suggestions,
+	})
+}
+
+// buildEngagementHeatmap averages the engagement rate of each post's most recent insight
+// snapshot by weekday/hour of publishing in loc.
+func buildEngagementHeatmap(ctx context.Context, orgID primitive.ObjectID, loc *time.Location, days int) ([]models.HeatmapCell, int) {
+	cells := make([]models.HeatmapCell, 7*24)
+	for i := range cells {
+		cells[i].Weekday = i / 24
+		cells[i].Hour = i % 24
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"org_id": orgID, "published_at": bson.M{"$gte": since}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "captured_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$media_id",
+			"published_at": bson.M{"$first": "$published_at"},
+			"reach":        bson.M{"$first": "$reach"},
+			"interactions": bson.M{"$first": bson.M{"$add": bson.A{"$likes", "$comments", "$saves", "$shares"}}},
+		}}},
+	}
+	cursor, err := database.MediaInsights().Aggregate(ctx, pipeline)
+	if err != nil {
+		slog.Error("heatmap_aggregate", "error", err)
+		return cells, 0
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		PublishedAt  time.Time `bson:"published_at"`
+		Reach        int64     `bson:"reach"`
+		Interactions int64     `bson:"interactions"`
+	}
+	cursor.All(ctx, &rows)
+
+	sums := make([]float64, 7*24)
+	total := 0
+	for _, row := range rows {
+		if row.Reach <= 0 {
+			continue // no insights for this post
+		}
+		t := row.PublishedAt.In(loc)
+		i := int(t.Weekday())*24 + t.Hour()
+		sums[i] += float64(row.Interactions) / float64(row.Reach) * 100
+		cells[i].PostCount++
+		total++
+	}
+	for i := range cells {
+		if cells[i].PostCount > 0 {
+			cells[i].AvgEngagement = sums[i] / float64(cells[i].PostCount)
+		}
+	}
+	return cells, total
+}
+
+// slotPlanner picks posting slots from the heatmap under the org's preferences.
+type slotPlanner struct {
+	loc      *time.Location
+	prefs    models.SchedulingPreferences
+	cells    []models.HeatmapCell
+	mean     float64
+	occupied []time.Time
+}
+
+func newSlotPlanner(ctx context.Context, orgID primitive.ObjectID) *slotPlanner {
+	p := &slotPlanner{
+		loc:   orgLocation(ctx, orgID),
+		prefs: loadSchedulingPreferences(ctx, orgID),
+	}
+	p.cells, _ = buildEngagementHeatmap(ctx, orgID, p.loc, 90)
+
+	var sum float64
+	var n int
+	for _, c := range p.cells {
+		sum += c.AvgEngagement * float64(c.PostCount)
+		n += c.PostCount
+	}
+	if n > 0 {
+		p.mean = sum / float64(n)
+	}
+
+	cursor, err := database.InstagramSchedules().Find(ctx, bson.M{
+		"org_id":       orgID,
+		"status":       bson.M{"$in": []string{"scheduled", "pending_approval", "publishing"}},
+		"scheduled_at": bson.M{"$gte": time.Now().Add(-24 * time.Hour)},
+	}, options.Find().SetProjection(bson.M{"scheduled_at": 1}))
+	if err == nil {
+		var schedules []models.InstagramSchedule
+		cursor.All(ctx, &schedules)
+		cursor.Close(ctx)
+		for _, s := range schedules {
+			p.occupied = append(p.occupied, s.ScheduledAt)
+		}
+	}
+	return p
+}
+
+// score is the smoothed average engagement of a weekday/hour.
+func (p *slotPlanner) score(weekday, hour int) (float64, int) {
+	c := p.cells[weekday*24+hour]
+	n := float64(c.PostCount)
+	return (c.AvgEngagement*n + p.mean*heatmapSmoothing) / (n + heatmapSmoothing), c.PostCount
+}
+
+// available reports whether t respects preferred days, blocked hours, gap and daily cap.
+func (p *slotPlanner) available(t time.Time) bool {
+	local := t.In(p.loc)
+	if len(p.prefs.PreferredDays) > 0 && !containsInt(p.prefs.PreferredDays, int(local.Weekday())) {
+		return false
+	}
+	if containsInt(p.prefs.BlockedHours, local.Hour()) {
+		return false
+	}
+
+	gap := time.Duration(p.prefs.MinGapMinutes) * time.Minute
+	sameDay := 0
+	y, m, d := local.Date()
+	for _, o := range p.occupied {
+		diff := o.Sub(t)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < gap {
+			return false
+		}
+		if oy, om, od := o.In(p.loc).Date(); oy == y && om == m && od == d {
+			sameDay++
+		}
+	}
+	return p.prefs.MaxPostsPerDay == 0 || sameDay < p.prefs.MaxPostsPerDay
+}
+
+// suggest returns up to n free hourly slots within horizon, best score first.
+// Chosen slots count as occupied for the following ones.
+func (p *slotPlanner) suggest(n int, horizon time.Duration) []models.SlotSuggestion {
+	now := time.Now()
+	// Round on the local wall clock: Truncate works on the absolute instant, which
+	// lands on :30 or :45 in zones with fractional-hour offsets
+	lead := now.Add(slotLeadTime).In(p.loc)
+	y, m, d := lead.Date()
+	start := time.Date(y, m, d, lead.Hour(), 0, 0, 0, p.loc).Add(time.Hour)
+	end := now.Add(horizon)
+
+	var candidates []models.SlotSuggestion
+	for t := start; t.Before(end); t = t.Add(time.Hour) {
+		local := t.In(p.loc)
+		score, count := p.score(int(local.Weekday()), local.Hour())
+		candidates = append(candidates, models.SlotSuggestion{
+			At:        t,
+			Weekday:   int(local.Weekday()),
+			Hour:      local.Hour(),
+			Score:     score,
+			PostCount: count,
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+
+	picked := []models.SlotSuggestion{}
+	for _, c := range candidates {
+		if len(picked) == n {
+			break
+		}
+		if !p.available(c.At) {
+			continue
+		}
+		p.occupied = append(p.occupied, c.At)
+		picked = append(picked, c)
+	}
+	return picked
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// ─── Queue ───────────────────────────────────────────────────────────
+
+// ListQueuedPosts returns the org's smart queue.
+// @Summary Listar fila de posts
+// @Description Lista os posts na fila inteligente, por prioridade
+// @Tags instagram-scheduling
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "queued ou scheduled (padrão queued)"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} models.QueuedPostListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/queue [get]
+func ListQueuedPosts(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = "queued"
+	}
+
+	filter := bson.M{"org_id": orgID, "status": status}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.QueuedPosts().CountDocuments(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error listing queue", http.StatusInternalServerError)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "created_at", Value: 1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.QueuedPosts().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error listing queue", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var posts []models.QueuedPost
+	if err := cursor.All(ctx, &posts); err != nil {
+		http.Error(w, "Error decoding queue", http.StatusInternalServerError)
+		return
+	}
+	if posts == nil {
+		posts = []models.QueuedPost{}
+	}
+
+	json.NewEncoder(w).Encode(models.QueuedPostListResponse{
+		Posts: posts,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
+}
+
+// CreateQueuedPost adds a post without a time to the smart queue.
+// @Summary Adicionar post à fila
+// @Description Adiciona um post sem horário à fila; o job atribui o próximo melhor horário e cria o agendamento
+// @Tags instagram-scheduling
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateQueuedPostRequest true "Post"
+// @Success 201 {object} models.QueuedPost
+// @Failure 400 {string} string "Invalid request"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/queue [post]
+func CreateQueuedPost(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+
+	var req models.CreateQueuedPostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if res := validateInstagramPost(ctx, req.Caption, req.MediaType, req.ImageIDs); !res.Valid {
+		writeValidationErrors(w, res)
+		return
+	}
+	if req.PostToThreads {
+		if msg := validateThreadsCrosspost(ctx, orgID, req.Caption, req.ImageIDs); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := time.Now()
+	post := models.QueuedPost{
+		ID:             primitive.NewObjectID(),
+		OrgID:          orgID,
+		UserID:         userID,
+		Caption:        req.Caption,
+		MediaType:      req.MediaType,
+		ImageIDs:       req.ImageIDs,
+		PostToFacebook: req.PostToFacebook,
+		PostToThreads:  req.PostToThreads,
+		Priority:       req.Priority,
+		Status:         "queued",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if _, err := database.QueuedPosts().InsertOne(ctx, post); err != nil {
+		http.Error(w, "Error adding post to queue", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("queued_post_created", "id", post.ID.Hex(), "org_id", orgID.Hex(), "priority", post.Priority)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(post)
+}
+
+// UpdateQueuedPost edits a post that is still waiting in the queue.
+// @Summary Atualizar post da fila
+// @Description Edita legenda, mídia ou prioridade de um post ainda na fila
+// @Tags instagram-scheduling
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do post na fila"
+// @Param body body models.UpdateQueuedPostRequest true "Campos a atualizar"
+// @Success 200 {object} models.QueuedPost
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Queued post not found"
+// @Failure 409 {string} string "Post already scheduled"
+// @Router /admin/instagram/queue/{id} [put]
+func UpdateQueuedPost(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	post, ok := findQueuedPost(ctx, w, r)
+	if !ok {
+		return
+	}
+	if post.Status != "queued" {
+		http.Error(w, "Post already scheduled; edit the schedule instead", http.StatusConflict)
+		return
+	}
+
+	var req models.UpdateQueuedPostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Caption != nil {
+		post.Caption = *req.Caption
+	}
+	if req.MediaType != nil {
+		post.MediaType = *req.MediaType
+	}
+	if req.ImageIDs != nil {
+		post.ImageIDs = req.ImageIDs
+	}
+	if req.PostToFacebook != nil {
+		post.PostToFacebook = *req.PostToFacebook
+	}
+	if req.PostToThreads != nil {
+		post.PostToThreads = *req.PostToThreads
+	}
+	if req.Priority != nil {
+		post.Priority = *req.Priority
+	}
+	if res := validateInstagramPost(ctx, post.Caption, post.MediaType, post.ImageIDs); !res.Valid {
+		writeValidationErrors(w, res)
+		return
+	}
+	if post.PostToThreads {
+		if msg := validateThreadsCrosspost(ctx, post.OrgID, post.Caption, post.ImageIDs); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+	}
+	post.UpdatedAt = time.Now()
+
+	res, err := database.QueuedPosts().UpdateOne(ctx, bson.M{"_id": post.ID, "status": "queued"}, bson.M{"$set": bson.M{
+		"caption":          post.Caption,
+		"media_type":       post.MediaType,
+		"image_ids":        post.ImageIDs,
+		"post_to_facebook": post.PostToFacebook,
+		"post_to_threads":  post.PostToThreads,
+		"priority":         post.Priority,
+		"updated_at":       post.UpdatedAt,
+	}})
+	if err != nil {
+		http.Error(w, "Error updating queued post", http.StatusInternalServerError)
+		return
+	}
+	if res.MatchedCount == 0 {
+		http.Error(w, "Post already scheduled; edit the schedule instead", http.StatusConflict)
+		return
+	}
+
+	json.NewEncoder(w).Encode(post)
+}
+
+// DeleteQueuedPost removes a post from the queue.
+// @Summary Remover post da fila
+// @Description Remove um post que ainda não foi agendado
+// @Tags instagram-scheduling
+// @Security BearerAuth
+// @Param id path string true "ID do post na fila"
+// @Success 204
+// @Failure 404 {string} string "Queued post not found"
+// @Failure 409 {string} string "Post already scheduled"
+// @Router /admin/instagram/queue/{id} [delete]
+func DeleteQueuedPost(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	post, ok := findQueuedPost(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	res, err := database.QueuedPosts().DeleteOne(ctx, bson.M{"_id": post.ID, "status": "queued"})
+	if err != nil {
+		http.Error(w, "Error deleting queued post", http.StatusInternalServerError)
+		return
+	}
+	if res.DeletedCount == 0 {
+		http.Error(w, "Post already scheduled; delete the schedule instead", http.StatusConflict)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findQueuedPost loads the queued post from the {id} path value, scoped to the caller's org.
+func findQueuedPost(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.QueuedPost, bool) {
+	var post models.QueuedPost
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return post, false
+	}
+
+	err = database.QueuedPosts().FindOne(ctx, bson.M{"_id": id, "org_id": middleware.GetOrgID(r)}).Decode(&post)
+	if err == mongo.ErrNoDocuments {
+		http.Error(w, "Queued post not found", http.StatusNotFound)
+		return post, false
+	}
+	if err != nil {
+		http.Error(w, "Error loading queued post", http.StatusInternalServerError)
+		return post, false
+	}
+	return post, true
+}
+
+// ─── Queue job ───────────────────────────────────────────────────────
+
+// ProcessSchedulingQueue assigns queued posts to the next best slot, highest priority
+// first, and turns them into InstagramSchedule entries.
+func ProcessSchedulingQueue() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	orgIDs, err := database.QueuedPosts().Distinct(ctx, "org_id", bson.M{"status": "queued"})
+	if err != nil {
+		slog.Error("scheduling_queue_orgs", "error", err)
+		return
+	}
+
+	scheduled := 0
+	for _, raw := range orgIDs {
+		orgID, ok := raw.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		scheduled += processOrgQueue(ctx, orgID)
+	}
+
+	if scheduled > 0 {
+		slog.Info("scheduling_queue_cycle_complete", "scheduled", scheduled)
+	}
+}
+
+func processOrgQueue(ctx context.Context, orgID primitive.ObjectID) int {
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "created_at", Value: 1}}).SetLimit(50)
+	cursor, err := database.QueuedPosts().Find(ctx, bson.M{"org_id": orgID, "status": "queued"}, opts)
+	if err != nil {
+		slog.Error("scheduling_queue_find", "org_id", orgID.Hex(), "error", err)
+		return 0
+	}
+	var posts []models.QueuedPost
+	cursor.All(ctx, &posts)
+	cursor.Close(ctx)
+
+	planner := newSlotPlanner(ctx, orgID)
+	count, pending := 0, 0
+	defer func() {
+		if pending > 0 {
+			notifyReviewers(ctx, orgID, pending, "")
+		}
+	}()
+	for _, post := range posts {
+		slots := planner.suggest(1, queueLookahead)
+		if len(slots) == 0 {
+			slots = planner.suggest(1, suggestionHorizon)
+		}
+		if len(slots) == 0 {
+			slog.Warn("scheduling_queue_no_slot", "org_id", orgID.Hex(), "queued_post_id", post.ID.Hex())
+			return count
+		}
+		slotAt := slots[0].At
+		status, approvals := initialApprovalState(ctx, post.OrgID, post.UserID)
+
+		now := time.Now()
+		schedule := models.InstagramSchedule{
+			ID:             primitive.NewObjectID(),
+			UserID:         post.UserID,
+			OrgID:          post.OrgID,
+			Caption:        post.Caption,
+			MediaType:      post.MediaType,
+			ImageIDs:       post.ImageIDs,
+			ScheduledAt:    slotAt,
+			Status:         status,
+			PostToFacebook: post.PostToFacebook,
+			PostToThreads:  post.PostToThreads,
+			Approvals:      approvals,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+
+		// Claim the queued post first so a concurrent run can't schedule it twice
+		res, err := database.QueuedPosts().UpdateOne(ctx,
+			bson.M{"_id": post.ID, "status": "queued"},
+			bson.M{"$set": bson.M{
+				"status":      "scheduled",
+				"schedule_id": schedule.ID,
+				"slot_at":     slotAt,
+				"updated_at":  now,
+			}},
+		)
+		if err != nil || res.ModifiedCount == 0 {
+			continue
+		}
+
+		if _, err := database.InstagramSchedules().InsertOne(ctx, schedule); err != nil {
+			slog.Error("scheduling_queue_insert", "queued_post_id", post.ID.Hex(), "error", err)
+			database.QueuedPosts().UpdateOne(ctx, bson.M{"_id": post.ID}, bson.M{
+				"$set":   bson.M{"status": "queued", "updated_at": time.Now()},
+				"$unset": bson.M{"schedule_id": "", "slot_at": ""},
+			})
+			continue
+		}
+
+		slog.Info("queued_post_scheduled",
+			"queued_post_id", post.ID.Hex(),
+			"schedule_id", schedule.ID.Hex(),
+			"scheduled_at", slotAt.In(planner.loc).Format(time.RFC3339),
+		)
+		count++
+		if status == "pending_approval" {
+			pending++
+		}
+	}
+	return count
+}
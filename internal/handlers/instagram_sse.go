@@ -21,6 +21,7 @@ import (
 // WebhookSSEEvent is the payload broadcast to live-feed clients.
 type WebhookSSEEvent struct {
 	Type         string `json:"type"`
+	Channel      string `json:"channel,omitempty"` // "facebook"; empty for Instagram
 	RuleName     string `json:"rule_name"`
 	Sender       string `json:"sender"`
 	TriggerText  string `json:"trigger_text"`
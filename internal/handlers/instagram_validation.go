@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Instagram publishing limits for feed posts.
+const (
+	igMaxCaptionLength = 2200
+	igMaxHashtags      = 30
+	igMaxMentions      = 20
+	igMaxCarouselItems = 10
+	igMinAspectRatio   = 0.8  // 4:5 portrait
+	igMaxAspectRatio   = 1.91 // 1.91:1 landscape
+	igAspectTolerance  = 0.01
+)
+
+// mentionRe matches @username not preceded by a word character, so e-mail addresses are skipped.
+var mentionRe = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9._]{1,30})`)
+
+// ValidateInstagramPost runs the pre-publish validation without saving anything.
+// @Summary Validar post do Instagram
+// @Description Valida legenda e mídias contra os limites do Instagram (2200 caracteres, 30 hashtags, 20 menções, 10 itens no carrossel, proporção entre 4:5 e 1.91:1) e retorna os erros por campo
+// @Tags instagram
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.ValidatePostRequest true "Post a validar"
+// @Success 200 {object} models.PostValidationResult
+// @Failure 400 {string} string "Invalid request body"
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/instagram/validate [post]
+func ValidateInstagramPost(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	var req models.ValidatePostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	caption, msg := appendHashtagSets(ctx, orgID, req.Caption, req.HashtagSetIDs)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(validateInstagramPost(ctx, caption, req.MediaType, req.ImageIDs))
+}
+
+// validateInstagramPost checks a post against Instagram's limits, including that every
+// image exists and has a supported aspect ratio.
+func validateInstagramPost(ctx context.Context, caption, mediaType string, imageIDs []string) models.PostValidationResult {
+	res := instagramPostChecks(caption, mediaType, len(imageIDs))
+	for i, imgID := range imageIDs {
+		field := fmt.Sprintf("image_ids[%d]", i)
+		if issue, ok := checkInstagramImage(ctx, imgID); !ok {
+			issue.Field = field
+			res.Errors = append(res.Errors, issue)
+		}
+	}
+
+	res.Valid = len(res.Errors) == 0
+	if res.Errors == nil {
+		res.Errors = []models.ValidationIssue{}
+	}
+	return res
+}
+
+// instagramPostChecks runs the caption and media-count checks, which need no stored
+// images. The caller adds image issues and sets Valid.
+func instagramPostChecks(caption, mediaType string, itemCount int) models.PostValidationResult {
+	res := models.PostValidationResult{
+		CaptionLength: utf8.RuneCountInString(caption),
+		HashtagCount:  len(hashtagRe.FindAllString(caption, -1)),
+		MentionCount:  len(mentionRe.FindAllString(caption, -1)),
+		ItemCount:     itemCount,
+	}
+	add := func(field, code, message string) {
+		res.Errors = append(res.Errors, models.ValidationIssue{Field: field, Code: code, Message: message})
+	}
+
+	if res.CaptionLength > igMaxCaptionLength {
+		add("caption", "too_long", fmt.Sprintf("Caption must be %d characters or less (has %d)", igMaxCaptionLength, res.CaptionLength))
+	}
+	if res.HashtagCount > igMaxHashtags {
+		add("caption", "too_many_hashtags", fmt.Sprintf("Caption can have at most %d hashtags (has %d)", igMaxHashtags, res.HashtagCount))
+	}
+	if res.MentionCount > igMaxMentions {
+		add("caption", "too_many_mentions", fmt.Sprintf("Caption can have at most %d mentions (has %d)", igMaxMentions, res.MentionCount))
+	}
+
+	switch mediaType {
+	case "image":
+		if itemCount > 1 {
+			add("image_ids", "too_many", "image type allows only one image; use 'carousel' for multiple")
+		}
+	case "carousel":
+		if itemCount < 2 {
+			add("image_ids", "too_few", "carousel requires at least 2 images")
+		}
+		if itemCount > igMaxCarouselItems {
+			add("image_ids", "too_many", fmt.Sprintf("carousel allows at most %d items (has %d)", igMaxCarouselItems, itemCount))
+		}
+	default:
+		add("media_type", "invalid", "media_type must be 'image' or 'carousel'")
+	}
+
+	if itemCount == 0 {
+		add("image_ids", "required", "At least one image is required")
+	}
+	return res
+}
+
+// checkInstagramImage verifies that an image exists and that its aspect ratio is accepted by the feed.
+func checkInstagramImage(ctx context.Context, imgID string) (models.ValidationIssue, bool) {
+	oid, err := primitive.ObjectIDFromHex(imgID)
+	if err != nil {
+		return models.ValidationIssue{Code: "invalid", Message: "Invalid image ID: " + imgID}, false
+	}
+
+	var img models.BlogImage
+	if err := database.Images().FindOne(ctx, bson.M{"_id": oid}).Decode(&img); err != nil {
+		return models.ValidationIssue{Code: "not_found", Message: "Image not found: " + imgID}, false
+	}
+
+	width, height := img.Width, img.Height
+	if width == 0 || height == 0 {
+		// Older images only stored the width; read the dimensions from the image header
+		data, err := base64.StdEncoding.DecodeString(img.Data)
+		if err != nil {
+			return models.ValidationIssue{}, true
+		}
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return models.ValidationIssue{}, true
+		}
+		width, height = cfg.Width, cfg.Height
+	}
+	if height == 0 {
+		return models.ValidationIssue{}, true
+	}
+
+	ratio := float64(width) / float64(height)
+	if ratio < igMinAspectRatio-igAspectTolerance || ratio > igMaxAspectRatio+igAspectTolerance {
+		return models.ValidationIssue{
+			Code:    "aspect_ratio",
+			Message: fmt.Sprintf("Image %s is %dx%d (%.2f:1); feed posts must be between 4:5 and 1.91:1", imgID, width, height, ratio),
+		}, false
+	}
+	return models.ValidationIssue{}, true
+}
+
+// writeValidationErrors responds 400 with the structured validation result.
+func writeValidationErrors(w http.ResponseWriter, res models.PostValidationResult) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(res)
+}
+
+// validationSummary joins the validation messages into a single error string.
+func validationSummary(issues []models.ValidationIssue) string {
+	msgs := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		msgs = append(msgs, issue.Field+": "+issue.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
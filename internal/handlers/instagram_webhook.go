@@ -7,6 +7,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -38,6 +39,7 @@ import (
 // @Failure 403 {string} string "Forbidden"
 // @Failure 500 {string} string "Server not configured"
 // @Router /webhooks/instagram [get]
+// @Router /webhooks/facebook [get]
 func WebhookVerify(w http.ResponseWriter, r *http.Request) {
 	mode := r.URL.Query().Get("hub.mode")
 	token := r.URL.Query().Get("hub.verify_token")
@@ -83,8 +85,9 @@ type webhookChange struct {
 }
 
 type webhookCommentValue struct {
-	ID   string `json:"id"`
-	Text string `json:"text"`
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	ParentID string `json:"parent_id,omitempty"`
 	From struct {
 		ID       string `json:"id"`
 		Username string `json:"username"`
@@ -100,14 +103,35 @@ type webhookMessaging struct {
 	Recipient struct{ ID string `json:"id"` }    `json:"recipient"`
 	Timestamp int64                               `json:"timestamp"`
 	Message   *struct {
-		MID  string `json:"mid"`
-		Text string `json:"text"`
+		MID         string              `json:"mid"`
+		Text        string              `json:"text"`
+		IsEcho      bool                `json:"is_echo,omitempty"`
+		Attachments []webhookAttachment `json:"attachments,omitempty"`
+		ReplyTo     *struct {
+			Story *struct {
+				ID  string `json:"id"`
+				URL string `json:"url"`
+			} `json:"story,omitempty"`
+		} `json:"reply_to,omitempty"`
 	} `json:"message,omitempty"`
 }
 
+type webhookAttachment struct {
+	Type    string `json:"type"` // "story_mention", "image", ...
+	Payload struct {
+		URL string `json:"url"`
+	} `json:"payload"`
+}
+
+// webhookMentionValue is the value of a "mentions" change (caption or comment @mention).
+type webhookMentionValue struct {
+	MediaID   string `json:"media_id"`
+	CommentID string `json:"comment_id,omitempty"`
+}
+
 // WebhookEvent processes incoming Instagram webhook events.
 // @Summary Receber evento de webhook do Instagram
-// @Description Processa eventos de webhook do Instagram (comentários e DMs) com validação de assinatura
+// @Description Processa eventos de webhook do Instagram e de Páginas do Facebook (comentários e mensagens) com validação de assinatura
 // @Tags webhooks
 // @Accept json
 // @Param X-Hub-Signature-256 header string false "Assinatura HMAC SHA-256 do payload"
@@ -115,6 +139,7 @@ type webhookMessaging struct {
 // @Failure 400 {string} string "Bad request"
 // @Failure 403 {string} string "Forbidden"
 // @Router /webhooks/instagram [post]
+// @Router /webhooks/facebook [post]
 func WebhookEvent(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB limit
 	if err != nil {
@@ -164,18 +189,35 @@ func WebhookEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Persist before acknowledging so the event survives failures and restarts
+	eventID, err := storeWebhookEvent(payload.Object, body)
+	if err != nil {
+		slog.Error("webhook_event: store failed", "error", err)
+	}
+
 	// Respond 200 immediately (Meta expects fast response)
 	w.WriteHeader(http.StatusOK)
 
 	// Process in background
-	go processWebhookPayload(payload)
+	if eventID.IsZero() {
+		go processWebhookPayload(payload, false)
+		return
+	}
+	go runWebhookEvent(eventID, payload, false)
 }
 
-func processWebhookPayload(payload webhookPayload) {
+// processWebhookPayload dispatches every item in the payload. Each comment,
+// mention and message is processed at most once (keyed by its ID/MID) unless
+// force is set. Returns the joined errors of items that failed.
+func processWebhookPayload(payload webhookPayload, force bool) error {
+	if payload.Object == "page" {
+		return processFacebookPayload(payload, force)
+	}
 	if payload.Object != "instagram" {
-		return
+		return nil
 	}
 
+	var errs []error
 	for _, entry := range payload.Entry {
 		igAccountID := entry.ID
 
@@ -187,24 +229,65 @@ func processWebhookPayload(payload webhookPayload) {
 					slog.Error("webhook: parse comment", "error", err)
 					continue
 				}
-				processComment(igAccountID, comment)
+				errs = append(errs, processWebhookItem("comment:"+comment.ID, force, func() error {
+					return processComment(igAccountID, comment)
+				}))
+			}
+			if change.Field == "mentions" {
+				var mention webhookMentionValue
+				if err := json.Unmarshal(change.Value, &mention); err != nil {
+					slog.Error("webhook: parse mention", "error", err)
+					continue
+				}
+				errs = append(errs, processWebhookItem("mention:"+mention.MediaID+":"+mention.CommentID, force, func() error {
+					return processMention(igAccountID, mention)
+				}))
 			}
 		}
 
 		// Process DM events
 		for _, msg := range entry.Messaging {
-			if msg.Message != nil && msg.Message.Text != "" {
-				// Ignore messages sent by the page itself
-				if msg.Sender.ID == igAccountID {
-					continue
-				}
-				processDM(igAccountID, msg)
+			if msg.Message == nil {
+				continue
+			}
+
+			mid := msg.Message.MID
+
+			// Echoes of messages the account sent (from here or the Instagram app)
+			// only go to the inbox history
+			if msg.Message.IsEcho || msg.Sender.ID == igAccountID {
+				errs = append(errs, processWebhookItem("echo:"+mid, force, func() error {
+					return processDMEcho(igAccountID, msg)
+				}))
+				continue
+			}
+
+			// Story mentions arrive as an attachment-only message
+			if url := storyMentionURL(msg); url != "" {
+				errs = append(errs, processWebhookItem("story_mention:"+mid, force, func() error {
+					return processStoryMention(igAccountID, msg, "story_mention", url)
+				}))
+			}
+			// Story replies carry text and are also regular DMs
+			if msg.Message.ReplyTo != nil && msg.Message.ReplyTo.Story != nil {
+				errs = append(errs, processWebhookItem("story_reply:"+mid, force, func() error {
+					return processStoryMention(igAccountID, msg, "story_reply", msg.Message.ReplyTo.Story.URL)
+				}))
+			}
+
+			if msg.Message.Text != "" || len(msg.Message.Attachments) > 0 {
+				errs = append(errs, processWebhookItem("mid:"+mid, force, func() error {
+					return processDM(igAccountID, msg)
+				}))
 			}
 		}
 	}
+	return errors.Join(errs...)
 }
 
-func processComment(igAccountID string, comment webhookCommentValue) {
+// processComment handles a comment event. Returned errors are transient
+// (database lookups) and make the event eligible for retry.
+func processComment(igAccountID string, comment webhookCommentValue) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -220,14 +303,30 @@ func processComment(igAccountID string, comment webhookCommentValue) {
 			Response:  "Erro: credenciais do Instagram não encontradas para a conta " + igAccountID,
 			Timestamp: time.Now().Format(time.RFC3339),
 		})
-		return
+		return err
+	}
+
+	// Every comment lands in the moderation inbox, matched or not
+	if comment.From.ID != igAccountID {
+		ingestInboxComment(ctx, models.InboxComment{
+			OrgID:          creds.OrgID,
+			Platform:       "instagram",
+			AccountID:      igAccountID,
+			CommentID:      comment.ID,
+			ParentID:       comment.ParentID,
+			MediaID:        comment.Media.ID,
+			Text:           comment.Text,
+			AuthorID:       comment.From.ID,
+			AuthorUsername: comment.From.Username,
+			Source:         "webhook",
+		})
 	}
 
 	// Find matching active rules (scoped to org)
-	rules, err := findMatchingRules(ctx, comment.Text, "comment", comment.Media.ID, creds.OrgID)
+	rules, err := findMatchingRules(ctx, comment.Text, "comment", "instagram", comment.Media.ID, creds.OrgID)
 	if err != nil {
 		slog.Error("webhook_comment: find rules", "error", err)
-		return
+		return err
 	}
 
 	if len(rules) == 0 {
@@ -238,7 +337,7 @@ func processComment(igAccountID string, comment webhookCommentValue) {
 			Response:  "Nenhuma regra ativa corresponde a este comentário",
 			Timestamp: time.Now().Format(time.RFC3339),
 		})
-		return
+		return nil
 	}
 
 	for _, mr := range rules {
@@ -247,7 +346,7 @@ func processComment(igAccountID string, comment webhookCommentValue) {
 
 		// Check 24h cooldown
 		if hasCooldown(ctx, comment.From.ID, rule.ID) {
-			logAutoReply(ctx, rule, "comment", comment.From.ID, comment.From.Username, comment.Text, rule.ResponseMessage, "", "skipped_cooldown", "", creds.OrgID)
+			logAutoReply(ctx, rule, "instagram", "comment", comment.From.ID, comment.From.Username, comment.Text, rule.ResponseMessage, "", "skipped_cooldown", "", creds.OrgID)
 			BroadcastWebhookEvent(WebhookSSEEvent{
 				Type: "comment", RuleName: rule.Name, Sender: comment.From.Username,
 				TriggerText: comment.Text, Response: rule.ResponseMessage,
@@ -270,10 +369,11 @@ func processComment(igAccountID string, comment webhookCommentValue) {
 
 		// 2) Send DM via Private Reply (uses comment_id, not user ID)
 		dmMsg := replaceTemplateVars(rule.ResponseMessage, comment.From.Username, keyword)
-		err := sendPrivateReply(creds.AccountID, creds.Token, comment.ID, dmMsg)
+		mid, err := sendPrivateReply(creds.AccountID, creds.Token, comment.ID, dmMsg)
+		recordAutoReplyDM(ctx, creds.OrgID, igAccountID, comment.From.ID, comment.From.Username, rule.Name, dmMsg, mid, err)
 		if err != nil {
 			slog.Error("webhook_comment: send DM failed", "error", err, "sender", comment.From.ID, "rule", rule.Name)
-			logAutoReply(ctx, rule, "comment", comment.From.ID, comment.From.Username, comment.Text, dmMsg, commentReplySent, "failed", err.Error(), creds.OrgID)
+			logAutoReply(ctx, rule, "instagram", "comment", comment.From.ID, comment.From.Username, comment.Text, dmMsg, commentReplySent, "failed", err.Error(), creds.OrgID)
 			BroadcastWebhookEvent(WebhookSSEEvent{
 				Type: "comment", RuleName: rule.Name, Sender: comment.From.Username,
 				TriggerText: comment.Text, Response: dmMsg, CommentReply: commentReplySent,
@@ -283,16 +383,18 @@ func processComment(igAccountID string, comment webhookCommentValue) {
 		}
 
 		slog.Info("webhook_comment: DM sent", "sender", comment.From.ID, "rule", rule.Name)
-		logAutoReply(ctx, rule, "comment", comment.From.ID, comment.From.Username, comment.Text, dmMsg, commentReplySent, "sent", "", creds.OrgID)
+		logAutoReply(ctx, rule, "instagram", "comment", comment.From.ID, comment.From.Username, comment.Text, dmMsg, commentReplySent, "sent", "", creds.OrgID)
 		BroadcastWebhookEvent(WebhookSSEEvent{
 			Type: "comment", RuleName: rule.Name, Sender: comment.From.Username,
 			TriggerText: comment.Text, Response: dmMsg, CommentReply: commentReplySent,
 			Status: "sent", Timestamp: time.Now().Format(time.RFC3339),
 		})
 	}
+	return nil
 }
 
-func processDM(igAccountID string, msg webhookMessaging) {
+// processDM handles an inbound text DM. Returned errors are transient.
+func processDM(igAccountID string, msg webhookMessaging) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -310,13 +412,32 @@ func processDM(igAccountID string, msg webhookMessaging) {
 			Response:  "Erro: credenciais do Instagram não encontradas para a conta " + igAccountID,
 			Timestamp: time.Now().Format(time.RFC3339),
 		})
-		return
+		return err
+	}
+
+	// Persist the inbound message in the DM inbox
+	inbound := models.DMMessage{
+		MID:         msg.Message.MID,
+		Direction:   "inbound",
+		Text:        text,
+		Attachments: dmAttachments(msg.Message.Attachments),
+		Source:      "user",
+		Status:      "received",
+	}
+	if msg.Timestamp > 0 {
+		inbound.CreatedAt = time.UnixMilli(msg.Timestamp)
+	}
+	recordDMMessage(ctx, creds.OrgID, igAccountID, senderID, "", inbound)
+
+	// Attachment-only messages are kept in the inbox but match no keyword rule
+	if text == "" {
+		return nil
 	}
 
-	rules, err := findMatchingRules(ctx, text, "dm", "", creds.OrgID)
+	rules, err := findMatchingRules(ctx, text, "dm", "instagram", "", creds.OrgID)
 	if err != nil {
 		slog.Error("webhook_dm: find rules", "error", err)
-		return
+		return err
 	}
 
 	if len(rules) == 0 {
@@ -327,7 +448,7 @@ func processDM(igAccountID string, msg webhookMessaging) {
 			Response:  "Nenhuma regra ativa corresponde a esta DM",
 			Timestamp: time.Now().Format(time.RFC3339),
 		})
-		return
+		return nil
 	}
 
 	for _, mr := range rules {
@@ -335,7 +456,7 @@ func processDM(igAccountID string, msg webhookMessaging) {
 		keyword := mr.Keyword
 
 		if hasCooldown(ctx, senderID, rule.ID) {
-			logAutoReply(ctx, rule, "dm", senderID, "", text, rule.ResponseMessage, "", "skipped_cooldown", "", creds.OrgID)
+			logAutoReply(ctx, rule, "instagram", "dm", senderID, "", text, rule.ResponseMessage, "", "skipped_cooldown", "", creds.OrgID)
 			BroadcastWebhookEvent(WebhookSSEEvent{
 				Type: "dm", RuleName: rule.Name, Sender: senderID,
 				TriggerText: text, Response: rule.ResponseMessage,
@@ -345,10 +466,11 @@ func processDM(igAccountID string, msg webhookMessaging) {
 		}
 
 		dmMsg := replaceTemplateVars(rule.ResponseMessage, "", keyword)
-		err := sendInstagramDM(creds.AccountID, creds.Token, senderID, dmMsg)
+		mid, err := sendInstagramDM(creds.AccountID, creds.Token, senderID, dmMsg)
+		recordAutoReplyDM(ctx, creds.OrgID, igAccountID, senderID, "", rule.Name, dmMsg, mid, err)
 		if err != nil {
 			slog.Error("webhook_dm: send DM failed", "error", err, "sender", senderID, "rule", rule.Name)
-			logAutoReply(ctx, rule, "dm", senderID, "", text, dmMsg, "", "failed", err.Error(), creds.OrgID)
+			logAutoReply(ctx, rule, "instagram", "dm", senderID, "", text, dmMsg, "", "failed", err.Error(), creds.OrgID)
 			BroadcastWebhookEvent(WebhookSSEEvent{
 				Type: "dm", RuleName: rule.Name, Sender: senderID,
 				TriggerText: text, Response: dmMsg,
@@ -358,13 +480,14 @@ func processDM(igAccountID string, msg webhookMessaging) {
 		}
 
 		slog.Info("webhook_dm: DM sent", "sender", senderID, "rule", rule.Name)
-		logAutoReply(ctx, rule, "dm", senderID, "", text, dmMsg, "", "sent", "", creds.OrgID)
+		logAutoReply(ctx, rule, "instagram", "dm", senderID, "", text, dmMsg, "", "sent", "", creds.OrgID)
 		BroadcastWebhookEvent(WebhookSSEEvent{
 			Type: "dm", RuleName: rule.Name, Sender: senderID,
 			TriggerText: text, Response: dmMsg,
 			Status: "sent", Timestamp: time.Now().Format(time.RFC3339),
 		})
 	}
+	return nil
 }
 
 // ─── Helpers ─────────────────────────────────────────────────────────
@@ -425,14 +548,25 @@ type matchedRule struct {
 }
 
 // findMatchingRules returns active rules whose keywords match the text, scoped to an org.
-func findMatchingRules(ctx context.Context, text, triggerType, mediaID string, orgID primitive.ObjectID) ([]matchedRule, error) {
+// "both" covers comments and DMs only; mention rules must be explicitly typed.
+func findMatchingRules(ctx context.Context, text, triggerType, channel, mediaID string, orgID primitive.ObjectID) ([]matchedRule, error) {
+	// Rules created before Facebook support have no channel and apply to Instagram
+	channels := []interface{}{nil, "", "instagram", "all"}
+	if channel == "facebook" {
+		channels = []interface{}{"facebook", "all"}
+	}
 	filter := bson.M{
-		"active": true,
+		"active":  true,
+		"channel": bson.M{"$in": channels},
 		"$or": []bson.M{
 			{"trigger_type": triggerType},
 			{"trigger_type": "both"},
 		},
 	}
+	if triggerType == "mention" {
+		delete(filter, "$or")
+		filter["trigger_type"] = "mention"
+	}
 
 	// Scope to org if available
 	if orgID != primitive.NilObjectID {
@@ -468,6 +602,15 @@ func findMatchingRules(ctx context.Context, text, triggerType, mediaID string, o
 			}
 		}
 
+		// Only mention rules may match without keywords (story mentions carry no
+		// text); a keyword-less comment or DM rule would answer everything
+		if len(rule.Keywords) == 0 {
+			if rule.TriggerType == "mention" {
+				matched = append(matched, matchedRule{Rule: rule})
+			}
+			continue
+		}
+
 		// Check if any keyword matches
 		for _, kw := range rule.Keywords {
 			if strings.Contains(textLower, strings.ToLower(kw)) {
@@ -496,56 +639,70 @@ func hasCooldown(ctx context.Context, senderIGID string, ruleID primitive.Object
 	return count > 0
 }
 
-// sendInstagramDM sends a DM via the Instagram Messaging API using recipient user ID.
-// Use this for DM-triggered auto-replies (user already has a conversation with you).
-func sendInstagramDM(accountID, token, recipientID, message string) error {
-	url := fmt.Sprintf("https://graph.facebook.com/v21.0/%s/messages", accountID)
+// processDMEcho records a message the account sent to the participant's thread.
+// Sends made from here are already stored under the same MID and are skipped.
+func processDMEcho(igAccountID string, msg webhookMessaging) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-	payload := map[string]interface{}{
-		"recipient": map[string]string{"id": recipientID},
-		"message":   map[string]string{"text": message},
+	creds, err := resolveCredsByAccountID(ctx, igAccountID)
+	if err != nil || creds == nil {
+		slog.Warn("webhook_dm_echo: no credentials for account", "ig_account_id", igAccountID, "error", err)
+		return err
 	}
 
-	body, _ := json.Marshal(payload)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+	echo := models.DMMessage{
+		MID:         msg.Message.MID,
+		Direction:   "outbound",
+		Text:        msg.Message.Text,
+		Attachments: dmAttachments(msg.Message.Attachments),
+		Source:      "external",
+		Status:      "sent",
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("send request: %w", err)
+	if msg.Timestamp > 0 {
+		echo.CreatedAt = time.UnixMilli(msg.Timestamp)
 	}
-	defer resp.Body.Close()
+	recordDMMessage(ctx, creds.OrgID, igAccountID, msg.Recipient.ID, "", echo)
+	return nil
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("instagram API error %d: %s", resp.StatusCode, string(respBody))
+// dmAttachments converts webhook attachments to their inbox form.
+func dmAttachments(in []webhookAttachment) []models.DMAttachment {
+	var out []models.DMAttachment
+	for _, a := range in {
+		out = append(out, models.DMAttachment{Type: a.Type, URL: a.Payload.URL})
 	}
+	return out
+}
 
-	return nil
+// sendInstagramDM sends a DM via the Instagram Messaging API using recipient user ID.
+// Use this for DM-triggered auto-replies (user already has a conversation with you).
+// It returns the Meta message ID, which the webhook echo of the message carries too.
+func sendInstagramDM(accountID, token, recipientID, message string) (string, error) {
+	return sendInstagramMessage(accountID, token, map[string]string{"id": recipientID}, message)
 }
 
 // sendPrivateReply sends a DM via Instagram Private Replies API using comment_id.
 // This is the correct way to initiate a DM from a comment — you cannot use recipient.id
 // because Instagram only allows DMs to users who have messaged you first.
 // Private Replies uses comment_id as the recipient identifier.
-func sendPrivateReply(accountID, token, commentID, message string) error {
+func sendPrivateReply(accountID, token, commentID, message string) (string, error) {
+	return sendInstagramMessage(accountID, token, map[string]string{"comment_id": commentID}, message)
+}
+
+// sendInstagramMessage posts a text message to the Messaging API and returns its message ID.
+func sendInstagramMessage(accountID, token string, recipient map[string]string, message string) (string, error) {
 	url := fmt.Sprintf("https://graph.facebook.com/v21.0/%s/messages", accountID)
 
 	payload := map[string]interface{}{
-		"recipient": map[string]string{"comment_id": commentID},
+		"recipient": recipient,
 		"message":   map[string]string{"text": message},
 	}
 
 	body, _ := json.Marshal(payload)
 	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return "", fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -554,16 +711,20 @@ func sendPrivateReply(accountID, token, commentID, message string) error {
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("send request: %w", err)
+		return "", fmt.Errorf("send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		return fmt.Errorf("instagram API error %d: %s", resp.StatusCode, string(respBody))
+		return "", fmt.Errorf("instagram API error %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	return nil
+	var result struct {
+		MessageID string `json:"message_id"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	return result.MessageID, nil
 }
 
 // replaceTemplateVars substitutes {{username}} and {{keyword}} in a message.
@@ -603,12 +764,13 @@ func sendCommentReply(token, commentID, message string) error {
 }
 
 // logAutoReply inserts an auto-reply log entry and upserts the lead when status is "sent".
-func logAutoReply(ctx context.Context, rule models.AutoReplyRule, triggerType, senderIGID, senderUsername, triggerText, responseSent, commentReplySent, status, errMsg string, orgID primitive.ObjectID) {
+func logAutoReply(ctx context.Context, rule models.AutoReplyRule, channel, triggerType, senderIGID, senderUsername, triggerText, responseSent, commentReplySent, status, errMsg string, orgID primitive.ObjectID) {
 	logEntry := models.AutoReplyLog{
 		RuleID:           rule.ID,
 		OrgID:            orgID,
 		RuleName:         rule.Name,
 		TriggerType:      triggerType,
+		Channel:          channel,
 		SenderIGID:       senderIGID,
 		SenderUsername:   senderUsername,
 		TriggerText:      triggerText,
@@ -626,27 +788,56 @@ func logAutoReply(ctx context.Context, rule models.AutoReplyRule, triggerType, s
 
 	// Upsert lead when a DM was actually sent
 	if status == "sent" {
-		upsertInstagramLead(ctx, senderIGID, senderUsername, triggerType, rule.Name)
+		source := triggerType
+		if channel == "facebook" {
+			source = "facebook_" + triggerType
+		}
+		upsertInstagramLead(ctx, senderIGID, senderUsername, source, rule.Name, orgID)
 	}
 }
 
 // upsertInstagramLead creates or updates a lead entry.
-func upsertInstagramLead(ctx context.Context, senderIGID, senderUsername, source, ruleName string) {
+// ruleName may be empty for interactions that didn't trigger a rule (e.g. mentions).
+// senderIGID may be empty when only the username is known (caption and comment
+// mentions); the interaction then joins the org's lead with that username.
+func upsertInstagramLead(ctx context.Context, senderIGID, senderUsername, source, ruleName string, orgID primitive.ObjectID) {
 	now := time.Now()
 	filter := bson.M{"sender_ig_id": senderIGID}
+	if senderIGID == "" {
+		if senderUsername == "" || orgID == primitive.NilObjectID {
+			return
+		}
+		filter = bson.M{"org_id": orgID, "sender_username": senderUsername}
+	}
+	set := bson.M{
+		"last_interaction": now,
+		"updated_at":       now,
+	}
+	if senderUsername != "" {
+		set["sender_username"] = senderUsername
+	}
+	if orgID != primitive.NilObjectID {
+		set["org_id"] = orgID
+	}
+	setOnInsert := bson.M{
+		"first_interaction": now,
+		"tags":              []string{},
+		"created_at":        now,
+	}
+	if senderIGID == "" {
+		setOnInsert["sender_ig_id"] = ""
+	}
+	addToSet := bson.M{"sources": source}
+	if ruleName != "" {
+		addToSet["rules_triggered"] = ruleName
+	} else {
+		setOnInsert["rules_triggered"] = []string{}
+	}
 	update := bson.M{
-		"$set": bson.M{
-			"sender_username":  senderUsername,
-			"last_interaction": now,
-			"updated_at":       now,
-		},
-		"$inc":      bson.M{"interaction_count": 1},
-		"$addToSet": bson.M{"sources": source, "rules_triggered": ruleName},
-		"$setOnInsert": bson.M{
-			"first_interaction": now,
-			"tags":              []string{},
-			"created_at":        now,
-		},
+		"$set":         set,
+		"$inc":         bson.M{"interaction_count": 1},
+		"$addToSet":    addToSet,
+		"$setOnInsert": setOnInsert,
 	}
 
 	opts := options.Update().SetUpsert(true)
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// igWebhookMaxAttempts is how many times an event is tried before it's left as failed.
+const igWebhookMaxAttempts = 5
+
+// storeWebhookEvent persists a verified payload with status "pending".
+func storeWebhookEvent(object string, body []byte) (primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	evt := models.IGWebhookEvent{
+		ID:         primitive.NewObjectID(),
+		Object:     object,
+		Status:     "pending",
+		RawPayload: body,
+		ReceivedAt: now,
+		UpdatedAt:  now,
+	}
+	if _, err := database.IGWebhookEvents().InsertOne(ctx, evt); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return evt.ID, nil
+}
+
+// runWebhookEvent processes a stored event and records the outcome.
+// When force is true, items already processed are handled again (replay).
+func runWebhookEvent(eventID primitive.ObjectID, payload webhookPayload, force bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var evt models.IGWebhookEvent
+	err := database.IGWebhookEvents().FindOneAndUpdate(ctx,
+		bson.M{"_id": eventID},
+		bson.M{
+			"$set": bson.M{"status": "processing", "updated_at": time.Now()},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&evt)
+	if err != nil {
+		slog.Error("webhook_event_mark_processing", "error", err, "event_id", eventID.Hex())
+		return
+	}
+
+	procErr := processWebhookPayload(payload, force)
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel2()
+
+	now := time.Now()
+	update := bson.M{"updated_at": now}
+	unset := bson.M{}
+	if procErr == nil {
+		update["status"] = "processed"
+		update["processed_at"] = now
+		unset["last_error"] = ""
+		unset["next_retry_at"] = ""
+	} else {
+		update["status"] = "failed"
+		update["last_error"] = procErr.Error()
+		if evt.Attempts < igWebhookMaxAttempts {
+			// Exponential backoff: 1, 2, 4, 8 minutes
+			next := now.Add(time.Duration(1<<(evt.Attempts-1)) * time.Minute)
+			update["next_retry_at"] = next
+		} else {
+			unset["next_retry_at"] = ""
+		}
+		slog.Error("webhook_event_failed", "event_id", eventID.Hex(), "attempts", evt.Attempts, "error", procErr)
+	}
+
+	change := bson.M{"$set": update}
+	if len(unset) > 0 {
+		change["$unset"] = unset
+	}
+	if _, err := database.IGWebhookEvents().UpdateOne(ctx2, bson.M{"_id": eventID}, change); err != nil {
+		slog.Error("webhook_event_mark_result", "error", err, "event_id", eventID.Hex())
+	}
+}
+
+// igWebhookItemLease is how long a claimed item may stay "processing" before
+// another delivery or retry can take it over (e.g. the instance restarted mid-way).
+const igWebhookItemLease = 5 * time.Minute
+
+// claimWebhookItem marks an item (comment ID, message MID, ...) as processing
+// under a lease. Returns false if it is done or another worker holds a live
+// lease, which makes Meta retries and overlapping deliveries no-ops.
+func claimWebhookItem(key string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	_, err := database.IGWebhookItems().InsertOne(ctx, bson.M{
+		"_id":         key,
+		"status":      "processing",
+		"lease_until": now.Add(igWebhookItemLease),
+		"created_at":  now,
+	})
+	if err == nil {
+		return true
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		// Don't drop events because the dedup store is unavailable
+		slog.Error("webhook_item_claim_error", "error", err, "key", key)
+		return true
+	}
+
+	// Take over a claim whose holder never finished
+	res, err := database.IGWebhookItems().UpdateOne(ctx,
+		bson.M{"_id": key, "status": "processing", "lease_until": bson.M{"$lt": now}},
+		bson.M{"$set": bson.M{"lease_until": now.Add(igWebhookItemLease)}},
+	)
+	if err != nil {
+		slog.Error("webhook_item_claim_error", "error", err, "key", key)
+		return false
+	}
+	return res.MatchedCount > 0
+}
+
+// completeWebhookItem marks a claimed item as handled for good.
+func completeWebhookItem(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := database.IGWebhookItems().UpdateOne(ctx, bson.M{"_id": key}, bson.M{
+		"$set":   bson.M{"status": "done"},
+		"$unset": bson.M{"lease_until": ""},
+	}); err != nil {
+		slog.Error("webhook_item_complete_error", "error", err, "key", key)
+	}
+}
+
+// releaseWebhookItem removes a claim so a failed item can be retried.
+func releaseWebhookItem(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := database.IGWebhookItems().DeleteOne(ctx, bson.M{"_id": key}); err != nil {
+		slog.Error("webhook_item_release_error", "error", err, "key", key)
+	}
+}
+
+// processWebhookItem runs fn once per key. The claim only becomes permanent once
+// fn succeeds; failed items are released for retry.
+func processWebhookItem(key string, force bool, fn func() error) error {
+	if !force && !claimWebhookItem(key) {
+		slog.Info("webhook_item_duplicate", "key", key)
+		return nil
+	}
+
+	if err := fn(); err != nil {
+		if !force {
+			releaseWebhookItem(key)
+		}
+		return err
+	}
+	if !force {
+		completeWebhookItem(key)
+	}
+	return nil
+}
+
+// ─── Retry worker ────────────────────────────────────────────────────
+
+// RetryFailedWebhookEvents reprocesses failed events whose backoff has elapsed,
+// plus events stuck in pending/processing (e.g. the instance restarted mid-way).
+func RetryFailedWebhookEvents() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	stale := now.Add(-10 * time.Minute)
+	filter := bson.M{
+		"attempts": bson.M{"$lt": igWebhookMaxAttempts},
+		"$or": []bson.M{
+			{"status": "failed", "next_retry_at": bson.M{"$lte": now}},
+			{"status": bson.M{"$in": []string{"pending", "processing"}}, "updated_at": bson.M{"$lte": stale}},
+		},
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "received_at", Value: 1}}).SetLimit(100)
+	cursor, err := database.IGWebhookEvents().Find(ctx, filter, opts)
+	if err != nil {
+		slog.Error("webhook_retry_find", "error", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.IGWebhookEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		slog.Error("webhook_retry_decode", "error", err)
+		return
+	}
+
+	for _, evt := range events {
+		var payload webhookPayload
+		if err := json.Unmarshal(evt.RawPayload, &payload); err != nil {
+			slog.Error("webhook_retry_parse", "error", err, "event_id", evt.ID.Hex())
+			continue
+		}
+		runWebhookEvent(evt.ID, payload, false)
+	}
+
+	if len(events) > 0 {
+		slog.Info("webhook_retry_cycle_complete", "events", len(events))
+	}
+}
+
+// ─── Platform endpoints (superadmin only) ────────────────────────────
+
+// PlatformListIGWebhookEvents returns stored Instagram webhook events.
+// @Summary Listar eventos de webhook do Instagram
+// @Description Lista os eventos de webhook do Instagram armazenados, do mais recente ao mais antigo (somente superadmin)
+// @Tags platform
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 50, máx 100)"
+// @Param status query string false "Filtrar por status (pending, processing, processed, failed)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error listing webhook events"
+// @Router /platform/ig-webhook-events [get]
+func PlatformListIGWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	filter := bson.M{}
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter["status"] = status
+	}
+
+	total, _ := database.IGWebhookEvents().CountDocuments(ctx, filter)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "received_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.IGWebhookEvents().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error listing webhook events", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.IGWebhookEvent
+	cursor.All(ctx, &events)
+	if events == nil {
+		events = []models.IGWebhookEvent{}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events": events,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+// PlatformReplayIGWebhookEvents replays one stored event or every event in a time range.
+// @Summary Reprocessar eventos de webhook do Instagram
+// @Description Reprocessa um evento armazenado ou todos os eventos de um intervalo, em segundo plano (somente superadmin)
+// @Tags platform
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.ReplayIGWebhookEventsRequest true "Evento ou intervalo a reprocessar"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {string} string "Invalid request"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "No webhook events found"
+// @Router /platform/ig-webhook-events/replay [post]
+func PlatformReplayIGWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	var req models.ReplayIGWebhookEventsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	filter := bson.M{}
+	switch {
+	case req.EventID != "":
+		oid, err := primitive.ObjectIDFromHex(req.EventID)
+		if err != nil {
+			http.Error(w, "Invalid event_id", http.StatusBadRequest)
+			return
+		}
+		filter["_id"] = oid
+	case req.From != "" && req.To != "":
+		from, err1 := time.Parse(time.RFC3339, req.From)
+		to, err2 := time.Parse(time.RFC3339, req.To)
+		if err := errors.Join(err1, err2); err != nil || !to.After(from) {
+			http.Error(w, "Invalid from/to (RFC3339, from < to)", http.StatusBadRequest)
+			return
+		}
+		filter["received_at"] = bson.M{"$gte": from, "$lte": to}
+		if req.Status != "" {
+			filter["status"] = req.Status
+		}
+	default:
+		http.Error(w, "Provide event_id or from/to", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "received_at", Value: 1}}).SetLimit(500)
+	cursor, err := database.IGWebhookEvents().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error loading webhook events", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.IGWebhookEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		http.Error(w, "Error loading webhook events", http.StatusInternalServerError)
+		return
+	}
+	if len(events) == 0 {
+		http.Error(w, "No webhook events found", http.StatusNotFound)
+		return
+	}
+
+	// Replay sequentially in the background, oldest first
+	go func(events []models.IGWebhookEvent, force bool) {
+		for _, evt := range events {
+			var payload webhookPayload
+			if err := json.Unmarshal(evt.RawPayload, &payload); err != nil {
+				slog.Error("webhook_replay_parse", "error", err, "event_id", evt.ID.Hex())
+				continue
+			}
+			runWebhookEvent(evt.ID, payload, force)
+		}
+		slog.Info("webhook_replay_complete", "events", len(events), "force", force)
+	}(events, req.Force)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Replay started",
+		"events":  len(events),
+		"force":   req.Force,
+	})
+}
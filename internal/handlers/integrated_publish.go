@@ -31,7 +31,7 @@ import (
 // @Security BearerAuth
 // @Param body body models.CreateIntegratedPublishRequest true "Dados da publicação"
 // @Success 201 {object} models.IntegratedPublishResponse
-// @Failure 400 {string} string "Dados inválidos"
+// @Failure 400 {object} models.PostValidationResult "Validation failed"
 // @Failure 401 {string} string "Unauthorized"
 // @Failure 500 {string} string "Error creating integrated publish"
 // @Router /admin/integrated-publish [post]
@@ -49,28 +49,6 @@ func CreateIntegratedPublish(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate Instagram fields
-	if len(req.ImageIDs) == 0 {
-		http.Error(w, "At least one image is required", http.StatusBadRequest)
-		return
-	}
-	if req.MediaType != "image" && req.MediaType != "carousel" {
-		http.Error(w, "media_type must be 'image' or 'carousel'", http.StatusBadRequest)
-		return
-	}
-	if req.MediaType == "image" && len(req.ImageIDs) > 1 {
-		http.Error(w, "image type allows only one image; use 'carousel' for multiple", http.StatusBadRequest)
-		return
-	}
-	if req.MediaType == "carousel" && len(req.ImageIDs) < 2 {
-		http.Error(w, "carousel requires at least 2 images", http.StatusBadRequest)
-		return
-	}
-	if len(req.Caption) > 2200 {
-		http.Error(w, "Caption must be 2200 characters or less", http.StatusBadRequest)
-		return
-	}
-
 	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
 	if err != nil {
 		http.Error(w, "scheduled_at must be a valid ISO 8601 date", http.StatusBadRequest)
@@ -110,18 +88,10 @@ func CreateIntegratedPublish(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Verify all images exist
-	for _, imgID := range req.ImageIDs {
-		oid, err := primitive.ObjectIDFromHex(imgID)
-		if err != nil {
-			http.Error(w, "Invalid image ID: "+imgID, http.StatusBadRequest)
-			return
-		}
-		count, err := database.Images().CountDocuments(ctx, bson.M{"_id": oid})
-		if err != nil || count == 0 {
-			http.Error(w, "Image not found: "+imgID, http.StatusBadRequest)
-			return
-		}
+	// Validate caption and media against Instagram limits
+	if res := validateInstagramPost(ctx, req.Caption, req.MediaType, req.ImageIDs); !res.Valid {
+		writeValidationErrors(w, res)
+		return
 	}
 
 	// Verify credentials exist
@@ -136,6 +106,8 @@ func CreateIntegratedPublish(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	status, approvals := initialApprovalState(ctx, orgID, userID)
+
 	now := time.Now()
 	pub := models.IntegratedPublish{
 		ID:                 primitive.NewObjectID(),
@@ -145,9 +117,10 @@ func CreateIntegratedPublish(w http.ResponseWriter, r *http.Request) {
 		MediaType:          req.MediaType,
 		ImageIDs:           req.ImageIDs,
 		ScheduledAt:        scheduledAt,
-		Status:             "scheduled",
+		Status:             status,
 		Campaign:           req.Campaign,
 		ExistingCampaignID: req.ExistingCampaignID,
+		Approvals:          approvals,
 		CreatedAt:          now,
 		UpdatedAt:          now,
 	}
@@ -157,6 +130,9 @@ func CreateIntegratedPublish(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error creating integrated publish", http.StatusInternalServerError)
 		return
 	}
+	if status == "pending_approval" {
+		notifyReviewers(ctx, orgID, 1, pub.Caption)
+	}
 
 	slog.Info("integrated_publish_created",
 		"id", pub.ID.Hex(),
@@ -229,9 +205,16 @@ func ListIntegratedPublishes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]primitive.ObjectID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	counts := contentCommentCounts(ctx, orgID, "integrated_publish", ids)
+
 	responses := make([]models.IntegratedPublishResponse, len(items))
 	for i, item := range items {
 		responses[i] = buildIPResponse(item)
+		responses[i].CommentCount = counts[item.ID]
 	}
 
 	json.NewEncoder(w).Encode(models.IntegratedPublishListResponse{
@@ -407,6 +390,7 @@ func DeleteIntegratedPublish(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error deleting record", http.StatusInternalServerError)
 		return
 	}
+	deleteContentComments(ctx, orgID, "integrated_publish", oid)
 
 	slog.Info("integrated_publish_deleted", "id", oid.Hex(), "org_id", orgID.Hex())
 	json.NewEncoder(w).Encode(map[string]string{"message": "Integrated publish deleted"})
@@ -472,7 +456,7 @@ func processIntegratedPublish(ctx context.Context, pub models.IntegratedPublish)
 
 	// Save ig_media_id
 	database.IntegratedPublishes().UpdateOne(ctx, bson.M{"_id": pub.ID}, bson.M{
-		"$set": bson.M{"ig_media_id": mediaID, "updated_at": time.Now()},
+		"$set": bson.M{"ig_media_id": mediaID, "ig_published_at": time.Now(), "updated_at": time.Now()},
 	})
 
 	slog.Info("integrated_publish_ig_done", "id", pub.ID.Hex(), "ig_media_id", mediaID)
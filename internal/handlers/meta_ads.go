@@ -140,11 +140,24 @@ func GetMetaAdsConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	json.NewEncoder(w).Encode(models.MetaAdsConfigResponse{
+	resp := models.MetaAdsConfigResponse{
 		Configured:  true,
 		AdAccountID: creds.AdAccountID,
 		Source:      creds.Source,
-	})
+	}
+	switch creds.Source {
+	case "user":
+		var cfg models.MetaAdsConfig
+		if database.MetaAdsConfigs().FindOne(ctx, bson.M{"org_id": orgID}).Decode(&cfg) == nil {
+			resp.Token = tokenHealth(cfg.TokenMeta)
+		}
+	case "instagram":
+		var cfg models.InstagramConfig
+		if database.InstagramConfigs().FindOne(ctx, bson.M{"org_id": orgID, "ad_account_id": bson.M{"$ne": ""}}).Decode(&cfg) == nil {
+			resp.Token = tokenHealth(cfg.TokenMeta)
+		}
+	}
+	json.NewEncoder(w).Encode(resp)
 }
 
 func SaveMetaAdsConfig(w http.ResponseWriter, r *http.Request) {
@@ -176,6 +189,7 @@ func SaveMetaAdsConfig(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Encryption error", http.StatusInternalServerError)
 		return
 	}
+	tokenMeta := newTokenMeta(req.AccessToken, 0)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -189,6 +203,7 @@ func SaveMetaAdsConfig(w http.ResponseWriter, r *http.Request) {
 			"ad_account_id":    req.AdAccountID,
 			"business_id":      req.BusinessID,
 			"access_token_enc": encToken,
+			"token_meta":       tokenMeta,
 			"updated_at":       now,
 		},
 		"$setOnInsert": bson.M{"created_at": now},
@@ -131,11 +131,12 @@ func MetaOAuthCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Step 2: Exchange for long-lived token
-	longToken, err := ExchangeForLongLivedToken(shortToken)
+	longToken, expiresIn, err := ExchangeForLongLivedToken(shortToken)
 	if err != nil {
 		slog.Warn("meta_oauth_long_token_failed, using short token", "error", err)
 		longToken = shortToken
 	}
+	tokenMeta := newTokenMeta(longToken, expiresIn)
 
 	// Step 3: Fetch pages + instagram_business_account(s)
 	igAccounts, igReason, igErr := fetchInstagramAccounts(longToken)
@@ -183,6 +184,7 @@ func MetaOAuthCallback(w http.ResponseWriter, r *http.Request) {
 		filter := bson.M{"org_id": orgID, "instagram_account_id": acc.IGAccountID}
 		setFields := bson.M{
 			"access_token_enc": tokenEnc,
+			"token_meta":       tokenMeta,
 			"username":         acc.Username,
 			"page_name":        acc.PageName,
 			"business_id":      acc.PageID,
@@ -218,6 +220,7 @@ func MetaOAuthCallback(w http.ResponseWriter, r *http.Request) {
 		update := bson.M{
 			"$set": bson.M{
 				"access_token_enc": tokenEnc,
+				"token_meta":       tokenMeta,
 				"updated_at":       now,
 			},
 			"$setOnInsert": bson.M{
@@ -247,6 +250,7 @@ func MetaOAuthCallback(w http.ResponseWriter, r *http.Request) {
 				fbSetFields := bson.M{
 					"page_id":               fbPageID,
 					"page_access_token_enc": pageTokenEnc,
+					"token_meta":            newTokenMeta(pageToken, 0),
 					"page_name":             fbPageName,
 					"updated_at":            now,
 				}
@@ -263,6 +267,7 @@ func MetaOAuthCallback(w http.ResponseWriter, r *http.Request) {
 				if fbSaveErr != nil {
 					slog.Warn("meta_oauth_fb_save_failed", "error", fbSaveErr)
 				} else {
+					go subscribeFacebookPageWebhooks(fbPageID, pageToken)
 					slog.Info("facebook_config_auto_saved",
 						"org_id", orgID.Hex(),
 						"page_id", fbPageID,
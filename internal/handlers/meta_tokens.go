@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/tron-legacy/api/internal/config"
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// tokenRefreshWindow is how long before expiry a token is refreshed (and reported as expiring).
+const tokenRefreshWindow = 10 * 24 * time.Hour
+
+// debugMetaToken inspects a token with /debug_token using the app access token.
+func debugMetaToken(token string) (*models.TokenMeta, error) {
+	cfg := config.Get()
+	if cfg.MetaAppID == "" || cfg.MetaAppSecret == "" {
+		return nil, fmt.Errorf("META_APP_ID or META_APP_SECRET not configured")
+	}
+
+	params := url.Values{}
+	params.Set("input_token", token)
+	result, err := metaGraphGet("/debug_token", cfg.MetaAppID+"|"+cfg.MetaAppSecret, params)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := result["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("empty debug_token response")
+	}
+
+	now := time.Now()
+	meta := &models.TokenMeta{CheckedAt: &now}
+	meta.Type, _ = data["type"].(string)
+	if exp, _ := data["expires_at"].(float64); exp > 0 {
+		t := time.Unix(int64(exp), 0)
+		meta.ExpiresAt = &t
+	}
+	if exp, _ := data["data_access_expires_at"].(float64); exp > 0 {
+		t := time.Unix(int64(exp), 0)
+		meta.DataAccessExpiresAt = &t
+	}
+	scopes, _ := data["scopes"].([]interface{})
+	for _, s := range scopes {
+		if scope, ok := s.(string); ok {
+			meta.Scopes = append(meta.Scopes, scope)
+		}
+	}
+
+	if valid, _ := data["is_valid"].(bool); !valid {
+		meta.Status = "invalid"
+		if e, ok := data["error"].(map[string]interface{}); ok {
+			meta.Error, _ = e["message"].(string)
+		}
+		return meta, nil
+	}
+	meta.Status = tokenStatusAt(meta.ExpiresAt, now)
+	return meta, nil
+}
+
+// tokenStatusAt derives the status of a valid token from its expiry.
+func tokenStatusAt(expiresAt *time.Time, now time.Time) string {
+	switch {
+	case expiresAt == nil:
+		return "valid"
+	case now.After(*expiresAt):
+		return "expired"
+	case expiresAt.Sub(now) < tokenRefreshWindow:
+		return "expiring"
+	default:
+		return "valid"
+	}
+}
+
+// newTokenMeta builds the metadata stored with a freshly connected token. debug_token is
+// preferred; when it is unavailable, the expiry comes from expires_in (0 = unknown).
+func newTokenMeta(token string, expiresIn int) *models.TokenMeta {
+	meta, err := debugMetaToken(token)
+	if err == nil {
+		return meta
+	}
+	slog.Warn("token_debug_failed", "error", err)
+
+	meta = &models.TokenMeta{}
+	if expiresIn > 0 {
+		t := time.Now().Add(time.Duration(expiresIn) * time.Second)
+		meta.ExpiresAt = &t
+	}
+	meta.Status = tokenStatusAt(meta.ExpiresAt, time.Now())
+	return meta
+}
+
+// tokenHealth converts stored token metadata into the config GET status.
+func tokenHealth(meta *models.TokenMeta) *models.TokenHealth {
+	if meta == nil {
+		return &models.TokenHealth{Status: "unknown"}
+	}
+	status := meta.Status
+	if status != "invalid" {
+		status = tokenStatusAt(meta.ExpiresAt, time.Now())
+	}
+	return &models.TokenHealth{
+		Status:         status,
+		ExpiresAt:      meta.ExpiresAt,
+		Scopes:         meta.Scopes,
+		Error:          meta.Error,
+		CheckedAt:      meta.CheckedAt,
+		NeedsReconnect: status == "invalid" || status == "expired",
+	}
+}
+
+// ─── Refresh job ─────────────────────────────────────────────────────
+
+// storedToken is a token held by one of the Meta config collections.
+type storedToken struct {
+	Coll        *mongo.Collection
+	ID          primitive.ObjectID
+	OrgID       primitive.ObjectID
+	Label       string // "Instagram", "Facebook", "Meta Ads"
+	TokenField  string // encrypted token field name
+	TokenEnc    string
+	Meta        *models.TokenMeta
+	Refreshable bool // user tokens can be exchanged again; page tokens cannot
+}
+
+// RefreshMetaTokens checks every stored Meta token with debug_token, refreshes tokens
+// that are about to expire and marks invalid ones. Owners are notified once per status change.
+func RefreshMetaTokens() {
+	cfg := config.Get()
+	if !crypto.Available() || cfg.MetaAppID == "" || cfg.MetaAppSecret == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var tokens []storedToken
+
+	var igCfgs []models.InstagramConfig
+	if cursor, err := database.InstagramConfigs().Find(ctx, bson.M{}); err == nil {
+		cursor.All(ctx, &igCfgs)
+		cursor.Close(ctx)
+	}
+	for _, c := range igCfgs {
+		tokens = append(tokens, storedToken{database.InstagramConfigs(), c.ID, c.OrgID, "Instagram", "access_token_enc", c.AccessTokenEnc, c.TokenMeta, true})
+	}
+
+	var fbCfgs []models.FacebookConfig
+	if cursor, err := database.FacebookConfigs().Find(ctx, bson.M{}); err == nil {
+		cursor.All(ctx, &fbCfgs)
+		cursor.Close(ctx)
+	}
+	for _, c := range fbCfgs {
+		tokens = append(tokens, storedToken{database.FacebookConfigs(), c.ID, c.OrgID, "Facebook", "page_access_token_enc", c.PageAccessToken, c.TokenMeta, false})
+	}
+
+	var adsCfgs []models.MetaAdsConfig
+	if cursor, err := database.MetaAdsConfigs().Find(ctx, bson.M{}); err == nil {
+		cursor.All(ctx, &adsCfgs)
+		cursor.Close(ctx)
+	}
+	for _, c := range adsCfgs {
+		tokens = append(tokens, storedToken{database.MetaAdsConfigs(), c.ID, c.OrgID, "Meta Ads", "access_token_enc", c.AccessTokenEnc, c.TokenMeta, true})
+	}
+
+	notified := map[string]bool{} // org+label+status, so accounts sharing a token notify once
+	refreshed, invalid := 0, 0
+	for _, t := range tokens {
+		if t.TokenEnc == "" {
+			continue
+		}
+		token, err := crypto.Decrypt(t.TokenEnc)
+		if err != nil {
+			slog.Warn("token_refresh_decrypt", "label", t.Label, "org_id", t.OrgID.Hex(), "error", err)
+			continue
+		}
+
+		meta, err := debugMetaToken(token)
+		if err != nil {
+			slog.Warn("token_refresh_debug", "label", t.Label, "org_id", t.OrgID.Hex(), "error", err)
+			continue // transient; keep the last known status
+		}
+
+		set := bson.M{}
+		exchanged := false
+		if meta.Status == "expiring" && t.Refreshable {
+			newToken, expiresIn, err := ExchangeForLongLivedToken(token)
+			if err == nil {
+				enc, encErr := crypto.Encrypt(newToken)
+				if encErr == nil {
+					now := time.Now()
+					meta = newTokenMeta(newToken, expiresIn)
+					meta.RefreshedAt = &now
+					set[t.TokenField] = enc
+					exchanged = true
+				}
+			} else {
+				meta.Error = "refresh failed: " + err.Error()
+				slog.Warn("token_refresh_exchange", "label", t.Label, "org_id", t.OrgID.Hex(), "error", err)
+			}
+		}
+
+		if t.Meta != nil {
+			meta.NotifiedStatus = t.Meta.NotifiedStatus
+			if meta.RefreshedAt == nil {
+				meta.RefreshedAt = t.Meta.RefreshedAt
+			}
+		}
+		notify := false
+		if meta.Status == "valid" {
+			meta.NotifiedStatus = ""
+		} else if meta.NotifiedStatus != meta.Status {
+			notify = true
+			meta.NotifiedStatus = meta.Status
+		}
+
+		// Only write over the token this check ran against: a reconnect while the
+		// check was in flight must not be replaced by the old token or its status
+		set["token_meta"] = meta
+		res, err := t.Coll.UpdateOne(ctx, bson.M{"_id": t.ID, t.TokenField: t.TokenEnc}, bson.M{"$set": set})
+		if err != nil {
+			slog.Error("token_refresh_update", "label", t.Label, "org_id", t.OrgID.Hex(), "error", err)
+			continue
+		}
+		if res.MatchedCount == 0 {
+			slog.Info("token_refresh_skipped_changed", "label", t.Label, "org_id", t.OrgID.Hex())
+			continue
+		}
+
+		if exchanged {
+			refreshed++
+			slog.Info("token_refreshed", "label", t.Label, "org_id", t.OrgID.Hex())
+		}
+		if notify {
+			key := t.OrgID.Hex() + t.Label + meta.Status
+			if !notified[key] {
+				notified[key] = true
+				title, message := tokenNotificationText(t.Label, meta)
+				notifyOrgOwners(ctx, t.OrgID, "token_"+meta.Status, title, message, "/admin/settings")
+			}
+		}
+		if meta.Status == "invalid" || meta.Status == "expired" {
+			invalid++
+		}
+	}
+
+	slog.Info("token_refresh_cycle_complete", "tokens", len(tokens), "refreshed", refreshed, "invalid", invalid)
+}
+
+func tokenNotificationText(label string, meta *models.TokenMeta) (string, string) {
+	switch meta.Status {
+	case "expiring":
+		when := "em breve"
+		if meta.ExpiresAt != nil {
+			when = "em " + meta.ExpiresAt.Format("02/01/2006")
+		}
+		return fmt.Sprintf("Conexão com %s expira %s", label, when),
+			fmt.Sprintf("Não foi possível renovar automaticamente o token do %s. Reconecte a conta nas configurações para evitar que os agendamentos falhem.", label)
+	case "expired":
+		return fmt.Sprintf("Conexão com %s expirou", label),
+			fmt.Sprintf("O token do %s expirou e as publicações agendadas vão falhar. Reconecte a conta nas configurações.", label)
+	default:
+		msg := fmt.Sprintf("O token do %s foi invalidado pela Meta (senha alterada, permissões removidas ou app desconectado). Reconecte a conta nas configurações.", label)
+		if meta.Error != "" {
+			msg += " Detalhe: " + meta.Error
+		}
+		return fmt.Sprintf("Conexão com %s precisa ser refeita", label), msg
+	}
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/config"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListNotifications returns the caller's notifications in the current org, newest first.
+// @Summary Listar notificações
+// @Description Lista as notificações do usuário na organização atual
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Somente não lidas"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} models.NotificationListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/notifications [get]
+func ListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"org_id": orgID, "user_id": userID}
+	if r.URL.Query().Get("unread") == "true" {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.Notifications().CountDocuments(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error listing notifications", http.StatusInternalServerError)
+		return
+	}
+	unread, _ := database.Notifications().CountDocuments(ctx, bson.M{"org_id": orgID, "user_id": userID, "read_at": bson.M{"$exists": false}})
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := database.Notifications().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error listing notifications", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []models.Notification
+	cursor.All(ctx, &notifications)
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	json.NewEncoder(w).Encode(models.NotificationListResponse{
+		Notifications: notifications,
+		Unread:        unread,
+		Total:         total,
+		Page:          page,
+		Limit:         limit,
+	})
+}
+
+// MarkNotificationRead marks one notification as read.
+// @Summary Marcar notificação como lida
+// @Description Marca uma notificação do usuário como lida
+// @Tags notifications
+// @Security BearerAuth
+// @Param id path string true "ID da notificação"
+// @Success 204
+// @Failure 404 {string} string "Notification not found"
+// @Router /admin/notifications/{id}/read [post]
+func MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := database.Notifications().UpdateOne(ctx,
+		bson.M{"_id": id, "org_id": middleware.GetOrgID(r), "user_id": middleware.GetUserID(r)},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		http.Error(w, "Error updating notification", http.StatusInternalServerError)
+		return
+	}
+	if res.MatchedCount == 0 {
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllNotificationsRead marks all of the caller's notifications in the org as read.
+// @Summary Marcar todas as notificações como lidas
+// @Description Marca todas as notificações do usuário na organização como lidas
+// @Tags notifications
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/notifications/read-all [post]
+func MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := database.Notifications().UpdateMany(ctx,
+		bson.M{"org_id": middleware.GetOrgID(r), "user_id": middleware.GetUserID(r), "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		http.Error(w, "Error updating notifications", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ─── Delivery ────────────────────────────────────────────────────────
+
+// notifyUsers stores an in-app notification for each user and, when email is set,
+// also sends it by email in the background. Delivery errors are logged, never returned.
+func notifyUsers(ctx context.Context, orgID primitive.ObjectID, userIDs []primitive.ObjectID, kind, title, message, link string, email bool) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(userIDs))
+	for _, uid := range userIDs {
+		docs = append(docs, models.Notification{
+			ID:        primitive.NewObjectID(),
+			OrgID:     orgID,
+			UserID:    uid,
+			Type:      kind,
+			Title:     title,
+			Message:   message,
+			Link:      link,
+			CreatedAt: now,
+		})
+	}
+	if _, err := database.Notifications().InsertMany(ctx, docs); err != nil {
+		slog.Error("notification_insert", "org_id", orgID.Hex(), "type", kind, "error", err)
+	}
+
+	if !email {
+		return
+	}
+	go sendNotificationEmails(userIDs, kind, title, message, link)
+}
+
+// sendNotificationEmails emails a notification to each user. It runs in the
+// background with its own deadline so callers don't wait on the email API.
+func sendNotificationEmails(userIDs []primitive.ObjectID, kind, title, message, link string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cursor, err := database.Users().Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		slog.Error("notification_users", "error", err)
+		return
+	}
+	var users []models.User
+	cursor.All(ctx, &users)
+	cursor.Close(ctx)
+
+	for _, u := range users {
+		if u.Email == "" {
+			continue
+		}
+		if ctx.Err() != nil {
+			slog.Warn("notification_email_timeout", "type", kind, "remaining_user_id", u.ID.Hex())
+			return
+		}
+		if err := sendNotificationEmail(u.Email, title, message, link); err != nil {
+			slog.Warn("notification_email_failed", "type", kind, "user_id", u.ID.Hex(), "error", err)
+		}
+	}
+}
+
+// notifyOrgOwners notifies every owner of the org in-app and by email.
+func notifyOrgOwners(ctx context.Context, orgID primitive.ObjectID, kind, title, message, link string) {
+	cursor, err := database.OrgMemberships().Find(ctx, bson.M{"org_id": orgID, "org_role": "owner"})
+	if err != nil {
+		slog.Error("notification_owners", "org_id", orgID.Hex(), "error", err)
+		return
+	}
+	var members []models.OrgMembership
+	cursor.All(ctx, &members)
+	cursor.Close(ctx)
+
+	userIDs := make([]primitive.ObjectID, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+	notifyUsers(ctx, orgID, userIDs, kind, title, message, link, true)
+}
+
+// sendNotificationEmail sends a plain notification email via Resend.
+func sendNotificationEmail(to, title, message, link string) error {
+	cfg := config.Get()
+	if cfg.ResendAPIKey == "" || cfg.FromEmail == "" {
+		return fmt.Errorf("email not configured")
+	}
+
+	var action string
+	if link != "" {
+		action = fmt.Sprintf(`<p style="margin:24px 0 0;"><a href="%s" style="display:inline-block;padding:12px 24px;background:#a855f7;color:#ffffff;text-decoration:none;border-radius:8px;font-weight:600;">Abrir no Whodo</a></p>`,
+			html.EscapeString(strings.TrimRight(cfg.FrontendURL, "/")+link))
+	}
+	body := fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+<body style="margin:0;padding:32px 20px;background-color:#09090b;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;color:#e4e4e7;">
+  <div style="max-width:520px;margin:0 auto;">
+    <h1 style="margin:0 0 24px;font-size:24px;font-weight:700;color:#a855f7;">Whodo</h1>
+    <h2 style="margin:0 0 12px;font-size:18px;color:#fafafa;">%s</h2>
+    <p style="margin:0;font-size:15px;line-height:1.6;">%s</p>
+    %s
+  </div>
+</body>
+</html>`, html.EscapeString(title), html.EscapeString(message), action)
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{
+		"from":    cfg.FromEmail,
+		"to":      []string{to},
+		"subject": title + " - Whodo",
+		"html":    body,
+	})
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, _ := http.NewRequest("POST", "https://api.resend.com/emails", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+cfg.ResendAPIKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("resend call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated {
+		return nil
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("resend status %d: %s", resp.StatusCode, string(respBody))
+}
@@ -104,6 +104,7 @@ func CreateOrg(w http.ResponseWriter, r *http.Request) {
 		Settings: models.OrgSettings{
 			DefaultLanguage: "pt-BR",
 			DefaultCurrency: "BRL",
+			Timezone:        defaultOrgTimezone,
 		},
 		CreatedAt: now,
 		UpdatedAt: now,
@@ -282,6 +283,12 @@ func UpdateOrg(w http.ResponseWriter, r *http.Request) {
 		update["logo_url"] = *req.LogoURL
 	}
 	if req.Settings != nil {
+		if req.Settings.Timezone != "" {
+			if _, err := time.LoadLocation(req.Settings.Timezone); err != nil {
+				http.Error(w, "Invalid timezone", http.StatusBadRequest)
+				return
+			}
+		}
 		update["settings"] = *req.Settings
 	}
 
@@ -1637,6 +1644,7 @@ func CreateOrgForUser(ctx context.Context, userID primitive.ObjectID, userName s
 		Settings: models.OrgSettings{
 			DefaultLanguage: "pt-BR",
 			DefaultCurrency: "BRL",
+			Timezone:        defaultOrgTimezone,
 		},
 		CreatedAt: now,
 		UpdatedAt: now,
@@ -0,0 +1,833 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultSeriesDaysAhead = 14
+	maxSeriesDaysAhead     = 60
+	maxSeriesItems         = 100
+	defaultMinReuseDays    = 30
+)
+
+// seriesScheduleCollection returns the schedule collection a series materializes into.
+func seriesScheduleCollection(channel string) *mongo.Collection {
+	if channel == "facebook" {
+		return database.FacebookSchedules()
+	}
+	return database.InstagramSchedules()
+}
+
+// canScheduleChannel checks the channel's schedule permission for the caller.
+func canScheduleChannel(w http.ResponseWriter, r *http.Request, channel string) bool {
+	if middleware.HasPermission(r, channel+":schedule") {
+		return true
+	}
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message":             "Forbidden: insufficient permissions",
+		"required_permission": channel + ":schedule",
+	})
+	return false
+}
+
+// validateSeriesSlots checks weekdays and HH:MM times.
+func validateSeriesSlots(weekdays []int, times []string) string {
+	if len(weekdays) == 0 || len(times) == 0 {
+		return "weekdays and times are required"
+	}
+	for _, d := range weekdays {
+		if d < 0 || d > 6 {
+			return "weekdays must be between 0 (Sunday) and 6 (Saturday)"
+		}
+	}
+	for _, t := range times {
+		if _, err := time.Parse("15:04", t); err != nil {
+			return "times must be in HH:MM format"
+		}
+	}
+	return ""
+}
+
+// validateSeriesContent validates one post of a series for its channel. Instagram posts
+// go through the full pre-publish validation; it responds and returns false when invalid.
+func validateSeriesContent(ctx context.Context, w http.ResponseWriter, orgID primitive.ObjectID, channel string, c models.SeriesContent) bool {
+	if channel == "instagram" {
+		if res := validateInstagramPost(ctx, c.Caption, c.MediaType, c.ImageIDs); !res.Valid {
+			writeValidationErrors(w, res)
+			return false
+		}
+		return true
+	}
+
+	if msg := validateFacebookPost(ctx, orgID, c.Caption, c.MediaType, c.LinkURL, c.ImageIDs, ""); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// validateSeries checks the series definition, including every post it can publish.
+func validateSeries(ctx context.Context, w http.ResponseWriter, s *models.PostSeries) bool {
+	if msg := validateSeriesSlots(s.Weekdays, s.Times); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return false
+	}
+	if s.DaysAhead < 1 || s.DaysAhead > maxSeriesDaysAhead {
+		http.Error(w, fmt.Sprintf("days_ahead must be between 1 and %d", maxSeriesDaysAhead), http.StatusBadRequest)
+		return false
+	}
+	if s.StartAt != nil && s.EndAt != nil && !s.EndAt.After(*s.StartAt) {
+		http.Error(w, "end_at must be after start_at", http.StatusBadRequest)
+		return false
+	}
+
+	if s.Kind == "recurring" {
+		if s.Content == nil {
+			http.Error(w, "content is required for recurring series", http.StatusBadRequest)
+			return false
+		}
+		return validateSeriesContent(ctx, w, s.OrgID, s.Channel, *s.Content)
+	}
+
+	if len(s.Items) == 0 || len(s.Items) > maxSeriesItems {
+		http.Error(w, fmt.Sprintf("evergreen series need between 1 and %d items", maxSeriesItems), http.StatusBadRequest)
+		return false
+	}
+	if s.MinReuseDays < 0 {
+		http.Error(w, "min_reuse_days must not be negative", http.StatusBadRequest)
+		return false
+	}
+	for _, item := range s.Items {
+		if !validateSeriesContent(ctx, w, s.OrgID, s.Channel, item.SeriesContent) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseOptionalTime parses an optional ISO 8601 time; "" means unset.
+func parseOptionalTime(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ─── Handlers ────────────────────────────────────────────────────────
+
+// ListPostSeries returns the org's recurring and evergreen series.
+// @Summary Listar séries de posts
+// @Description Lista as séries recorrentes e filas evergreen da organização
+// @Tags post-series
+// @Produce json
+// @Security BearerAuth
+// @Param channel query string false "instagram ou facebook"
+// @Param kind query string false "recurring ou evergreen"
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 20, máx 100)"
+// @Success 200 {object} models.PostSeriesListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Router /admin/series [get]
+func ListPostSeries(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := bson.M{"org_id": orgID}
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		filter["channel"] = channel
+	}
+	if kind := r.URL.Query().Get("kind"); kind != "" {
+		filter["kind"] = kind
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.PostSeries().CountDocuments(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error listing series", http.StatusInternalServerError)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	cursor, err := database.PostSeries().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error listing series", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var series []models.PostSeries
+	if err := cursor.All(ctx, &series); err != nil {
+		http.Error(w, "Error decoding series", http.StatusInternalServerError)
+		return
+	}
+	if series == nil {
+		series = []models.PostSeries{}
+	}
+
+	json.NewEncoder(w).Encode(models.PostSeriesListResponse{
+		Series: series,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+	})
+}
+
+// CreatePostSeries creates a recurring series or an evergreen queue and materializes
+// its first occurrences.
+// @Summary Criar série de posts
+// @Description Cria um post recorrente (mesmo conteúdo em dias/horários fixos) ou uma fila evergreen (posts reciclados nos horários configurados, respeitando um intervalo mínimo antes de reutilizar um item). Os agendamentos são gerados com days_ahead dias de antecedência
+// @Tags post-series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreatePostSeriesRequest true "Série"
+// @Success 201 {object} models.PostSeries
+// @Failure 400 {string} string "Invalid request"
+// @Failure 403 {string} string "Forbidden"
+// @Router /admin/series [post]
+func CreatePostSeries(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+
+	var req models.CreatePostSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	if req.Kind != "recurring" && req.Kind != "evergreen" {
+		http.Error(w, "kind must be 'recurring' or 'evergreen'", http.StatusBadRequest)
+		return
+	}
+	if req.Channel != "instagram" && req.Channel != "facebook" {
+		http.Error(w, "channel must be 'instagram' or 'facebook'", http.StatusBadRequest)
+		return
+	}
+	if !canScheduleChannel(w, r, req.Channel) {
+		return
+	}
+
+	startAt, err := parseOptionalTime(req.StartAt)
+	if err != nil {
+		http.Error(w, "start_at must be a valid ISO 8601 date", http.StatusBadRequest)
+		return
+	}
+	endAt, err := parseOptionalTime(req.EndAt)
+	if err != nil {
+		http.Error(w, "end_at must be a valid ISO 8601 date", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	series := models.PostSeries{
+		ID:           primitive.NewObjectID(),
+		OrgID:        orgID,
+		UserID:       userID,
+		Name:         req.Name,
+		Kind:         req.Kind,
+		Channel:      req.Channel,
+		Weekdays:     req.Weekdays,
+		Times:        req.Times,
+		StartAt:      startAt,
+		EndAt:        endAt,
+		MinReuseDays: req.MinReuseDays,
+		DaysAhead:    req.DaysAhead,
+		Active:       true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if series.DaysAhead == 0 {
+		series.DaysAhead = defaultSeriesDaysAhead
+	}
+	if req.Kind == "recurring" {
+		series.Content = req.Content
+	} else {
+		if series.MinReuseDays == 0 {
+			series.MinReuseDays = defaultMinReuseDays
+		}
+		for _, c := range req.Items {
+			series.Items = append(series.Items, models.SeriesItem{ID: primitive.NewObjectID(), SeriesContent: c})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if !validateSeries(ctx, w, &series) {
+		return
+	}
+
+	if _, err := database.PostSeries().InsertOne(ctx, series); err != nil {
+		http.Error(w, "Error creating series", http.StatusInternalServerError)
+		return
+	}
+
+	created := materializeSeries(ctx, &series)
+	slog.Info("post_series_created",
+		"series_id", series.ID.Hex(),
+		"org_id", orgID.Hex(),
+		"kind", series.Kind,
+		"channel", series.Channel,
+		"materialized", created,
+	)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(series)
+}
+
+// GetPostSeries returns one series.
+// @Summary Obter série de posts
+// @Description Retorna uma série recorrente ou fila evergreen
+// @Tags post-series
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da série"
+// @Success 200 {object} models.PostSeries
+// @Failure 404 {string} string "Series not found"
+// @Router /admin/series/{id} [get]
+func GetPostSeries(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	series, ok := findPostSeries(ctx, w, r)
+	if !ok {
+		return
+	}
+	json.NewEncoder(w).Encode(series)
+}
+
+// UpdatePostSeries edits the whole series. Future occurrences that were not edited
+// individually are discarded and materialized again with the new definition.
+// @Summary Atualizar série de posts
+// @Description Edita a série inteira; as próximas ocorrências não editadas individualmente são regeradas com o novo conteúdo e horários
+// @Tags post-series
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da série"
+// @Param body body models.UpdatePostSeriesRequest true "Campos a atualizar"
+// @Success 200 {object} models.PostSeries
+// @Failure 400 {string} string "Invalid request"
+// @Failure 404 {string} string "Series not found"
+// @Router /admin/series/{id} [put]
+func UpdatePostSeries(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	series, ok := findPostSeries(ctx, w, r)
+	if !ok {
+		return
+	}
+	if !canScheduleChannel(w, r, series.Channel) {
+		return
+	}
+
+	var req models.UpdatePostSeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name != nil {
+		if *req.Name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		series.Name = *req.Name
+	}
+	if req.Weekdays != nil {
+		series.Weekdays = req.Weekdays
+	}
+	if req.Times != nil {
+		series.Times = req.Times
+	}
+	if req.StartAt != nil {
+		t, err := parseOptionalTime(*req.StartAt)
+		if err != nil {
+			http.Error(w, "start_at must be a valid ISO 8601 date", http.StatusBadRequest)
+			return
+		}
+		series.StartAt = t
+	}
+	if req.EndAt != nil {
+		t, err := parseOptionalTime(*req.EndAt)
+		if err != nil {
+			http.Error(w, "end_at must be a valid ISO 8601 date", http.StatusBadRequest)
+			return
+		}
+		series.EndAt = t
+	}
+	if req.Content != nil && series.Kind == "recurring" {
+		series.Content = req.Content
+	}
+	if req.Items != nil && series.Kind == "evergreen" {
+		for i := range req.Items {
+			if req.Items[i].ID.IsZero() {
+				req.Items[i].ID = primitive.NewObjectID()
+			}
+		}
+		series.Items = req.Items
+	}
+	if req.MinReuseDays != nil {
+		series.MinReuseDays = *req.MinReuseDays
+	}
+	if req.DaysAhead != nil {
+		series.DaysAhead = *req.DaysAhead
+	}
+	if req.Active != nil {
+		series.Active = *req.Active
+	}
+	if !validateSeries(ctx, w, &series) {
+		return
+	}
+	series.UpdatedAt = time.Now()
+	series.MaterializedUntil = nil
+
+	_, err := database.PostSeries().UpdateOne(ctx, bson.M{"_id": series.ID}, bson.M{"$set": bson.M{
+		"name":               series.Name,
+		"weekdays":           series.Weekdays,
+		"times":              series.Times,
+		"start_at":           series.StartAt,
+		"end_at":             series.EndAt,
+		"content":            series.Content,
+		"items":              series.Items,
+		"min_reuse_days":     series.MinReuseDays,
+		"days_ahead":         series.DaysAhead,
+		"active":             series.Active,
+		"materialized_until": nil,
+		"updated_at":         series.UpdatedAt,
+	}})
+	if err != nil {
+		http.Error(w, "Error updating series", http.StatusInternalServerError)
+		return
+	}
+
+	removed := discardFutureOccurrences(ctx, series, false)
+	created := 0
+	if series.Active {
+		created = materializeSeries(ctx, &series)
+	}
+	slog.Info("post_series_updated",
+		"series_id", series.ID.Hex(),
+		"removed", removed,
+		"materialized", created,
+	)
+
+	json.NewEncoder(w).Encode(series)
+}
+
+// DeletePostSeries deletes a series and its pending occurrences. Published posts are kept.
+// @Summary Remover série de posts
+// @Description Remove a série e todos os agendamentos ainda pendentes dela; posts já publicados são mantidos
+// @Tags post-series
+// @Security BearerAuth
+// @Param id path string true "ID da série"
+// @Success 204
+// @Failure 404 {string} string "Series not found"
+// @Router /admin/series/{id} [delete]
+func DeletePostSeries(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	series, ok := findPostSeries(ctx, w, r)
+	if !ok {
+		return
+	}
+	if !canScheduleChannel(w, r, series.Channel) {
+		return
+	}
+
+	if _, err := database.PostSeries().DeleteOne(ctx, bson.M{"_id": series.ID}); err != nil {
+		http.Error(w, "Error deleting series", http.StatusInternalServerError)
+		return
+	}
+	removed := discardFutureOccurrences(ctx, series, true)
+
+	slog.Info("post_series_deleted", "series_id", series.ID.Hex(), "removed", removed)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSeriesOccurrences lists the schedules materialized from a series.
+// @Summary Listar ocorrências da série
+// @Description Lista os agendamentos gerados pela série. Para editar uma única ocorrência use o endpoint do agendamento (PUT /admin/instagram/schedules/{id} ou /admin/facebook/schedules/{id}); ela deixa de acompanhar as edições da série. Removê-la pula essa ocorrência
+// @Tags post-series
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da série"
+// @Param upcoming query bool false "Somente ocorrências futuras"
+// @Success 200 {array} models.SeriesOccurrence
+// @Failure 404 {string} string "Series not found"
+// @Router /admin/series/{id}/occurrences [get]
+func ListSeriesOccurrences(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	series, ok := findPostSeries(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{"org_id": series.OrgID, "series_id": series.ID}
+	if r.URL.Query().Get("upcoming") == "true" {
+		filter["scheduled_at"] = bson.M{"$gte": time.Now()}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "occurrence_at", Value: 1}}).SetLimit(500)
+
+	occurrences := []models.SeriesOccurrence{}
+	if series.Channel == "facebook" {
+		var schedules []models.FacebookSchedule
+		if cursor, err := database.FacebookSchedules().Find(ctx, filter, opts); err == nil {
+			cursor.All(ctx, &schedules)
+			cursor.Close(ctx)
+		}
+		for _, s := range schedules {
+			occurrences = append(occurrences, seriesOccurrence(s.ID, s.OccurrenceAt, s.ScheduledAt, s.Status, s.Message, s.SeriesItemID, s.Detached))
+		}
+	} else {
+		var schedules []models.InstagramSchedule
+		if cursor, err := database.InstagramSchedules().Find(ctx, filter, opts); err == nil {
+			cursor.All(ctx, &schedules)
+			cursor.Close(ctx)
+		}
+		for _, s := range schedules {
+			occurrences = append(occurrences, seriesOccurrence(s.ID, s.OccurrenceAt, s.ScheduledAt, s.Status, s.Caption, s.SeriesItemID, s.Detached))
+		}
+	}
+
+	json.NewEncoder(w).Encode(occurrences)
+}
+
+func seriesOccurrence(id primitive.ObjectID, occurrenceAt *time.Time, scheduledAt time.Time, status, caption string, itemID *primitive.ObjectID, detached bool) models.SeriesOccurrence {
+	occ := models.SeriesOccurrence{
+		ScheduleID:   id,
+		OccurrenceAt: scheduledAt,
+		ScheduledAt:  scheduledAt,
+		Status:       status,
+		Caption:      caption,
+		ItemID:       itemID,
+		Detached:     detached,
+	}
+	if occurrenceAt != nil {
+		occ.OccurrenceAt = *occurrenceAt
+	}
+	return occ
+}
+
+// findPostSeries loads the series from the {id} path value, scoped to the caller's org.
+func findPostSeries(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.PostSeries, bool) {
+	var series models.PostSeries
+	id, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return series, false
+	}
+
+	err = database.PostSeries().FindOne(ctx, bson.M{"_id": id, "org_id": middleware.GetOrgID(r)}).Decode(&series)
+	if err == mongo.ErrNoDocuments {
+		http.Error(w, "Series not found", http.StatusNotFound)
+		return series, false
+	}
+	if err != nil {
+		http.Error(w, "Error loading series", http.StatusInternalServerError)
+		return series, false
+	}
+	return series, true
+}
+
+// skipSeriesOccurrence records that an occurrence was deleted individually, so the
+// job does not materialize it again.
+func skipSeriesOccurrence(ctx context.Context, seriesID *primitive.ObjectID, occurrenceAt *time.Time) {
+	if seriesID == nil || occurrenceAt == nil {
+		return
+	}
+	database.PostSeries().UpdateOne(ctx, bson.M{"_id": *seriesID}, bson.M{
+		"$addToSet": bson.M{"skipped_at": *occurrenceAt},
+	})
+}
+
+// discardFutureOccurrences deletes the series' pending future schedules. Occurrences
+// edited individually are kept unless all is set.
+func discardFutureOccurrences(ctx context.Context, series models.PostSeries, all bool) int64 {
+	filter := bson.M{
+		"org_id":       series.OrgID,
+		"series_id":    series.ID,
+		"status":       bson.M{"$in": []string{"scheduled", "pending_approval", "rejected"}},
+		"scheduled_at": bson.M{"$gt": time.Now()},
+	}
+	if !all {
+		filter["detached"] = bson.M{"$ne": true}
+	}
+	if series.Channel == "facebook" {
+		cancelNativeFacebookPosts(ctx, filter)
+	}
+	res, err := seriesScheduleCollection(series.Channel).DeleteMany(ctx, filter)
+	if err != nil {
+		slog.Error("post_series_discard", "series_id", series.ID.Hex(), "error", err)
+		return 0
+	}
+	return res.DeletedCount
+}
+
+// ─── Materialization ─────────────────────────────────────────────────
+
+// seriesSlots returns the series' slot times in (from, to], in the org timezone,
+// excluding skipped occurrences.
+func seriesSlots(series models.PostSeries, loc *time.Location, from, to time.Time) []time.Time {
+	skipped := make(map[int64]bool, len(series.SkippedAt))
+	for _, t := range series.SkippedAt {
+		skipped[t.Unix()] = true
+	}
+
+	var slots []time.Time
+	day := from.In(loc)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	for ; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !containsInt(series.Weekdays, int(day.Weekday())) {
+			continue
+		}
+		for _, hhmm := range series.Times {
+			clock, err := time.Parse("15:04", hhmm)
+			if err != nil {
+				continue
+			}
+			t := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+			switch {
+			case !t.After(from), t.After(to), skipped[t.Unix()]:
+				continue
+			case series.StartAt != nil && t.Before(*series.StartAt):
+				continue
+			case series.EndAt != nil && t.After(*series.EndAt):
+				continue
+			}
+			slots = append(slots, t)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Before(slots[j]) })
+	return slots
+}
+
+// seriesRecord is the part of a materialized schedule the job needs.
+type seriesRecord struct {
+	SeriesItemID *primitive.ObjectID `bson:"series_item_id"`
+	OccurrenceAt *time.Time          `bson:"occurrence_at"`
+	ScheduledAt  time.Time           `bson:"scheduled_at"`
+}
+
+// materializeSeries creates the schedules for the series' slots up to DaysAhead days
+// ahead that do not exist yet. Evergreen items are picked least recently used first,
+// skipping items used within MinReuseDays of the slot. Returns the number created.
+func materializeSeries(ctx context.Context, series *models.PostSeries) int {
+	if !series.Active {
+		return 0
+	}
+	loc := orgLocation(ctx, series.OrgID)
+	now := time.Now()
+	until := now.AddDate(0, 0, series.DaysAhead)
+	coll := seriesScheduleCollection(series.Channel)
+
+	// Existing occurrences (any status, so published and failed ones are not recreated)
+	// and every use of each evergreen item.
+	var records []seriesRecord
+	opts := options.Find().SetProjection(bson.M{"series_item_id": 1, "occurrence_at": 1, "scheduled_at": 1})
+	if cursor, err := coll.Find(ctx, bson.M{"org_id": series.OrgID, "series_id": series.ID}, opts); err == nil {
+		cursor.All(ctx, &records)
+		cursor.Close(ctx)
+	}
+	existing := make(map[int64]bool, len(records))
+	uses := map[primitive.ObjectID][]time.Time{}
+	for _, rec := range records {
+		if rec.OccurrenceAt != nil {
+			existing[rec.OccurrenceAt.Unix()] = true
+		}
+		if rec.SeriesItemID != nil {
+			uses[*rec.SeriesItemID] = append(uses[*rec.SeriesItemID], rec.ScheduledAt)
+		}
+	}
+
+	// Respect the plan's scheduled posts limit
+	remaining := remainingScheduledPosts(ctx, series.OrgID)
+
+	status, approvals := initialApprovalState(ctx, series.OrgID, series.UserID)
+
+	created := 0
+	for _, slot := range seriesSlots(*series, loc, now.Add(slotLeadTime), until) {
+		if existing[slot.Unix()] {
+			continue
+		}
+		if remaining == 0 {
+			slog.Warn("post_series_plan_limit", "series_id", series.ID.Hex(), "org_id", series.OrgID.Hex())
+			break
+		}
+
+		content := series.Content
+		var itemID *primitive.ObjectID
+		if series.Kind == "evergreen" {
+			item := pickEvergreenItem(series.Items, uses, slot, time.Duration(series.MinReuseDays)*24*time.Hour)
+			if item == nil {
+				slog.Info("post_series_no_item_available", "series_id", series.ID.Hex(), "slot", slot.Format(time.RFC3339))
+				continue
+			}
+			content = &item.SeriesContent
+			id := item.ID
+			itemID = &id
+			uses[id] = append(uses[id], slot)
+		}
+		if content == nil {
+			break
+		}
+
+		if err := insertSeriesSchedule(ctx, series, *content, itemID, slot, status, approvals); err != nil {
+			slog.Error("post_series_insert", "series_id", series.ID.Hex(), "error", err)
+			continue
+		}
+		created++
+		if remaining > 0 {
+			remaining--
+		}
+	}
+
+	if created > 0 && status == "pending_approval" {
+		notifyReviewers(ctx, series.OrgID, created, "")
+	}
+	database.PostSeries().UpdateOne(ctx, bson.M{"_id": series.ID}, bson.M{"$set": bson.M{"materialized_until": until}})
+	series.MaterializedUntil = &until
+	return created
+}
+
+// pickEvergreenItem returns the least recently used item not used within gap of slot,
+// or nil when every item is too recent.
+func pickEvergreenItem(items []models.SeriesItem, uses map[primitive.ObjectID][]time.Time, slot time.Time, gap time.Duration) *models.SeriesItem {
+	var best *models.SeriesItem
+	var bestLast time.Time
+	for i := range items {
+		var last time.Time
+		eligible := true
+		for _, t := range uses[items[i].ID] {
+			diff := slot.Sub(t)
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff < gap {
+				eligible = false
+				break
+			}
+			if t.After(last) {
+				last = t
+			}
+		}
+		if !eligible {
+			continue
+		}
+		if best == nil || last.Before(bestLast) {
+			best, bestLast = &items[i], last
+		}
+	}
+	return best
+}
+
+// insertSeriesSchedule creates the concrete schedule for one occurrence.
+func insertSeriesSchedule(ctx context.Context, series *models.PostSeries, content models.SeriesContent, itemID *primitive.ObjectID, slot time.Time, status string, approvals []models.ApprovalDecision) error {
+	now := time.Now()
+	seriesID := series.ID
+	occurrenceAt := slot
+
+	if series.Channel == "facebook" {
+		_, err := database.FacebookSchedules().InsertOne(ctx, models.FacebookSchedule{
+			ID:           primitive.NewObjectID(),
+			UserID:       series.UserID,
+			OrgID:        series.OrgID,
+			Message:      content.Caption,
+			MediaType:    content.MediaType,
+			ImageIDs:     content.ImageIDs,
+			LinkURL:      content.LinkURL,
+			ScheduledAt:  slot,
+			Status:       status,
+			SeriesID:     &seriesID,
+			SeriesItemID: itemID,
+			OccurrenceAt: &occurrenceAt,
+			Approvals:    approvals,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+		return err
+	}
+
+	_, err := database.InstagramSchedules().InsertOne(ctx, models.InstagramSchedule{
+		ID:             primitive.NewObjectID(),
+		UserID:         series.UserID,
+		OrgID:          series.OrgID,
+		Caption:        content.Caption,
+		MediaType:      content.MediaType,
+		ImageIDs:       content.ImageIDs,
+		ScheduledAt:    slot,
+		Status:         status,
+		PostToFacebook: content.PostToFacebook,
+		PostToThreads:  content.PostToThreads,
+		SeriesID:       &seriesID,
+		SeriesItemID:   itemID,
+		OccurrenceAt:   &occurrenceAt,
+		Approvals:      approvals,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	return err
+}
+
+// MaterializePostSeries runs materialization for every active series.
+func MaterializePostSeries() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	cursor, err := database.PostSeries().Find(ctx, bson.M{"active": true})
+	if err != nil {
+		slog.Error("post_series_query", "error", err)
+		return
+	}
+	var series []models.PostSeries
+	cursor.All(ctx, &series)
+	cursor.Close(ctx)
+
+	created := 0
+	for i := range series {
+		created += materializeSeries(ctx, &series[i])
+	}
+	if created > 0 {
+		slog.Info("post_series_cycle_complete", "series", len(series), "materialized", created)
+	}
+}
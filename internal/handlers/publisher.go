@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PublishContent is a post in channel-neutral form, as a Publisher sends it.
+// MediaType uses the channel's own vocabulary ("image", "carousel", "text", ...).
+type PublishContent struct {
+	ID          primitive.ObjectID // source schedule
+	Text        string             // caption or message
+	MediaType   string
+	ImageIDs    []string
+	LinkURL     string // Facebook link posts
+	VideoID     string // Facebook video and Reel posts
+	ThumbnailID string
+	Title       string
+}
+
+// PublishStatus is the state of a post on its network.
+type PublishStatus struct {
+	State  string // "published", "scheduled", "processing", "failed"
+	PostID string // the published post, when it differs from the ID asked about
+	Reason string // why the post failed
+}
+
+// Publisher is one network's publishing flow. Implementations carry the org's
+// resolved credentials; get one from newPublisher.
+type Publisher interface {
+	Channel() string
+	// Validate checks the post against the network's rules and that its media exists.
+	Validate(ctx context.Context, c PublishContent) error
+	// UploadMedia prepares the post's media on the network and returns the
+	// container Publish consumes, or "" when media is sent with the post itself.
+	UploadMedia(ctx context.Context, c PublishContent) (string, error)
+	// Publish makes the post public and returns its network ID.
+	Publish(ctx context.Context, c PublishContent, containerID string) (string, error)
+	FetchStatus(ctx context.Context, c PublishContent, postID string) (PublishStatus, error)
+	// FetchMetrics returns the post's latest lifetime metrics by name.
+	FetchMetrics(ctx context.Context, postID string) (map[string]int64, error)
+}
+
+// newPublisher returns the publisher of a channel ("instagram", "facebook" or
+// "threads") for the org, failing when the channel is not configured.
+func newPublisher(ctx context.Context, channel string, userID, orgID primitive.ObjectID) (Publisher, error) {
+	switch channel {
+	case "instagram":
+		creds, err := getInstagramCredentials(ctx, userID, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("get credentials: %w", err)
+		}
+		if creds == nil {
+			return nil, fmt.Errorf("instagram not configured")
+		}
+		return instagramPublisher{creds: creds}, nil
+	case "facebook":
+		creds, err := getFacebookCredentials(ctx, userID, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("get credentials: %w", err)
+		}
+		if creds == nil {
+			return nil, fmt.Errorf("facebook not configured")
+		}
+		return facebookPublisher{creds: creds}, nil
+	case "threads":
+		creds, err := getThreadsCredentials(ctx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("get credentials: %w", err)
+		}
+		if creds == nil {
+			return nil, fmt.Errorf("threads not configured")
+		}
+		return threadsPublisher{creds: creds}, nil
+	}
+	return nil, fmt.Errorf("unknown channel: %s", channel)
+}
+
+// publishWith validates c, uploads its media and publishes it.
+func publishWith(ctx context.Context, p Publisher, c PublishContent) (string, error) {
+	if err := p.Validate(ctx, c); err != nil {
+		return "", fmt.Errorf("validation: %w", err)
+	}
+	containerID, err := p.UploadMedia(ctx, c)
+	if err != nil {
+		return "", err
+	}
+	return p.Publish(ctx, c, containerID)
+}
+
+// ─── Instagram ───────────────────────────────────────────────────────
+
+// instagramPublisher publishes through Instagram media containers.
+type instagramPublisher struct {
+	creds *instagramCredentials
+}
+
+func instagramPublishContent(s models.InstagramSchedule) PublishContent {
+	return PublishContent{ID: s.ID, Text: s.Caption, MediaType: s.MediaType, ImageIDs: s.ImageIDs}
+}
+
+func (p instagramPublisher) Channel() string { return "instagram" }
+
+func (p instagramPublisher) Validate(ctx context.Context, c PublishContent) error {
+	if res := validateInstagramPost(ctx, c.Text, c.MediaType, c.ImageIDs); !res.Valid {
+		return errors.New(validationSummary(res.Errors))
+	}
+	return nil
+}
+
+func (p instagramPublisher) UploadMedia(ctx context.Context, c PublishContent) (string, error) {
+	accountID, token := p.creds.AccountID, p.creds.Token
+
+	if c.MediaType == "image" {
+		containerID, err := createMediaContainer(accountID, token, getPublicImageURL(c.ImageIDs[0]), c.Text, false)
+		if err != nil {
+			return "", fmt.Errorf("create container: %w", err)
+		}
+		return containerID, nil
+	}
+
+	var childIDs []string
+	for _, imgID := range c.ImageIDs {
+		childID, err := createMediaContainer(accountID, token, getPublicImageURL(imgID), "", true)
+		if err != nil {
+			return "", fmt.Errorf("create carousel item: %w", err)
+		}
+		childIDs = append(childIDs, childID)
+	}
+	carouselID, err := createCarouselContainer(accountID, token, childIDs, c.Text)
+	if err != nil {
+		return "", fmt.Errorf("create carousel container: %w", err)
+	}
+	return carouselID, nil
+}
+
+func (p instagramPublisher) Publish(ctx context.Context, c PublishContent, containerID string) (string, error) {
+	mediaID, err := publishMediaContainer(p.creds.AccountID, p.creds.Token, containerID)
+	if err != nil {
+		return "", fmt.Errorf("publish: %w", err)
+	}
+	return mediaID, nil
+}
+
+func (p instagramPublisher) FetchStatus(ctx context.Context, c PublishContent, postID string) (PublishStatus, error) {
+	if _, err := metaGraphGet("/"+postID, p.creds.Token, url.Values{"fields": {"id"}}); err != nil {
+		return PublishStatus{}, err
+	}
+	return PublishStatus{State: "published"}, nil
+}
+
+func (p instagramPublisher) FetchMetrics(ctx context.Context, postID string) (map[string]int64, error) {
+	snap, err := fetchMediaInsightSnapshot(postID, p.creds.Token)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int64{
+		"reach":          snap.Reach,
+		"impressions":    snap.Impressions,
+		"saves":          snap.Saves,
+		"shares":         snap.Shares,
+		"plays":          snap.Plays,
+		"profile_visits": snap.ProfileVisits,
+		"likes":          snap.Likes,
+		"comments":       snap.Comments,
+	}, nil
+}
+
+// ─── Facebook ────────────────────────────────────────────────────────
+
+// facebookPublisher publishes to a Facebook Page. Media goes with the post
+// request, so UploadMedia is a no-op; videos are uploaded by Publish.
+type facebookPublisher struct {
+	creds *facebookCredentials
+}
+
+func facebookPublishContent(s models.FacebookSchedule) PublishContent {
+	return PublishContent{
+		ID:          s.ID,
+		Text:        s.Message,
+		MediaType:   s.MediaType,
+		ImageIDs:    s.ImageIDs,
+		LinkURL:     s.LinkURL,
+		VideoID:     s.VideoID,
+		ThumbnailID: s.ThumbnailID,
+		Title:       s.Title,
+	}
+}
+
+func (p facebookPublisher) Channel() string { return "facebook" }
+
+func (p facebookPublisher) Validate(ctx context.Context, c PublishContent) error {
+	if msg := validateFacebookPost(ctx, p.creds.OrgID, c.Text, c.MediaType, c.LinkURL, c.ImageIDs, c.VideoID); msg != "" {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (p facebookPublisher) UploadMedia(ctx context.Context, c PublishContent) (string, error) {
+	return "", nil
+}
+
+// Publish returns the post ID, or for videos and Reels the video ID: the post
+// appears once Facebook finishes processing (see FetchStatus).
+func (p facebookPublisher) Publish(ctx context.Context, c PublishContent, containerID string) (string, error) {
+	return publishFacebookContent(ctx, p.creds, models.FacebookSchedule{
+		ID:          c.ID,
+		OrgID:       p.creds.OrgID,
+		Message:     c.Text,
+		MediaType:   c.MediaType,
+		ImageIDs:    c.ImageIDs,
+		LinkURL:     c.LinkURL,
+		VideoID:     c.VideoID,
+		ThumbnailID: c.ThumbnailID,
+		Title:       c.Title,
+	})
+}
+
+// FetchStatus reads the processing state of a video, or whether a post
+// (including one scheduled natively) is published.
+func (p facebookPublisher) FetchStatus(ctx context.Context, c PublishContent, postID string) (PublishStatus, error) {
+	if isFacebookVideoType(c.MediaType) {
+		status, err := fetchFacebookVideoStatus(ctx, p.creds.Token, postID)
+		if err != nil {
+			return PublishStatus{}, err
+		}
+		outcome, reason := status.videoOutcome()
+		if outcome == "" {
+			outcome = "processing"
+		}
+		return PublishStatus{State: outcome, PostID: status.PostID, Reason: reason}, nil
+	}
+
+	post, err := metaGraphGet("/"+postID, p.creds.Token, url.Values{"fields": {"is_published"}})
+	if err != nil {
+		return PublishStatus{}, err
+	}
+	if post["is_published"] == true {
+		return PublishStatus{State: "published"}, nil
+	}
+	return PublishStatus{State: "scheduled"}, nil
+}
+
+func (p facebookPublisher) FetchMetrics(ctx context.Context, postID string) (map[string]int64, error) {
+	snap, err := fetchPostInsightSnapshot(postID, p.creds.Token)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int64{
+		"impressions": snap.Impressions,
+		"reach":       snap.Reach,
+		"reactions":   snap.TotalReactions,
+		"shares":      snap.Shares,
+		"comments":    snap.Comments,
+		"clicks":      snap.Clicks,
+	}, nil
+}
@@ -0,0 +1,504 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	importMaxUpload    = 100 << 20 // CSV + ZIP
+	importMaxRows      = 200
+	importMaxImageSize = 10 << 20 // same limit as a single upload
+)
+
+// importTimeLayouts are the accepted scheduled_at formats besides RFC 3339,
+// interpreted in the org timezone.
+var importTimeLayouts = []string{
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"02/01/2006 15:04",
+}
+
+// scheduleImport is a parsed upload: the CSV rows and the ZIP entries by file name.
+type scheduleImport struct {
+	rows  []models.ScheduleImportRow
+	files map[string]*zip.File
+}
+
+// readScheduleImport parses the multipart upload: "csv" (required) and "media" (ZIP, optional).
+func readScheduleImport(w http.ResponseWriter, r *http.Request, loc *time.Location) (*scheduleImport, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, importMaxUpload)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "Upload too large (max 100MB)", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+
+	csvFile, _, err := r.FormFile("csv")
+	if err != nil {
+		http.Error(w, "No CSV provided. Use field name 'csv'", http.StatusBadRequest)
+		return nil, false
+	}
+	defer csvFile.Close()
+	csvData, err := io.ReadAll(csvFile)
+	if err != nil {
+		http.Error(w, "Failed to read CSV", http.StatusBadRequest)
+		return nil, false
+	}
+	rows, msg := parseImportCSV(csvData, loc)
+	if msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return nil, false
+	}
+
+	files := map[string]*zip.File{}
+	if zipFile, header, err := r.FormFile("media"); err == nil {
+		defer zipFile.Close()
+		zr, err := zip.NewReader(zipFile, header.Size)
+		if err != nil {
+			http.Error(w, "media must be a valid ZIP file", http.StatusBadRequest)
+			return nil, false
+		}
+		for _, f := range zr.File {
+			name := path.Base(f.Name)
+			if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(name, ".") {
+				continue
+			}
+			files[strings.ToLower(name)] = f
+		}
+	}
+
+	return &scheduleImport{rows: rows, files: files}, true
+}
+
+// parseImportCSV reads the rows. Columns are matched by header name: caption,
+// scheduled_at, channel and media are required; media_type and link_url are optional.
+// Comma and semicolon delimiters are accepted.
+func parseImportCSV(data []byte, loc *time.Location) ([]models.ScheduleImportRow, string) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel BOM
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, "Invalid CSV: " + err.Error()
+	}
+	if len(records) < 2 {
+		return nil, "CSV must have a header and at least one row"
+	}
+	if len(records)-1 > importMaxRows {
+		return nil, fmt.Sprintf("CSV can have at most %d rows", importMaxRows)
+	}
+
+	cols := map[string]int{}
+	for i, name := range records[0] {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"caption", "scheduled_at", "channel", "media"} {
+		if _, ok := cols[required]; !ok {
+			return nil, "CSV is missing the '" + required + "' column"
+		}
+	}
+	field := func(record []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rows := make([]models.ScheduleImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := models.ScheduleImportRow{
+			Row:        i + 2,
+			Channel:    strings.ToLower(strings.ReplaceAll(field(record, "channel"), " ", "")),
+			Caption:    field(record, "caption"),
+			MediaType:  strings.ToLower(field(record, "media_type")),
+			MediaFiles: strings.FieldsFunc(field(record, "media"), func(r rune) bool { return r == '|' || r == ';' }),
+			LinkURL:    field(record, "link_url"),
+			Errors:     []string{},
+		}
+		for j := range row.MediaFiles {
+			row.MediaFiles[j] = strings.TrimSpace(row.MediaFiles[j])
+		}
+		if row.MediaFiles == nil {
+			row.MediaFiles = []string{}
+		}
+		if row.MediaType == "" {
+			row.MediaType = defaultImportMediaType(len(row.MediaFiles), row.LinkURL)
+		}
+		if t, ok := parseImportTime(field(record, "scheduled_at"), loc); ok {
+			row.ScheduledAt = &t
+		}
+		rows = append(rows, row)
+	}
+	return rows, ""
+}
+
+func defaultImportMediaType(files int, linkURL string) string {
+	switch {
+	case files > 1:
+		return "carousel"
+	case files == 1:
+		return "image"
+	case linkURL != "":
+		return "link"
+	default:
+		return "text"
+	}
+}
+
+func parseImportTime(raw string, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	for _, layout := range importTimeLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// checkImportImage decodes a ZIP entry the way UploadInstagramImage does.
+// Returns the decoded image or an error message.
+func checkImportImage(f *zip.File) (image.Image, string) {
+	if f.UncompressedSize64 > importMaxImageSize {
+		return nil, "image too large (max 10MB)"
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, "failed to read image"
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, importMaxImageSize+1))
+	if err != nil {
+		return nil, "failed to read image"
+	}
+	if len(data) > importMaxImageSize {
+		return nil, "image too large (max 10MB)"
+	}
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png", "image/webp":
+	default:
+		return nil, "only JPEG, PNG and WebP images are allowed"
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "invalid image format"
+	}
+	return img, ""
+}
+
+// remainingScheduledPosts returns how many more posts the org's plan allows to be
+// waiting for publication (scheduled or in review), or -1 when unlimited.
+func remainingScheduledPosts(ctx context.Context, orgID primitive.ObjectID) int {
+	var sub models.Subscription
+	database.Subscriptions().FindOne(ctx, bson.M{"org_id": orgID}).Decode(&sub)
+	limits := models.Plans[sub.PlanID]
+	if limits.MaxScheduledPosts <= 0 {
+		return -1
+	}
+	filter := bson.M{"org_id": orgID, "status": bson.M{"$in": []string{"scheduled", "pending_approval"}}}
+	ig, _ := database.InstagramSchedules().CountDocuments(ctx, filter)
+	fb, _ := database.FacebookSchedules().CountDocuments(ctx, filter)
+	if remaining := limits.MaxScheduledPosts - int(ig+fb); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// validateScheduleImport fills in each row's errors and builds the preview. Images are
+// decoded once per file; nothing is saved.
+func validateScheduleImport(ctx context.Context, r *http.Request, imp *scheduleImport) models.ScheduleImportResponse {
+	now := time.Now()
+	remaining := remainingScheduledPosts(ctx, middleware.GetOrgID(r))
+	imageErrors := map[string]string{}
+	used := map[string]bool{}
+
+	resp := models.ScheduleImportResponse{Rows: imp.rows, Total: len(imp.rows), Remaining: remaining, UnusedFiles: []string{}}
+	accepted := 0
+	for i := range imp.rows {
+		row := &imp.rows[i]
+		addErr := func(msg string) { row.Errors = append(row.Errors, msg) }
+
+		switch row.Channel {
+		case "instagram", "facebook", "instagram+facebook":
+			for _, channel := range strings.Split(row.Channel, "+") {
+				if !middleware.HasPermission(r, channel+":schedule") {
+					addErr("missing permission " + channel + ":schedule")
+				}
+			}
+		default:
+			addErr("channel must be 'instagram', 'facebook' or 'instagram+facebook'")
+		}
+
+		if row.ScheduledAt == nil {
+			addErr("scheduled_at must be RFC 3339, YYYY-MM-DD HH:MM or DD/MM/YYYY HH:MM")
+		} else if !row.ScheduledAt.After(now) {
+			addErr("scheduled_at must be in the future")
+		}
+
+		if strings.HasPrefix(row.Channel, "instagram") {
+			res := instagramPostChecks(row.Caption, row.MediaType, len(row.MediaFiles))
+			for _, issue := range res.Errors {
+				addErr(issue.Field + ": " + issue.Message)
+			}
+		} else if row.Channel == "facebook" {
+			if msg := facebookPostCheck(row.Caption, row.MediaType, row.LinkURL, len(row.MediaFiles), false); msg != "" {
+				addErr(msg)
+			}
+		}
+
+		for _, name := range row.MediaFiles {
+			key := strings.ToLower(path.Base(name))
+			f, ok := imp.files[key]
+			if !ok {
+				addErr("media file not found in ZIP: " + name)
+				continue
+			}
+			used[key] = true
+			msg, checked := imageErrors[key]
+			if !checked {
+				_, msg = checkImportImage(f)
+				imageErrors[key] = msg
+			}
+			if msg != "" {
+				addErr(name + ": " + msg)
+			}
+		}
+
+		if len(row.Errors) == 0 && remaining >= 0 && accepted >= remaining {
+			addErr(fmt.Sprintf("plan limit reached: only %d more scheduled posts allowed", remaining))
+		}
+		row.Valid = len(row.Errors) == 0
+		if row.Valid {
+			accepted++
+			resp.ValidCount++
+		} else {
+			resp.ErrorCount++
+		}
+	}
+
+	for key, f := range imp.files {
+		if !used[key] {
+			resp.UnusedFiles = append(resp.UnusedFiles, f.Name)
+		}
+	}
+	sort.Strings(resp.UnusedFiles)
+	return resp
+}
+
+// PreviewScheduleImport validates a bulk import without saving anything.
+// @Summary Pré-visualizar importação de agendamentos
+// @Description Valida um CSV (colunas caption, scheduled_at, channel, media e opcionalmente media_type e link_url) e um ZIP com as imagens, retornando os erros por linha. O horário aceita RFC 3339, AAAA-MM-DD HH:MM ou DD/MM/AAAA HH:MM no fuso da organização; várias imagens em media são separadas por | e o limite de posts agendados do plano é respeitado
+// @Tags schedules
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param csv formData file true "Planilha CSV"
+// @Param media formData file false "ZIP com as imagens"
+// @Success 200 {object} models.ScheduleImportResponse
+// @Failure 400 {string} string "Invalid CSV"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 413 {string} string "Upload too large"
+// @Router /admin/schedules/import/preview [post]
+func PreviewScheduleImport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	imp, ok := readScheduleImport(w, r, orgLocation(ctx, middleware.GetOrgID(r)))
+	if !ok {
+		return
+	}
+	json.NewEncoder(w).Encode(validateScheduleImport(ctx, r, imp))
+}
+
+// CommitScheduleImport validates the same upload again and, when every row is valid,
+// uploads the images through the resize pipeline and creates all schedules. Nothing is
+// kept if any step fails.
+// @Summary Importar agendamentos
+// @Description Revalida o CSV e o ZIP da pré-visualização e, se todas as linhas forem válidas, envia as imagens (redimensionadas como no upload) e cria todos os agendamentos. Se alguma linha for inválida nada é criado e a pré-visualização é retornada com status 400
+// @Tags schedules
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param csv formData file true "Planilha CSV"
+// @Param media formData file false "ZIP com as imagens"
+// @Success 201 {object} models.ScheduleImportResponse
+// @Failure 400 {object} models.ScheduleImportResponse "Invalid rows"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 413 {string} string "Upload too large"
+// @Failure 500 {string} string "Error importing schedules"
+// @Router /admin/schedules/import [post]
+func CommitScheduleImport(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	imp, ok := readScheduleImport(w, r, orgLocation(ctx, orgID))
+	if !ok {
+		return
+	}
+	resp := validateScheduleImport(ctx, r, imp)
+	if resp.ErrorCount > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+
+	var imageIDs []primitive.ObjectID
+	var igIDs, fbIDs []primitive.ObjectID
+	rollback := func() {
+		if len(igIDs) > 0 {
+			database.InstagramSchedules().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": igIDs}})
+		}
+		if len(fbIDs) > 0 {
+			database.FacebookSchedules().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": fbIDs}})
+		}
+		if len(imageIDs) > 0 {
+			database.Images().DeleteMany(ctx, bson.M{"_id": bson.M{"$in": imageIDs}})
+		}
+	}
+	fail := func(step string, err error) {
+		slog.Error("schedule_import_error", "org_id", orgID.Hex(), "step", step, "error", err)
+		rollback()
+		http.Error(w, "Error importing schedules", http.StatusInternalServerError)
+	}
+
+	// Upload each referenced file once
+	uploaded := map[string]string{}
+	for _, row := range imp.rows {
+		for _, name := range row.MediaFiles {
+			key := strings.ToLower(path.Base(name))
+			if _, done := uploaded[key]; done {
+				continue
+			}
+			img, msg := checkImportImage(imp.files[key])
+			if msg != "" {
+				fail("decode", fmt.Errorf("%s: %s", name, msg))
+				return
+			}
+			originalDoc, imgDoc, err := instagramImageDocs(img, nil, userID, orgID)
+			if err != nil {
+				fail("resize", err)
+				return
+			}
+			if _, err := database.Images().InsertOne(ctx, originalDoc); err != nil {
+				fail("save_image", err)
+				return
+			}
+			imageIDs = append(imageIDs, originalDoc.ID)
+			if _, err := database.Images().InsertOne(ctx, imgDoc); err != nil {
+				fail("save_image", err)
+				return
+			}
+			imageIDs = append(imageIDs, imgDoc.ID)
+			uploaded[key] = imgDoc.ID.Hex()
+		}
+	}
+
+	status, approvals := initialApprovalState(ctx, orgID, userID)
+	now := time.Now()
+	var igDocs, fbDocs []interface{}
+	for i := range imp.rows {
+		row := &imp.rows[i]
+		ids := make([]string, 0, len(row.MediaFiles))
+		for _, name := range row.MediaFiles {
+			ids = append(ids, uploaded[strings.ToLower(path.Base(name))])
+		}
+		id := primitive.NewObjectID()
+		row.ScheduleID = id.Hex()
+
+		if row.Channel == "facebook" {
+			fbDocs = append(fbDocs, models.FacebookSchedule{
+				ID:          id,
+				UserID:      userID,
+				OrgID:       orgID,
+				Message:     row.Caption,
+				MediaType:   row.MediaType,
+				ImageIDs:    ids,
+				LinkURL:     row.LinkURL,
+				ScheduledAt: *row.ScheduledAt,
+				Status:      status,
+				Approvals:   approvals,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			})
+			fbIDs = append(fbIDs, id)
+			continue
+		}
+		igDocs = append(igDocs, models.InstagramSchedule{
+			ID:             id,
+			UserID:         userID,
+			OrgID:          orgID,
+			Caption:        row.Caption,
+			MediaType:      row.MediaType,
+			ImageIDs:       ids,
+			ScheduledAt:    *row.ScheduledAt,
+			Status:         status,
+			PostToFacebook: row.Channel == "instagram+facebook",
+			Approvals:      approvals,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+		igIDs = append(igIDs, id)
+	}
+
+	if len(igDocs) > 0 {
+		if _, err := database.InstagramSchedules().InsertMany(ctx, igDocs); err != nil {
+			fail("save_instagram", err)
+			return
+		}
+	}
+	if len(fbDocs) > 0 {
+		if _, err := database.FacebookSchedules().InsertMany(ctx, fbDocs); err != nil {
+			fail("save_facebook", err)
+			return
+		}
+	}
+	if status == "pending_approval" {
+		notifyReviewers(ctx, orgID, len(imp.rows), imp.rows[0].Caption)
+	}
+
+	resp.Committed = true
+	resp.Created = len(imp.rows)
+	resp.Images = len(uploaded)
+	slog.Info("schedule_import_committed",
+		"org_id", orgID.Hex(),
+		"user_id", userID.Hex(),
+		"instagram", len(igDocs),
+		"facebook", len(fbDocs),
+		"images", len(uploaded),
+	)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,980 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	threadsAPIBase = "https://graph.threads.net/v1.0"
+
+	threadsMaxTextLength    = 500
+	threadsMaxCarouselItems = 20
+)
+
+// threadsMetrics are the lifetime insights read for a published post.
+var threadsMetrics = []string{"views", "likes", "replies", "reposts", "quotes", "shares"}
+
+// threadsCredentials holds resolved Threads API credentials.
+type threadsCredentials struct {
+	ThreadsUserID string
+	Token         string
+	Username      string
+	OrgID         primitive.ObjectID
+}
+
+// validThreadsMediaTypes lists the supported Threads post types.
+var validThreadsMediaTypes = map[string]bool{"text": true, "image": true, "carousel": true}
+
+const threadsMediaTypeError = "media_type must be 'text', 'image' or 'carousel'"
+
+// threadsPostCheck checks the media type against the text and image count.
+func threadsPostCheck(text, mediaType string, imageCount int) string {
+	switch {
+	case !validThreadsMediaTypes[mediaType]:
+		return threadsMediaTypeError
+	case utf8.RuneCountInString(text) > threadsMaxTextLength:
+		return fmt.Sprintf("caption exceeds %d characters", threadsMaxTextLength)
+	case mediaType == "text" && strings.TrimSpace(text) == "":
+		return "caption is required for text type"
+	case mediaType == "text" && imageCount > 0:
+		return "text type does not take images"
+	case mediaType == "image" && imageCount != 1:
+		return "image type requires exactly one image; use 'carousel' for multiple"
+	case mediaType == "carousel" && (imageCount < 2 || imageCount > threadsMaxCarouselItems):
+		return fmt.Sprintf("carousel requires 2 to %d images", threadsMaxCarouselItems)
+	}
+	return ""
+}
+
+// validateThreadsPost checks a Threads post's shape and that its images exist.
+// Returns an error message, or "" when valid.
+func validateThreadsPost(ctx context.Context, text, mediaType string, imageIDs []string) string {
+	if msg := threadsPostCheck(text, mediaType, len(imageIDs)); msg != "" {
+		return msg
+	}
+	for _, imgID := range imageIDs {
+		if issue, ok := checkInstagramImage(ctx, imgID); !ok && issue.Code != "aspect_ratio" {
+			return issue.Message
+		}
+	}
+	return ""
+}
+
+// getThreadsCredentials resolves the org's Threads profile and token.
+func getThreadsCredentials(ctx context.Context, orgID primitive.ObjectID) (*threadsCredentials, error) {
+	if orgID == primitive.NilObjectID || !crypto.Available() {
+		return nil, nil
+	}
+
+	var cfg models.ThreadsConfig
+	err := database.ThreadsConfigs().FindOne(ctx, bson.M{"org_id": orgID}).Decode(&cfg)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db error: %w", err)
+	}
+
+	if cfg.AccessTokenEnc == "" {
+		return nil, nil
+	}
+	token, err := crypto.Decrypt(cfg.AccessTokenEnc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token: %w", err)
+	}
+
+	return &threadsCredentials{
+		ThreadsUserID: cfg.ThreadsUserID,
+		Token:         token,
+		Username:      cfg.Username,
+		OrgID:         cfg.OrgID,
+	}, nil
+}
+
+// connectThreadsProfile resolves the Threads profile behind token and saves it,
+// encrypted, as the org's Threads config. source is "oauth" or "manual".
+func connectThreadsProfile(ctx context.Context, userID, orgID primitive.ObjectID, token, source string, meta *models.TokenMeta) (models.ThreadsConfig, error) {
+	me, err := threadsGraphGet("/me", token, url.Values{"fields": {"id,username"}})
+	if err != nil {
+		return models.ThreadsConfig{}, err
+	}
+	threadsUserID, _ := me["id"].(string)
+	if threadsUserID == "" {
+		return models.ThreadsConfig{}, fmt.Errorf("threads profile not found for this token")
+	}
+	username, _ := me["username"].(string)
+
+	tokenEnc, err := crypto.Encrypt(token)
+	if err != nil {
+		return models.ThreadsConfig{}, fmt.Errorf("encrypt token: %w", err)
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"threads_user_id":  threadsUserID,
+			"username":         username,
+			"token_source":     source,
+			"access_token_enc": tokenEnc,
+			"token_meta":       meta,
+			"updated_at":       now,
+		},
+		"$setOnInsert": bson.M{
+			"user_id":    userID,
+			"org_id":     orgID,
+			"created_at": now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var cfg models.ThreadsConfig
+	if err := database.ThreadsConfigs().FindOneAndUpdate(ctx, bson.M{"org_id": orgID}, update, opts).Decode(&cfg); err != nil {
+		return models.ThreadsConfig{}, fmt.Errorf("save config: %w", err)
+	}
+	return cfg, nil
+}
+
+// threadsGraphGet does a GET to the Threads Graph API.
+func threadsGraphGet(endpoint, token string, params url.Values) (map[string]interface{}, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("access_token", token)
+
+	resp, err := http.Get(threadsAPIBase + endpoint + "?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+	return decodeThreadsResponse(resp)
+}
+
+// threadsGraphPost does a POST to the Threads Graph API with form-encoded body.
+func threadsGraphPost(endpoint, token string, params url.Values) (map[string]interface{}, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("access_token", token)
+
+	resp, err := http.PostForm(threadsAPIBase+endpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+	return decodeThreadsResponse(resp)
+}
+
+func decodeThreadsResponse(resp *http.Response) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+	if errObj, ok := result["error"]; ok {
+		return nil, fmt.Errorf("threads API error: %v", errObj)
+	}
+	return result, nil
+}
+
+// ─── Publisher ───────────────────────────────────────────────────────
+
+// threadsPublisher publishes through Threads media containers.
+type threadsPublisher struct {
+	creds *threadsCredentials
+}
+
+func threadsPublishContent(s models.ThreadsSchedule) PublishContent {
+	return PublishContent{ID: s.ID, Text: s.Caption, MediaType: s.MediaType, ImageIDs: s.ImageIDs}
+}
+
+func (p threadsPublisher) Channel() string { return "threads" }
+
+func (p threadsPublisher) Validate(ctx context.Context, c PublishContent) error {
+	if msg := validateThreadsPost(ctx, c.Text, c.MediaType, c.ImageIDs); msg != "" {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+func (p threadsPublisher) UploadMedia(ctx context.Context, c PublishContent) (string, error) {
+	params := url.Values{}
+	if c.Text != "" {
+		params.Set("text", c.Text)
+	}
+
+	switch c.MediaType {
+	case "text":
+		params.Set("media_type", "TEXT")
+	case "image":
+		params.Set("media_type", "IMAGE")
+		params.Set("image_url", getPublicImageURL(c.ImageIDs[0]))
+	case "carousel":
+		var childIDs []string
+		for _, imgID := range c.ImageIDs {
+			childID, err := p.createContainer(url.Values{
+				"media_type":       {"IMAGE"},
+				"image_url":        {getPublicImageURL(imgID)},
+				"is_carousel_item": {"true"},
+			})
+			if err != nil {
+				return "", fmt.Errorf("create carousel item: %w", err)
+			}
+			childIDs = append(childIDs, childID)
+		}
+		params.Set("media_type", "CAROUSEL")
+		params.Set("children", strings.Join(childIDs, ","))
+	default:
+		return "", fmt.Errorf("unsupported media type: %s", c.MediaType)
+	}
+
+	containerID, err := p.createContainer(params)
+	if err != nil {
+		return "", fmt.Errorf("create container: %w", err)
+	}
+	return containerID, nil
+}
+
+func (p threadsPublisher) createContainer(params url.Values) (string, error) {
+	result, err := threadsGraphPost("/"+p.creds.ThreadsUserID+"/threads", p.creds.Token, params)
+	if err != nil {
+		return "", err
+	}
+	id, _ := result["id"].(string)
+	if id == "" {
+		return "", fmt.Errorf("unexpected response: no id field")
+	}
+	return id, nil
+}
+
+// waitForContainer polls the container until Threads finished processing it.
+func (p threadsPublisher) waitForContainer(ctx context.Context, containerID string) error {
+	for i := 0; i < 30; i++ {
+		result, err := threadsGraphGet("/"+containerID, p.creds.Token, url.Values{"fields": {"status,error_message"}})
+		if err != nil {
+			return err
+		}
+		switch status, _ := result["status"].(string); status {
+		case "FINISHED":
+			return nil
+		case "ERROR", "EXPIRED":
+			reason, _ := result["error_message"].(string)
+			return fmt.Errorf("container processing failed: %s %s", status, reason)
+		}
+		// IN_PROGRESS — wait and retry
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+	return fmt.Errorf("container not ready after 60s")
+}
+
+func (p threadsPublisher) Publish(ctx context.Context, c PublishContent, containerID string) (string, error) {
+	if err := p.waitForContainer(ctx, containerID); err != nil {
+		return "", fmt.Errorf("wait for container: %w", err)
+	}
+	result, err := threadsGraphPost("/"+p.creds.ThreadsUserID+"/threads_publish", p.creds.Token, url.Values{
+		"creation_id": {containerID},
+	})
+	if err != nil {
+		return "", fmt.Errorf("publish: %w", err)
+	}
+	mediaID, _ := result["id"].(string)
+	if mediaID == "" {
+		return "", fmt.Errorf("unexpected response: no id field")
+	}
+	return mediaID, nil
+}
+
+func (p threadsPublisher) FetchStatus(ctx context.Context, c PublishContent, postID string) (PublishStatus, error) {
+	if _, err := threadsGraphGet("/"+postID, p.creds.Token, url.Values{"fields": {"id"}}); err != nil {
+		return PublishStatus{}, err
+	}
+	return PublishStatus{State: "published"}, nil
+}
+
+func (p threadsPublisher) FetchMetrics(ctx context.Context, postID string) (map[string]int64, error) {
+	result, err := threadsGraphGet("/"+postID+"/insights", p.creds.Token, url.Values{"metric": {strings.Join(threadsMetrics, ",")}})
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]int64{}
+	data, _ := result["data"].([]interface{})
+	for _, item := range data {
+		metric, _ := item.(map[string]interface{})
+		name, _ := metric["name"].(string)
+		if total, ok := metric["total_value"].(map[string]interface{}); ok {
+			values[name] = graphInt64(total["value"])
+			continue
+		}
+		vals, _ := metric["values"].([]interface{})
+		if len(vals) > 0 {
+			first, _ := vals[0].(map[string]interface{})
+			values[name] = graphInt64(first["value"])
+		}
+	}
+	return values, nil
+}
+
+// crosspostToThreads publishes an Instagram schedule's caption and images to Threads.
+func crosspostToThreads(schedule models.InstagramSchedule) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	p, err := newPublisher(ctx, "threads", schedule.UserID, schedule.OrgID)
+	if err != nil {
+		return "", err
+	}
+	return publishWith(ctx, p, PublishContent{
+		ID:        schedule.ID,
+		Text:      schedule.Caption,
+		MediaType: threadsCrosspostMediaType(schedule.ImageIDs),
+		ImageIDs:  schedule.ImageIDs,
+	})
+}
+
+// threadsCrosspostMediaType is the Threads media type of an Instagram post's images.
+func threadsCrosspostMediaType(imageIDs []string) string {
+	if len(imageIDs) > 1 {
+		return "carousel"
+	}
+	return "image"
+}
+
+// validateThreadsCrosspost checks that an Instagram post can also go to Threads:
+// the org has Threads connected and the caption and images fit Threads' limits.
+// Returns an error message, or "" when valid.
+func validateThreadsCrosspost(ctx context.Context, orgID primitive.ObjectID, caption string, imageIDs []string) string {
+	creds, err := getThreadsCredentials(ctx, orgID)
+	if err != nil || creds == nil {
+		return "Threads is not configured for this organization"
+	}
+	if msg := validateThreadsPost(ctx, caption, threadsCrosspostMediaType(imageIDs), imageIDs); msg != "" {
+		return "Threads: " + msg
+	}
+	return ""
+}
+
+// ─── Config ──────────────────────────────────────────────────────────
+
+// GetThreadsConfig returns whether Threads is configured
+// @Summary Obter configuração do Threads
+// @Description Retorna o perfil do Threads conectado à organização atual
+// @Tags threads
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ThreadsConfigResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error checking config"
+// @Router /admin/threads/config [get]
+func GetThreadsConfig(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var cfg models.ThreadsConfig
+	err := database.ThreadsConfigs().FindOne(ctx, bson.M{"org_id": orgID}).Decode(&cfg)
+	if err == mongo.ErrNoDocuments {
+		json.NewEncoder(w).Encode(models.ThreadsConfigResponse{})
+		return
+	}
+	if err != nil {
+		slog.Error("get_threads_config_error", "error", err)
+		http.Error(w, "Error checking config", http.StatusInternalServerError)
+		return
+	}
+
+	resp := models.ThreadsConfigResponse{
+		Configured:    true,
+		ThreadsUserID: maskAccountID(cfg.ThreadsUserID),
+		Username:      cfg.Username,
+		TokenSource:   cfg.TokenSource,
+		Token:         tokenHealth(cfg.TokenMeta),
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SaveThreadsConfig connects the org's Threads profile with a manually issued token
+// @Summary Salvar configuração do Threads
+// @Description Conecta o perfil do Threads da organização com um token de usuário do Threads gerado manualmente. Para conectar via OAuth, use /admin/threads/oauth/url
+// @Tags threads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.SaveThreadsConfigRequest true "Token de acesso do Threads"
+// @Success 200 {object} models.ThreadsConfigResponse
+// @Failure 400 {string} string "access_token is required"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 502 {string} string "Threads profile not found"
+// @Failure 503 {string} string "Encryption not configured"
+// @Router /admin/threads/config [put]
+func SaveThreadsConfig(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+	if userID == primitive.NilObjectID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !crypto.Available() {
+		http.Error(w, "Encryption not configured (ENCRYPTION_KEY missing)", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req models.SaveThreadsConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	token, source := strings.TrimSpace(req.AccessToken), "manual"
+	if token == "" {
+		http.Error(w, "access_token is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// Short-lived tokens are exchanged for a 60-day one; an already long-lived
+	// token fails the exchange and is kept as is, refreshed later by the job.
+	expiresIn := 0
+	if longToken, exp, err := exchangeThreadsLongLivedToken(token); err == nil {
+		token, expiresIn = longToken, exp
+	}
+
+	cfg, err := connectThreadsProfile(ctx, userID, orgID, token, source, newThreadsTokenMeta(expiresIn))
+	if err != nil {
+		slog.Warn("threads_connect_failed", "org_id", orgID.Hex(), "error", err)
+		http.Error(w, "Threads profile not found for this token: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	slog.Info("threads_config_saved",
+		"user_id", userID.Hex(),
+		"threads_user_id", maskAccountID(cfg.ThreadsUserID),
+		"token_source", source,
+	)
+
+	json.NewEncoder(w).Encode(models.ThreadsConfigResponse{
+		Configured:    true,
+		ThreadsUserID: maskAccountID(cfg.ThreadsUserID),
+		Username:      cfg.Username,
+		TokenSource:   cfg.TokenSource,
+		Token:         tokenHealth(cfg.TokenMeta),
+	})
+}
+
+// DeleteThreadsConfig disconnects the org's Threads profile
+// @Summary Remover configuração do Threads
+// @Description Desconecta o perfil do Threads da organização
+// @Tags threads
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {string} string "No config found"
+// @Failure 500 {string} string "Error deleting config"
+// @Router /admin/threads/config [delete]
+func DeleteThreadsConfig(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := database.ThreadsConfigs().DeleteOne(ctx, bson.M{"org_id": orgID})
+	if err != nil {
+		slog.Error("delete_threads_config_error", "error", err)
+		http.Error(w, "Error deleting config", http.StatusInternalServerError)
+		return
+	}
+	if result.DeletedCount == 0 {
+		http.Error(w, "No config found", http.StatusNotFound)
+		return
+	}
+
+	slog.Info("threads_config_deleted", "org_id", orgID.Hex())
+	json.NewEncoder(w).Encode(map[string]string{"message": "Config deleted"})
+}
+
+// ─── Schedules ───────────────────────────────────────────────────────
+
+// CreateThreadsSchedule creates a new scheduled Threads post
+// @Summary Criar agendamento de post no Threads
+// @Description Cria um novo post agendado para o perfil do Threads (texto, imagem ou carrossel; até 500 caracteres)
+// @Tags threads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.CreateThreadsScheduleRequest true "Dados do agendamento"
+// @Success 201 {object} models.ThreadsScheduleResponse
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error creating schedule"
+// @Router /admin/threads/schedules [post]
+func CreateThreadsSchedule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+	if userID == primitive.NilObjectID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateThreadsScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	scheduledAt, err := time.Parse(time.RFC3339, req.ScheduledAt)
+	if err != nil {
+		http.Error(w, "scheduled_at must be a valid ISO 8601 date", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if msg := validateThreadsPost(ctx, req.Caption, req.MediaType, req.ImageIDs); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	status, approvals := initialApprovalState(ctx, orgID, userID)
+
+	now := time.Now()
+	schedule := models.ThreadsSchedule{
+		ID:          primitive.NewObjectID(),
+		UserID:      userID,
+		OrgID:       orgID,
+		Caption:     req.Caption,
+		MediaType:   req.MediaType,
+		ImageIDs:    req.ImageIDs,
+		ScheduledAt: scheduledAt,
+		Status:      status,
+		Approvals:   approvals,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if schedule.ImageIDs == nil {
+		schedule.ImageIDs = []string{}
+	}
+
+	if _, err := database.ThreadsSchedules().InsertOne(ctx, schedule); err != nil {
+		http.Error(w, "Error creating schedule", http.StatusInternalServerError)
+		return
+	}
+	if status == "pending_approval" {
+		notifyReviewers(ctx, orgID, 1, schedule.Caption)
+	}
+
+	slog.Info("threads_schedule_created",
+		"schedule_id", schedule.ID.Hex(),
+		"user_id", userID.Hex(),
+		"scheduled_at", scheduledAt.Format(time.RFC3339),
+	)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(buildThreadsScheduleResponse(schedule))
+}
+
+// ListThreadsSchedules lists scheduled Threads posts with pagination and filtering
+// @Summary Listar agendamentos do Threads
+// @Description Lista posts agendados do Threads com paginação e filtro por status
+// @Tags threads
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Página (padrão 1)"
+// @Param limit query int false "Itens por página (padrão 10, máx 50)"
+// @Param status query string false "Filtrar por status"
+// @Success 200 {object} models.ThreadsScheduleListResponse
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "Error fetching schedules"
+// @Router /admin/threads/schedules [get]
+func ListThreadsSchedules(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	filter := bson.M{"org_id": orgID}
+	if status := r.URL.Query().Get("status"); status != "" {
+		filter["status"] = status
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	total, err := database.ThreadsSchedules().CountDocuments(ctx, filter)
+	if err != nil {
+		http.Error(w, "Error counting schedules", http.StatusInternalServerError)
+		return
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "scheduled_at", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := database.ThreadsSchedules().Find(ctx, filter, opts)
+	if err != nil {
+		http.Error(w, "Error fetching schedules", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var schedules []models.ThreadsSchedule
+	if err := cursor.All(ctx, &schedules); err != nil {
+		http.Error(w, "Error decoding schedules", http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]primitive.ObjectID, len(schedules))
+	for i, s := range schedules {
+		ids[i] = s.ID
+	}
+	counts := contentCommentCounts(ctx, orgID, "threads_schedule", ids)
+
+	responses := make([]models.ThreadsScheduleResponse, len(schedules))
+	for i, s := range schedules {
+		responses[i] = buildThreadsScheduleResponse(s)
+		responses[i].CommentCount = counts[s.ID]
+	}
+
+	json.NewEncoder(w).Encode(models.ThreadsScheduleListResponse{
+		Schedules: responses,
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	})
+}
+
+// GetThreadsSchedule returns a single Threads schedule by ID
+// @Summary Obter agendamento do Threads
+// @Description Retorna um agendamento do Threads pelo ID
+// @Tags threads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} models.ThreadsScheduleResponse
+// @Failure 400 {string} string "Invalid schedule ID"
+// @Failure 404 {string} string "Schedule not found"
+// @Router /admin/threads/schedules/{id} [get]
+func GetThreadsSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	schedule, ok := findThreadsSchedule(ctx, w, r)
+	if !ok {
+		return
+	}
+	json.NewEncoder(w).Encode(buildThreadsScheduleResponse(schedule))
+}
+
+// UpdateThreadsSchedule updates a scheduled Threads post (only if scheduled, failed or in review)
+// @Summary Atualizar agendamento do Threads
+// @Description Atualiza um post agendado do Threads (somente se agendado, com falha ou em aprovação)
+// @Tags threads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Param body body models.UpdateThreadsScheduleRequest true "Dados para atualização"
+// @Success 200 {object} models.ThreadsScheduleResponse
+// @Failure 400 {string} string "Dados inválidos"
+// @Failure 404 {string} string "Schedule not found"
+// @Failure 500 {string} string "Error updating schedule"
+// @Router /admin/threads/schedules/{id} [put]
+func UpdateThreadsSchedule(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	var req models.UpdateThreadsScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	schedule, ok := findThreadsSchedule(ctx, w, r)
+	if !ok {
+		return
+	}
+	if !calendarEditable(schedule.Status) && schedule.Status != "rejected" {
+		http.Error(w, "Can only edit scheduled, failed or in-review posts", http.StatusBadRequest)
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	setFields := update["$set"].(bson.M)
+
+	if req.Caption != nil {
+		schedule.Caption = *req.Caption
+		setFields["caption"] = schedule.Caption
+	}
+	if req.MediaType != nil {
+		schedule.MediaType = *req.MediaType
+		setFields["media_type"] = schedule.MediaType
+	}
+	if req.ImageIDs != nil {
+		schedule.ImageIDs = req.ImageIDs
+		setFields["image_ids"] = schedule.ImageIDs
+	}
+	if req.ScheduledAt != nil {
+		scheduledAt, err := time.Parse(time.RFC3339, *req.ScheduledAt)
+		if err != nil {
+			http.Error(w, "scheduled_at must be a valid ISO 8601 date", http.StatusBadRequest)
+			return
+		}
+		setFields["scheduled_at"] = scheduledAt
+	}
+	if msg := validateThreadsPost(ctx, schedule.Caption, schedule.MediaType, schedule.ImageIDs); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// If re-scheduling a failed post, reset status
+	if schedule.Status == "failed" {
+		setFields["status"] = "scheduled"
+		setFields["error_message"] = ""
+	}
+
+	// Under the approval workflow, edits send the post back to review
+	resubmitted := resubmitOnEdit(ctx, orgID, middleware.GetUserID(r), schedule.Status)
+	if resubmitted != nil {
+		setFields["status"] = "pending_approval"
+		update["$push"] = bson.M{"approvals": *resubmitted}
+	}
+
+	if _, err := database.ThreadsSchedules().UpdateOne(ctx, bson.M{"_id": schedule.ID, "org_id": orgID}, update); err != nil {
+		http.Error(w, "Error updating schedule", http.StatusInternalServerError)
+		return
+	}
+
+	var updated models.ThreadsSchedule
+	database.ThreadsSchedules().FindOne(ctx, bson.M{"_id": schedule.ID, "org_id": orgID}).Decode(&updated)
+	if resubmitted != nil {
+		notifyReviewers(ctx, orgID, 1, updated.Caption)
+	}
+
+	slog.Info("threads_schedule_updated", "schedule_id", schedule.ID.Hex())
+	json.NewEncoder(w).Encode(buildThreadsScheduleResponse(updated))
+}
+
+// DeleteThreadsSchedule deletes a scheduled Threads post
+// @Summary Deletar agendamento do Threads
+// @Description Remove um post agendado do Threads (não pode deletar posts em publicação)
+// @Tags threads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {string} string "Cannot delete a post that is currently publishing"
+// @Failure 404 {string} string "Schedule not found"
+// @Failure 500 {string} string "Error deleting schedule"
+// @Router /admin/threads/schedules/{id} [delete]
+func DeleteThreadsSchedule(w http.ResponseWriter, r *http.Request) {
+	orgID := middleware.GetOrgID(r)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	schedule, ok := findThreadsSchedule(ctx, w, r)
+	if !ok {
+		return
+	}
+	if schedule.Status == "publishing" {
+		http.Error(w, "Cannot delete a post that is currently publishing", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := database.ThreadsSchedules().DeleteOne(ctx, bson.M{"_id": schedule.ID, "org_id": orgID}); err != nil {
+		http.Error(w, "Error deleting schedule", http.StatusInternalServerError)
+		return
+	}
+	deleteContentComments(ctx, orgID, "threads_schedule", schedule.ID)
+
+	slog.Info("threads_schedule_deleted", "schedule_id", schedule.ID.Hex())
+	json.NewEncoder(w).Encode(map[string]string{"message": "Schedule deleted"})
+}
+
+// GetThreadsScheduleInsights returns the latest metrics of a published Threads post
+// @Summary Métricas de post do Threads
+// @Description Retorna as métricas atuais (visualizações, curtidas, respostas, reposts, citações e compartilhamentos) de um post publicado
+// @Tags threads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} models.ThreadsPostMetrics
+// @Failure 400 {string} string "Schedule not published yet"
+// @Failure 404 {string} string "Schedule not found"
+// @Failure 502 {string} string "Error fetching metrics"
+// @Router /admin/threads/schedules/{id}/insights [get]
+func GetThreadsScheduleInsights(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	schedule, ok := findThreadsSchedule(ctx, w, r)
+	if !ok {
+		return
+	}
+	if schedule.ThreadsMediaID == "" {
+		http.Error(w, "Schedule not published yet", http.StatusBadRequest)
+		return
+	}
+
+	p, err := newPublisher(ctx, "threads", schedule.UserID, schedule.OrgID)
+	if err != nil {
+		http.Error(w, "Threads not configured", http.StatusBadRequest)
+		return
+	}
+	metrics, err := p.FetchMetrics(ctx, schedule.ThreadsMediaID)
+	if err != nil {
+		slog.Warn("threads_insights_error", "schedule_id", schedule.ID.Hex(), "error", err)
+		http.Error(w, "Error fetching metrics", http.StatusBadGateway)
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.ThreadsPostMetrics{
+		MediaID:   schedule.ThreadsMediaID,
+		Metrics:   metrics,
+		FetchedAt: time.Now(),
+	})
+}
+
+// findThreadsSchedule loads the {id} schedule of the caller's org.
+func findThreadsSchedule(ctx context.Context, w http.ResponseWriter, r *http.Request) (models.ThreadsSchedule, bool) {
+	oid, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+		return models.ThreadsSchedule{}, false
+	}
+	var schedule models.ThreadsSchedule
+	if err := database.ThreadsSchedules().FindOne(ctx, bson.M{"_id": oid, "org_id": middleware.GetOrgID(r)}).Decode(&schedule); err != nil {
+		http.Error(w, "Schedule not found", http.StatusNotFound)
+		return models.ThreadsSchedule{}, false
+	}
+	return schedule, true
+}
+
+func buildThreadsScheduleResponse(s models.ThreadsSchedule) models.ThreadsScheduleResponse {
+	imageURLs := make([]string, len(s.ImageIDs))
+	for i, id := range s.ImageIDs {
+		imageURLs[i] = "/api/v1/blog/images/" + id
+	}
+	return models.ThreadsScheduleResponse{ThreadsSchedule: s, ImageURLs: imageURLs}
+}
+
+// ProcessScheduledThreadsPosts checks for due Threads posts and publishes them
+func ProcessScheduledThreadsPosts() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cursor, err := database.ThreadsSchedules().Find(ctx, bson.M{
+		"status":       "scheduled",
+		"scheduled_at": bson.M{"$lte": time.Now()},
+	}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		slog.Error("threads_scheduler_query_error", "error", err)
+		return
+	}
+	var due []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &due); err != nil {
+		slog.Error("threads_scheduler_decode_error", "error", err)
+		return
+	}
+
+	for _, d := range due {
+		publishDueThreadsSchedule(d.ID)
+	}
+}
+
+// publishDueThreadsSchedule claims one due schedule and publishes it. Container
+// processing can take a while, so every step uses its own context and the claim
+// only succeeds while the schedule is still due.
+func publishDueThreadsSchedule(id primitive.ObjectID) {
+	cctx, ccancel := context.WithTimeout(context.Background(), 10*time.Second)
+	var schedule models.ThreadsSchedule
+	err := database.ThreadsSchedules().FindOneAndUpdate(cctx,
+		bson.M{"_id": id, "status": "scheduled", "scheduled_at": bson.M{"$lte": time.Now()}},
+		bson.M{"$set": bson.M{"status": "publishing", "updated_at": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&schedule)
+	ccancel()
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			slog.Error("threads_scheduler_claim_error", "schedule_id", id.Hex(), "error", err)
+		}
+		return // edited, rescheduled or claimed meanwhile
+	}
+
+	mediaID, err := publishToThreads(schedule)
+
+	uctx, ucancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer ucancel()
+	if err != nil {
+		slog.Error("threads_publish_failed", "schedule_id", schedule.ID.Hex(), "error", err)
+		database.ThreadsSchedules().UpdateOne(uctx, bson.M{"_id": schedule.ID}, bson.M{
+			"$set": bson.M{
+				"status":        "failed",
+				"error_message": err.Error(),
+				"updated_at":    time.Now(),
+			},
+		})
+		return
+	}
+
+	database.ThreadsSchedules().UpdateOne(uctx, bson.M{"_id": schedule.ID}, bson.M{
+		"$set": bson.M{
+			"status":           "published",
+			"threads_media_id": mediaID,
+			"published_at":     time.Now(),
+			"updated_at":       time.Now(),
+		},
+	})
+
+	middleware.IncThreadsPublished()
+	slog.Info("threads_published", "schedule_id", schedule.ID.Hex(), "threads_media_id", mediaID)
+}
+
+// publishToThreads publishes a scheduled post to the org's Threads profile.
+func publishToThreads(schedule models.ThreadsSchedule) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	p, err := newPublisher(ctx, "threads", schedule.UserID, schedule.OrgID)
+	if err != nil {
+		return "", err
+	}
+	return publishWith(ctx, p, threadsPublishContent(schedule))
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tron-legacy/api/internal/config"
+	"github.com/tron-legacy/api/internal/crypto"
+	"github.com/tron-legacy/api/internal/database"
+	"github.com/tron-legacy/api/internal/middleware"
+	"github.com/tron-legacy/api/internal/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Threads has its own OAuth flow (threads.net, Threads app ID); Facebook Login
+// tokens are not accepted by graph.threads.net.
+const (
+	threadsOAuthScopes = "threads_basic,threads_content_publish,threads_manage_insights"
+	threadsTokenBase   = "https://graph.threads.net"
+)
+
+func threadsRedirectURI() string {
+	return fmt.Sprintf("%s/threads/callback", strings.TrimRight(config.Get().FrontendURL, "/"))
+}
+
+// ThreadsOAuthURL godoc
+// @Summary Obter URL de autenticação do Threads
+// @Description Retorna a URL de autorização do Threads (threads.net) para conectar o perfil da organização
+// @Tags threads
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {string} string "THREADS_APP_ID not configured"
+// @Router /admin/threads/oauth/url [get]
+func ThreadsOAuthURL(w http.ResponseWriter, r *http.Request) {
+	cfg := config.Get()
+	if cfg.ThreadsAppID == "" {
+		http.Error(w, "THREADS_APP_ID not configured", http.StatusInternalServerError)
+		return
+	}
+
+	oauthURL := fmt.Sprintf(
+		"https://threads.net/oauth/authorize?client_id=%s&redirect_uri=%s&scope=%s&state=%s&response_type=code",
+		url.QueryEscape(cfg.ThreadsAppID),
+		url.QueryEscape(threadsRedirectURI()),
+		url.QueryEscape(threadsOAuthScopes),
+		url.QueryEscape(middleware.GetOrgID(r).Hex()),
+	)
+
+	json.NewEncoder(w).Encode(map[string]string{"url": oauthURL})
+}
+
+// ThreadsOAuthCallback godoc
+// @Summary Callback de autenticação do Threads
+// @Description Troca o código de autorização do Threads por um token de longa duração e conecta o perfil à organização
+// @Tags threads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.ThreadsOAuthCallbackRequest true "Código OAuth"
+// @Success 200 {object} models.ThreadsConfigResponse
+// @Failure 400 {string} string "code is required"
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 502 {string} string "Falha ao trocar codigo por token"
+// @Router /admin/threads/oauth/callback [post]
+func ThreadsOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	cfg := config.Get()
+	if cfg.ThreadsAppID == "" || cfg.ThreadsAppSecret == "" {
+		http.Error(w, "Threads app credentials not configured", http.StatusInternalServerError)
+		return
+	}
+
+	userID := middleware.GetUserID(r)
+	orgID := middleware.GetOrgID(r)
+	if userID == primitive.NilObjectID {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if !crypto.Available() {
+		http.Error(w, "Encryption not configured", http.StatusInternalServerError)
+		return
+	}
+
+	var req models.ThreadsOAuthCallbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	// Threads appends "#_" to the code in the redirect
+	code := strings.TrimSuffix(strings.TrimSpace(req.Code), "#_")
+	if code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	shortToken, err := exchangeThreadsCode(code)
+	if err != nil {
+		slog.Error("threads_oauth_code_exchange_failed", "error", err)
+		http.Error(w, "Falha ao trocar codigo por token: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	token, expiresIn, err := exchangeThreadsLongLivedToken(shortToken)
+	if err != nil {
+		slog.Warn("threads_oauth_long_token_failed, using short token", "error", err)
+		token, expiresIn = shortToken, 3600
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	threadsCfg, err := connectThreadsProfile(ctx, userID, orgID, token, "oauth", newThreadsTokenMeta(expiresIn))
+	if err != nil {
+		slog.Warn("threads_connect_failed", "org_id", orgID.Hex(), "error", err)
+		http.Error(w, "Threads profile not found for this token: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	slog.Info("threads_oauth_connected",
+		"org_id", orgID.Hex(),
+		"threads_user_id", maskAccountID(threadsCfg.ThreadsUserID),
+	)
+
+	json.NewEncoder(w).Encode(models.ThreadsConfigResponse{
+		Configured:    true,
+		ThreadsUserID: maskAccountID(threadsCfg.ThreadsUserID),
+		Username:      threadsCfg.Username,
+		TokenSource:   threadsCfg.TokenSource,
+		Token:         tokenHealth(threadsCfg.TokenMeta),
+	})
+}
+
+// exchangeThreadsCode exchanges an authorization code for a short-lived Threads user token.
+func exchangeThreadsCode(code string) (string, error) {
+	cfg := config.Get()
+	token, _, err := threadsTokenRequest(http.MethodPost, "/oauth/access_token", url.Values{
+		"client_id":     {cfg.ThreadsAppID},
+		"client_secret": {cfg.ThreadsAppSecret},
+		"grant_type":    {"authorization_code"},
+		"redirect_uri":  {threadsRedirectURI()},
+		"code":          {code},
+	})
+	return token, err
+}
+
+// exchangeThreadsLongLivedToken exchanges a short-lived Threads token for a
+// 60-day one and returns its lifetime in seconds.
+func exchangeThreadsLongLivedToken(shortToken string) (string, int, error) {
+	cfg := config.Get()
+	if cfg.ThreadsAppSecret == "" {
+		return "", 0, fmt.Errorf("THREADS_APP_SECRET not configured")
+	}
+	return threadsTokenRequest(http.MethodGet, "/access_token", url.Values{
+		"grant_type":    {"th_exchange_token"},
+		"client_secret": {cfg.ThreadsAppSecret},
+		"access_token":  {shortToken},
+	})
+}
+
+// refreshThreadsToken extends a long-lived Threads token (at least a day old,
+// not yet expired) for another 60 days.
+func refreshThreadsToken(token string) (string, int, error) {
+	return threadsTokenRequest(http.MethodGet, "/refresh_access_token", url.Values{
+		"grant_type":   {"th_refresh_token"},
+		"access_token": {token},
+	})
+}
+
+// threadsTokenRequest calls a Threads token endpoint and returns the token and its lifetime.
+func threadsTokenRequest(method, endpoint string, params url.Values) (string, int, error) {
+	var resp *http.Response
+	var err error
+	if method == http.MethodPost {
+		resp, err = http.PostForm(threadsTokenBase+endpoint, params)
+	} else {
+		resp, err = http.Get(threadsTokenBase + endpoint + "?" + params.Encode())
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       *struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", 0, fmt.Errorf("decode failed: %w", err)
+	}
+	if result.Error != nil {
+		return "", 0, fmt.Errorf("threads error %d: %s", result.Error.Code, result.Error.Message)
+	}
+	if result.AccessToken == "" {
+		return "", 0, fmt.Errorf("empty access_token in response")
+	}
+	return result.AccessToken, result.ExpiresIn, nil
+}
+
+// newThreadsTokenMeta builds the metadata of a freshly obtained Threads token.
+// Threads has no debug_token, so the expiry comes from expires_in (0 = unknown).
+func newThreadsTokenMeta(expiresIn int) *models.TokenMeta {
+	now := time.Now()
+	meta := &models.TokenMeta{CheckedAt: &now, Scopes: strings.Split(threadsOAuthScopes, ",")}
+	if expiresIn > 0 {
+		t := now.Add(time.Duration(expiresIn) * time.Second)
+		meta.ExpiresAt = &t
+	}
+	meta.Status = tokenStatusAt(meta.ExpiresAt, now)
+	return meta
+}
+
+// RefreshThreadsTokens refreshes Threads tokens that are about to expire (or
+// whose expiry is unknown), marks dead ones and notifies owners once per status
+// change, like RefreshMetaTokens does for the Meta tokens.
+func RefreshThreadsTokens() {
+	if !crypto.Available() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var cfgs []models.ThreadsConfig
+	cursor, err := database.ThreadsConfigs().Find(ctx, bson.M{"access_token_enc": bson.M{"$ne": ""}})
+	if err != nil {
+		slog.Error("threads_token_refresh_query", "error", err)
+		return
+	}
+	if err := cursor.All(ctx, &cfgs); err != nil {
+		slog.Error("threads_token_refresh_decode", "error", err)
+		return
+	}
+
+	refreshed, invalid := 0, 0
+	for _, c := range cfgs {
+		token, err := crypto.Decrypt(c.AccessTokenEnc)
+		if err != nil {
+			slog.Warn("threads_token_refresh_decrypt", "org_id", c.OrgID.Hex(), "error", err)
+			continue
+		}
+
+		now := time.Now()
+		meta := &models.TokenMeta{CheckedAt: &now}
+		if c.TokenMeta != nil {
+			copied := *c.TokenMeta
+			meta = &copied
+			meta.CheckedAt = &now
+			meta.Error = ""
+		}
+		meta.Status = tokenStatusAt(meta.ExpiresAt, now)
+
+		set := bson.M{}
+		if meta.Status != "expired" && (meta.ExpiresAt == nil || meta.Status == "expiring") {
+			newToken, expiresIn, err := refreshThreadsToken(token)
+			if err == nil {
+				enc, encErr := crypto.Encrypt(newToken)
+				if encErr == nil {
+					fresh := newThreadsTokenMeta(expiresIn)
+					fresh.RefreshedAt = &now
+					fresh.NotifiedStatus = meta.NotifiedStatus
+					meta = fresh
+					set["access_token_enc"] = enc
+					refreshed++
+					slog.Info("token_refreshed", "label", "Threads", "org_id", c.OrgID.Hex())
+				}
+			} else {
+				meta.Error = "refresh failed: " + err.Error()
+				slog.Warn("token_refresh_exchange", "label", "Threads", "org_id", c.OrgID.Hex(), "error", err)
+				// A token of unknown expiry that can't be refreshed may be dead; check it
+				if meta.ExpiresAt == nil {
+					if _, err := threadsGraphGet("/me", token, url.Values{"fields": {"id"}}); err != nil {
+						meta.Status = "invalid"
+					}
+				}
+			}
+		}
+
+		if meta.Status == "valid" {
+			meta.NotifiedStatus = ""
+		} else if meta.NotifiedStatus != meta.Status {
+			title, message := tokenNotificationText("Threads", meta)
+			notifyOrgOwners(ctx, c.OrgID, "token_"+meta.Status, title, message, "/admin/settings")
+			meta.NotifiedStatus = meta.Status
+		}
+		if meta.Status == "invalid" || meta.Status == "expired" {
+			invalid++
+		}
+
+		set["token_meta"] = meta
+		// Matching the old token keeps a reconnect made meanwhile from being overwritten
+		res, err := database.ThreadsConfigs().UpdateOne(ctx,
+			bson.M{"_id": c.ID, "access_token_enc": c.AccessTokenEnc},
+			bson.M{"$set": set},
+		)
+		if err != nil {
+			slog.Error("token_refresh_update", "label", "Threads", "org_id", c.OrgID.Hex(), "error", err)
+		} else if res.MatchedCount == 0 {
+			slog.Info("token_refresh_skipped_reconnected", "label", "Threads", "org_id", c.OrgID.Hex())
+		}
+	}
+
+	slog.Info("threads_token_refresh_cycle_complete", "tokens", len(cfgs), "refreshed", refreshed, "invalid", invalid)
+}
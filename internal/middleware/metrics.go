@@ -43,6 +43,9 @@ type Metrics struct {
 
 	// Facebook metrics
 	facebookPublished int64
+
+	// Threads metrics
+	threadsPublished int64
 }
 
 var metrics = &Metrics{
@@ -166,6 +169,12 @@ func IncFacebookPublished() {
 	metrics.mu.Unlock()
 }
 
+func IncThreadsPublished() {
+	metrics.mu.Lock()
+	metrics.threadsPublished++
+	metrics.mu.Unlock()
+}
+
 // MetricsMiddleware collects HTTP metrics
 func MetricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -362,6 +371,11 @@ func PrometheusHandler() http.Handler {
 		w.Write([]byte("\n# HELP facebook_published_total Total number of Facebook posts published\n"))
 		w.Write([]byte("# TYPE facebook_published_total counter\n"))
 		w.Write([]byte("facebook_published_total " + strconv.FormatInt(metrics.facebookPublished, 10) + "\n"))
+
+		// Threads metrics
+		w.Write([]byte("\n# HELP threads_published_total Total number of Threads posts published\n"))
+		w.Write([]byte("# TYPE threads_published_total counter\n"))
+		w.Write([]byte("threads_published_total " + strconv.FormatInt(metrics.threadsPublished, 10) + "\n"))
 	})
 }
 
@@ -190,31 +190,11 @@ func RequirePermission(perm string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			orgRole := GetOrgRole(r)
 
-			// Owner and admin always have all permissions
-			if orgRole == "owner" || orgRole == "admin" {
+			if HasPermission(r, perm) {
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Viewer never has permissions
-			if orgRole == "viewer" {
-				w.WriteHeader(http.StatusForbidden)
-				json.NewEncoder(w).Encode(map[string]string{
-					"message":             "Forbidden: insufficient permissions",
-					"required_permission": perm,
-				})
-				return
-			}
-
-			// Member: check explicit permission
-			perms := GetOrgPermissions(r)
-			for _, p := range perms {
-				if p == perm {
-					next.ServeHTTP(w, r)
-					return
-				}
-			}
-
 			slog.Warn("org_permission_check_failed",
 				"user_id", GetUserID(r).Hex(),
 				"org_id", GetOrgID(r).Hex(),
@@ -229,3 +209,20 @@ func RequirePermission(perm string) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// HasPermission reports whether the caller has a granular permission in the current org,
+// with the same rules as RequirePermission. Used by handlers whose permission depends on the request.
+func HasPermission(r *http.Request, perm string) bool {
+	switch GetOrgRole(r) {
+	case "owner", "admin":
+		return true
+	case "viewer":
+		return false
+	}
+	for _, p := range GetOrgPermissions(r) {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
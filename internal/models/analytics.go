@@ -1,5 +1,11 @@
 package models
 
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
 // AutoReplyAnalytics is the response for auto-reply metrics.
 type AutoReplyAnalytics struct {
 	TotalSent    int64               `json:"total_sent"`
@@ -66,3 +72,43 @@ type PostingHourStat struct {
 	AvgEngagement float64 `json:"avg_engagement"`
 	PostCount     int     `json:"post_count"`
 }
+
+// IGAccountSnapshot is a daily capture of an Instagram account's counters and insights.
+// Insights (reach, impressions, profile views, website clicks) refer to Date; counters
+// (followers, follows, media) are read when the snapshot is taken.
+type IGAccountSnapshot struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID         primitive.ObjectID `json:"org_id" bson:"org_id"`
+	AccountID     string             `json:"account_id" bson:"account_id"`
+	Username      string             `json:"username,omitempty" bson:"username,omitempty"`
+	Date          string             `json:"date" bson:"date"` // YYYY-MM-DD (UTC) capture day; reach/impressions/views/clicks are the previous day's
+	Followers     int64              `json:"followers" bson:"followers"`
+	Follows       int64              `json:"follows" bson:"follows"`
+	MediaCount    int64              `json:"media_count" bson:"media_count"`
+	Reach         int64              `json:"reach" bson:"reach"`
+	Impressions   int64              `json:"impressions" bson:"impressions"`
+	ProfileViews  int64              `json:"profile_views" bson:"profile_views"`
+	WebsiteClicks int64              `json:"website_clicks" bson:"website_clicks"`
+	CapturedAt    time.Time          `json:"captured_at" bson:"captured_at"`
+}
+
+// MetricDelta compares a metric between the current and the previous period.
+type MetricDelta struct {
+	Current   int64    `json:"current"`
+	Previous  int64    `json:"previous"`
+	Change    int64    `json:"change"`
+	ChangePct *float64 `json:"change_pct,omitempty"` // nil when the previous period is zero
+}
+
+// AccountGrowthReport is the response for account growth over 7, 30 or 90 days.
+type AccountGrowthReport struct {
+	AccountID       string              `json:"account_id"`
+	Days            int                 `json:"days"`
+	FollowersGained MetricDelta         `json:"followers_gained"`
+	MediaPublished  MetricDelta         `json:"media_published"`
+	Reach           MetricDelta         `json:"reach"`
+	Impressions     MetricDelta         `json:"impressions"`
+	ProfileViews    MetricDelta         `json:"profile_views"`
+	WebsiteClicks   MetricDelta         `json:"website_clicks"`
+	Series          []IGAccountSnapshot `json:"series"` // current period, oldest first
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApprovalDecision is one entry of an item's approval history.
+type ApprovalDecision struct {
+	Action    string             `json:"action" bson:"action"` // "submitted", "approved" or "rejected"
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	UserEmail string             `json:"user_email,omitempty" bson:"user_email,omitempty"`
+	Comment   string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	At        time.Time          `json:"at" bson:"at"`
+}
+
+// ApprovalDecisionRequest is the request body for approving or rejecting an item.
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment"` // required when rejecting
+}
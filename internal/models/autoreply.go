@@ -6,13 +6,15 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// AutoReplyRule defines a keyword-triggered auto-response rule for Instagram.
+// AutoReplyRule defines a keyword-triggered auto-response rule for Instagram and
+// Facebook Pages (feed comments and Messenger).
 type AutoReplyRule struct {
 	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	UserID          primitive.ObjectID `json:"user_id" bson:"user_id"`
 	OrgID           primitive.ObjectID `json:"org_id" bson:"org_id"`
 	Name            string             `json:"name" bson:"name"`
-	TriggerType     string             `json:"trigger_type" bson:"trigger_type"` // "comment", "dm", "both"
+	TriggerType     string             `json:"trigger_type" bson:"trigger_type"` // "comment", "dm", "both", "mention" (Instagram only)
+	Channel         string             `json:"channel" bson:"channel,omitempty"` // "instagram" (default), "facebook" or "all"
 	Keywords        []string           `json:"keywords" bson:"keywords"`
 	ResponseMessage string             `json:"response_message" bson:"response_message"`
 	CommentReply    string             `json:"comment_reply,omitempty" bson:"comment_reply,omitempty"`
@@ -28,7 +30,8 @@ type AutoReplyLog struct {
 	RuleID           primitive.ObjectID `json:"rule_id" bson:"rule_id"`
 	OrgID            primitive.ObjectID `json:"org_id" bson:"org_id"`
 	RuleName         string             `json:"rule_name" bson:"rule_name"`
-	TriggerType      string             `json:"trigger_type" bson:"trigger_type"` // "comment" or "dm"
+	TriggerType      string             `json:"trigger_type" bson:"trigger_type"`           // "comment", "dm" or "mention"
+	Channel          string             `json:"channel,omitempty" bson:"channel,omitempty"` // "instagram" or "facebook"
 	SenderIGID       string             `json:"sender_ig_id" bson:"sender_ig_id"`
 	SenderUsername   string             `json:"sender_username,omitempty" bson:"sender_username,omitempty"`
 	TriggerText      string             `json:"trigger_text" bson:"trigger_text"`
@@ -43,6 +46,7 @@ type AutoReplyLog struct {
 type CreateAutoReplyRuleRequest struct {
 	Name            string   `json:"name"`
 	TriggerType     string   `json:"trigger_type"`
+	Channel         string   `json:"channel,omitempty"` // "instagram" (default), "facebook" or "all"
 	Keywords        []string `json:"keywords"`
 	ResponseMessage string   `json:"response_message"`
 	CommentReply    string   `json:"comment_reply,omitempty"`
@@ -53,6 +57,7 @@ type CreateAutoReplyRuleRequest struct {
 type UpdateAutoReplyRuleRequest struct {
 	Name            *string  `json:"name,omitempty"`
 	TriggerType     *string  `json:"trigger_type,omitempty"`
+	Channel         *string  `json:"channel,omitempty"`
 	Keywords        []string `json:"keywords,omitempty"`
 	ResponseMessage *string  `json:"response_message,omitempty"`
 	CommentReply    *string  `json:"comment_reply,omitempty"`
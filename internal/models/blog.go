@@ -81,13 +81,16 @@ type PostListResponse struct {
 
 // BlogImage represents an uploaded image stored in the images collection
 type BlogImage struct {
-	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	UploaderID primitive.ObjectID `json:"uploader_id" bson:"uploader_id"`
-	OrgID      primitive.ObjectID `json:"org_id" bson:"org_id"`
-	GroupID    string             `json:"group_id,omitempty" bson:"group_id,omitempty"`       // shared across size variants
-	SizeLabel  string             `json:"size_label,omitempty" bson:"size_label,omitempty"`   // "thumb", "card", or "banner"
-	Width      int                `json:"width,omitempty" bson:"width,omitempty"`             // image width in pixels
-	Data       string             `json:"-" bson:"data"`                                      // base64 data, never in JSON list responses
-	Size       int                `json:"size" bson:"size"`                                   // compressed size in bytes
-	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	ID         primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	UploaderID primitive.ObjectID  `json:"uploader_id" bson:"uploader_id"`
+	OrgID      primitive.ObjectID  `json:"org_id" bson:"org_id"`
+	GroupID    string              `json:"group_id,omitempty" bson:"group_id,omitempty"`       // shared across size variants
+	SizeLabel  string              `json:"size_label,omitempty" bson:"size_label,omitempty"`   // "thumb", "card", or "banner"
+	Width      int                 `json:"width,omitempty" bson:"width,omitempty"`             // image width in pixels
+	Height     int                 `json:"height,omitempty" bson:"height,omitempty"`           // image height in pixels
+	OriginalID *primitive.ObjectID `json:"original_id,omitempty" bson:"original_id,omitempty"` // full-resolution source kept for re-cropping
+	Crop       *ImageCrop          `json:"crop,omitempty" bson:"crop,omitempty"`
+	Data       string              `json:"-" bson:"data"`    // base64 data, never in JSON list responses
+	Size       int                 `json:"size" bson:"size"` // compressed size in bytes
+	CreatedAt  time.Time           `json:"created_at" bson:"created_at"`
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarItem is a scheduled or published post normalized across channels.
+type CalendarItem struct {
+	ID           string              `json:"id"`
+	Type         string              `json:"type"`     // "instagram_schedule", "facebook_schedule", "threads_schedule" or "integrated_publish"
+	Channels     []string            `json:"channels"` // "instagram", "facebook", "threads", "meta_ads"
+	Title        string              `json:"title"`    // first line of the caption, shortened
+	Caption      string              `json:"caption"`
+	MediaType    string              `json:"media_type"`
+	ScheduledAt  time.Time           `json:"scheduled_at"`
+	PublishedAt  *time.Time          `json:"published_at,omitempty"`
+	Status       string              `json:"status"`     // normalized: "pending_approval", "rejected", "scheduled", "publishing", "published", "failed"
+	RawStatus    string              `json:"raw_status"` // status in the source collection
+	ThumbnailURL string              `json:"thumbnail_url,omitempty"`
+	ImageCount   int                 `json:"image_count"`
+	SeriesID     *primitive.ObjectID `json:"series_id,omitempty"`
+	ErrorMessage string              `json:"error_message,omitempty"`
+	Editable     bool                `json:"editable"` // can be rescheduled
+	UserID       primitive.ObjectID  `json:"user_id"`  // author
+	Approvals    []ApprovalDecision  `json:"approvals,omitempty"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	CommentCount int                 `json:"comment_count"` // internal team comments
+	Warnings     []string            `json:"warnings,omitempty"`
+}
+
+// CalendarResponse is the response for the unified calendar.
+type CalendarResponse struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Timezone string         `json:"timezone"`
+	Items    []CalendarItem `json:"items"`
+	Total    int            `json:"total"`
+}
+
+// RescheduleCalendarItemRequest is the request body for moving a calendar item.
+type RescheduleCalendarItemRequest struct {
+	ScheduledAt string `json:"scheduled_at"` // ISO 8601
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarFeed is a user's secret iCalendar subscription for one organization.
+type CalendarFeed struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID `json:"org_id" bson:"org_id"`
+	UserID         primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Token          string             `json:"-" bson:"token"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	LastAccessedAt *time.Time         `json:"last_accessed_at,omitempty" bson:"last_accessed_at,omitempty"`
+}
+
+// CalendarFeedResponse describes the caller's feed subscription.
+type CalendarFeedResponse struct {
+	Enabled        bool       `json:"enabled"`
+	URL            string     `json:"url,omitempty"` // secret: anyone with the URL can read the feed
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
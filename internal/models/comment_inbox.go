@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InboxComment is a comment ingested into the unified moderation inbox.
+// Comments arrive from webhooks and from the periodic backfill job.
+type InboxComment struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID  `json:"org_id" bson:"org_id"`
+	Platform       string              `json:"platform" bson:"platform"`     // "instagram" or "facebook"
+	AccountID      string              `json:"account_id" bson:"account_id"` // IG account ID or FB page ID
+	CommentID      string              `json:"comment_id" bson:"comment_id"`
+	ParentID       string              `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	MediaID        string              `json:"media_id" bson:"media_id"` // IG media ID or FB post ID
+	Text           string              `json:"text" bson:"text"`
+	AuthorID       string              `json:"author_id" bson:"author_id"`
+	AuthorUsername string              `json:"author_username,omitempty" bson:"author_username,omitempty"`
+	Status         string              `json:"status" bson:"status"` // "open", "done", "deleted"
+	Hidden         bool                `json:"hidden" bson:"hidden"`
+	AssignedTo     *primitive.ObjectID `json:"assigned_to,omitempty" bson:"assigned_to,omitempty"`
+	Replies        []InboxCommentReply `json:"replies" bson:"replies"`
+	Source         string              `json:"source" bson:"source"` // "webhook" or "backfill"
+	CommentedAt    time.Time           `json:"commented_at" bson:"commented_at"`
+	CreatedAt      time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// InboxCommentReply is a public reply sent from the inbox.
+type InboxCommentReply struct {
+	Message   string             `json:"message" bson:"message"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// ReplyInboxCommentRequest is the request body for replying to a comment.
+type ReplyInboxCommentRequest struct {
+	Message string `json:"message"`
+}
+
+// HideInboxCommentRequest is the request body for hiding/unhiding a comment.
+type HideInboxCommentRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// UpdateInboxCommentStatusRequest is the request body for changing the inbox status.
+type UpdateInboxCommentStatusRequest struct {
+	Status string `json:"status"` // "open" or "done"
+}
+
+// AssignInboxCommentRequest is the request body for assigning a comment to a member.
+// An empty user_id removes the assignment.
+type AssignInboxCommentRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// InboxCommentListResponse is the paginated response for listing inbox comments.
+type InboxCommentListResponse struct {
+	Comments []InboxComment `json:"comments"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	Limit    int            `json:"limit"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Competitor is a public Instagram business/creator account tracked by an org.
+type Competitor struct {
+	ID                primitive.ObjectID    `json:"id" bson:"_id,omitempty"`
+	OrgID             primitive.ObjectID    `json:"org_id" bson:"org_id"`
+	Username          string                `json:"username" bson:"username"`
+	Name              string                `json:"name,omitempty" bson:"name,omitempty"`
+	ProfilePictureURL string                `json:"profile_picture_url,omitempty" bson:"profile_picture_url,omitempty"`
+	Alerts            CompetitorAlertConfig `json:"alerts" bson:"alerts"`
+	LastSnapshotAt    *time.Time            `json:"last_snapshot_at,omitempty" bson:"last_snapshot_at,omitempty"`
+	LastError         string                `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	CreatedBy         primitive.ObjectID    `json:"created_by" bson:"created_by"`
+	CreatedAt         time.Time             `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at" bson:"updated_at"`
+}
+
+// CompetitorAlertConfig configures when alerts fire. Zero disables an alert.
+type CompetitorAlertConfig struct {
+	FollowerSpikePct float64 `json:"follower_spike_pct" bson:"follower_spike_pct"` // follower growth % since the previous snapshot
+	ViralMultiplier  float64 `json:"viral_multiplier" bson:"viral_multiplier"`     // post engagement vs the account's average
+}
+
+// CompetitorPost is a recent post captured in a snapshot.
+type CompetitorPost struct {
+	ID            string `json:"id" bson:"id"`
+	Caption       string `json:"caption,omitempty" bson:"caption,omitempty"`
+	MediaType     string `json:"media_type" bson:"media_type"`
+	Permalink     string `json:"permalink,omitempty" bson:"permalink,omitempty"`
+	LikeCount     int64  `json:"like_count" bson:"like_count"`
+	CommentsCount int64  `json:"comments_count" bson:"comments_count"`
+	Timestamp     string `json:"timestamp" bson:"timestamp"`
+}
+
+// CompetitorSnapshot is a daily capture of a competitor's public metrics.
+type CompetitorSnapshot struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID `json:"org_id" bson:"org_id"`
+	CompetitorID   primitive.ObjectID `json:"competitor_id" bson:"competitor_id"`
+	Date           string             `json:"date" bson:"date"` // YYYY-MM-DD (UTC)
+	Followers      int64              `json:"followers" bson:"followers"`
+	Follows        int64              `json:"follows" bson:"follows"`
+	MediaCount     int64              `json:"media_count" bson:"media_count"`
+	AvgLikes       float64            `json:"avg_likes" bson:"avg_likes"`
+	AvgComments    float64            `json:"avg_comments" bson:"avg_comments"`
+	EngagementRate float64            `json:"engagement_rate" bson:"engagement_rate"` // (avg likes + avg comments) / followers * 100
+	RecentPosts    []CompetitorPost   `json:"recent_posts,omitempty" bson:"recent_posts"`
+	CapturedAt     time.Time          `json:"captured_at" bson:"captured_at"`
+}
+
+// CompetitorAlert is a fired competitor alert.
+type CompetitorAlert struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID        primitive.ObjectID `json:"org_id" bson:"org_id"`
+	CompetitorID primitive.ObjectID `json:"competitor_id" bson:"competitor_id"`
+	Username     string             `json:"username" bson:"username"`
+	Type         string             `json:"type" bson:"type"` // "follower_spike", "viral_post"
+	Message      string             `json:"message" bson:"message"`
+	Value        float64            `json:"value" bson:"value"`
+	Threshold    float64            `json:"threshold" bson:"threshold"`
+	MediaID      string             `json:"media_id,omitempty" bson:"media_id,omitempty"`
+	Permalink    string             `json:"permalink,omitempty" bson:"permalink,omitempty"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// CreateCompetitorRequest is the request body for tracking a competitor.
+type CreateCompetitorRequest struct {
+	Username string                `json:"username"`
+	Alerts   CompetitorAlertConfig `json:"alerts"`
+}
+
+// UpdateCompetitorRequest is the request body for updating a competitor's alerts.
+type UpdateCompetitorRequest struct {
+	Alerts *CompetitorAlertConfig `json:"alerts,omitempty"`
+}
+
+// CompetitorSummary is one column of the side-by-side comparison.
+type CompetitorSummary struct {
+	CompetitorID    *primitive.ObjectID `json:"competitor_id,omitempty"` // nil for the org's own account
+	Username        string              `json:"username"`
+	IsOwn           bool                `json:"is_own"`
+	Followers       int64               `json:"followers"`
+	MediaCount      int64               `json:"media_count"`
+	AvgLikes        float64             `json:"avg_likes"`
+	AvgComments     float64             `json:"avg_comments"`
+	EngagementRate  float64             `json:"engagement_rate"`
+	FollowersChange *int64              `json:"followers_change_7d,omitempty"` // nil without a snapshot from 7 days ago
+	CapturedAt      *time.Time          `json:"captured_at,omitempty"`
+}
+
+// CompetitorComparisonResponse compares the org's account with its competitors.
+type CompetitorComparisonResponse struct {
+	Accounts []CompetitorSummary `json:"accounts"` // own account first
+}
+
+// CompetitorTrendResponse is a competitor's snapshot history, oldest first.
+type CompetitorTrendResponse struct {
+	Competitor Competitor           `json:"competitor"`
+	Days       int                  `json:"days"`
+	Snapshots  []CompetitorSnapshot `json:"snapshots"`
+}
+
+// CompetitorAlertListResponse is the paginated response for listing fired alerts.
+type CompetitorAlertListResponse struct {
+	Alerts []CompetitorAlert `json:"alerts"`
+	Total  int64             `json:"total"`
+	Page   int               `json:"page"`
+	Limit  int               `json:"limit"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContentComment is an internal team comment on a scheduled item. Replies point to
+// the thread's root comment through ParentID.
+type ContentComment struct {
+	ID        primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	OrgID     primitive.ObjectID   `json:"org_id" bson:"org_id"`
+	ItemType  string               `json:"item_type" bson:"item_type"` // "instagram_schedule", "facebook_schedule" or "integrated_publish"
+	ItemID    primitive.ObjectID   `json:"item_id" bson:"item_id"`
+	ParentID  *primitive.ObjectID  `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	UserID    primitive.ObjectID   `json:"user_id" bson:"user_id"`
+	Body      string               `json:"body" bson:"body"`
+	Mentions  []primitive.ObjectID `json:"mentions,omitempty" bson:"mentions,omitempty"` // mentioned org members
+	EditedAt  *time.Time           `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	CreatedAt time.Time            `json:"created_at" bson:"created_at"`
+}
+
+// CreateContentCommentRequest is the request body for commenting on an item.
+// Mention members with @ followed by their e-mail or the part before the @.
+type CreateContentCommentRequest struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id,omitempty"` // reply to this comment's thread
+}
+
+// UpdateContentCommentRequest is the request body for editing a comment.
+type UpdateContentCommentRequest struct {
+	Body string `json:"body"`
+}
+
+// ContentCommentResponse is a comment with author info and, for thread roots, its replies.
+type ContentCommentResponse struct {
+	ContentComment `json:",inline"`
+	AuthorName     string                   `json:"author_name"`
+	AuthorAvatar   string                   `json:"author_avatar,omitempty"`
+	Replies        []ContentCommentResponse `json:"replies,omitempty"`
+}
+
+// ContentCommentListResponse lists an item's comment threads, oldest first.
+type ContentCommentListResponse struct {
+	Comments []ContentCommentResponse `json:"comments"`
+	Total    int64                    `json:"total"` // comments including replies
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DMConversation is a persisted Instagram DM thread between the org and one IG user.
+type DMConversation struct {
+	ID                  primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	OrgID               primitive.ObjectID  `json:"org_id" bson:"org_id"`
+	AccountID           string              `json:"account_id" bson:"account_id"` // org's IG account that last received/sent
+	ParticipantIGID     string              `json:"participant_ig_id" bson:"participant_ig_id"`
+	ParticipantUsername string              `json:"participant_username,omitempty" bson:"participant_username,omitempty"`
+	LastMessageText     string              `json:"last_message_text" bson:"last_message_text"`
+	LastMessageAt       time.Time           `json:"last_message_at" bson:"last_message_at"`
+	LastInboundAt       time.Time           `json:"last_inbound_at,omitempty" bson:"last_inbound_at,omitempty"` // opens the 24h messaging window
+	UnreadCount         int                 `json:"unread_count" bson:"unread_count"`
+	AssignedTo          *primitive.ObjectID `json:"assigned_to,omitempty" bson:"assigned_to,omitempty"`
+	CreatedAt           time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// DMMessage is a single inbound or outbound message in a DMConversation.
+type DMMessage struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID  `json:"org_id" bson:"org_id"`
+	ConversationID primitive.ObjectID  `json:"conversation_id" bson:"conversation_id"`
+	MID            string              `json:"mid,omitempty" bson:"mid,omitempty"` // Meta message ID; echoes of sent messages share it
+	Direction      string              `json:"direction" bson:"direction"`         // "inbound" or "outbound"
+	Text           string              `json:"text" bson:"text"`
+	Attachments    []DMAttachment      `json:"attachments,omitempty" bson:"attachments,omitempty"`
+	Source         string              `json:"source" bson:"source"` // "user", "manual", "autoreply", "external" (sent outside the app)
+	RuleName       string              `json:"rule_name,omitempty" bson:"rule_name,omitempty"`
+	SentBy         *primitive.ObjectID `json:"sent_by,omitempty" bson:"sent_by,omitempty"` // member who sent a manual reply
+	Status         string              `json:"status" bson:"status"`                       // "received", "sent", "failed"
+	ErrorMessage   string              `json:"error_message,omitempty" bson:"error_message,omitempty"`
+	CreatedAt      time.Time           `json:"created_at" bson:"created_at"`
+}
+
+// DMAttachment is a media attachment of a DM (image, video, audio, share, story_mention...).
+type DMAttachment struct {
+	Type string `json:"type" bson:"type"`
+	URL  string `json:"url,omitempty" bson:"url,omitempty"`
+}
+
+// SendDMRequest is the request body for a manual DM reply.
+type SendDMRequest struct {
+	Text string `json:"text"`
+}
+
+// AssignDMConversationRequest is the request body for assigning a conversation.
+// An empty user_id removes the assignment.
+type AssignDMConversationRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// DMConversationResponse adds computed fields to a conversation.
+type DMConversationResponse struct {
+	DMConversation
+	WindowOpen   bool       `json:"window_open"`
+	WindowEndsAt *time.Time `json:"window_ends_at,omitempty"`
+}
+
+// DMConversationListResponse is the paginated response for listing conversations.
+type DMConversationListResponse struct {
+	Conversations []DMConversationResponse `json:"conversations"`
+	Total         int64                    `json:"total"`
+	UnreadTotal   int64                    `json:"unread_total"`
+	Page          int                      `json:"page"`
+	Limit         int                      `json:"limit"`
+}
+
+// DMThreadResponse is a conversation with a page of its messages (oldest first).
+type DMThreadResponse struct {
+	Conversation DMConversationResponse `json:"conversation"`
+	Messages     []DMMessage            `json:"messages"`
+	Total        int64                  `json:"total"`
+	Page         int                    `json:"page"`
+	Limit        int                    `json:"limit"`
+}
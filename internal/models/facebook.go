@@ -8,19 +8,37 @@ import (
 
 // FacebookSchedule represents a scheduled Facebook Page post
 type FacebookSchedule struct {
-	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	UserID       primitive.ObjectID `json:"user_id" bson:"user_id"`
-	OrgID        primitive.ObjectID `json:"org_id" bson:"org_id"`
-	Message      string             `json:"message" bson:"message"`
-	MediaType    string             `json:"media_type" bson:"media_type"` // "text", "image", "carousel", "link"
-	ImageIDs     []string           `json:"image_ids" bson:"image_ids"`   // IDs of images in the images collection
-	LinkURL      string             `json:"link_url,omitempty" bson:"link_url,omitempty"`
-	ScheduledAt  time.Time          `json:"scheduled_at" bson:"scheduled_at"`
-	Status       string             `json:"status" bson:"status"` // "scheduled", "publishing", "published", "failed"
-	FBPostID     string             `json:"fb_post_id,omitempty" bson:"fb_post_id,omitempty"`
-	ErrorMessage string             `json:"error_message,omitempty" bson:"error_message,omitempty"`
-	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	OrgID     primitive.ObjectID `json:"org_id" bson:"org_id"`
+	Message   string             `json:"message" bson:"message"`
+	MediaType string             `json:"media_type" bson:"media_type"` // "text", "image", "carousel", "link", "video", "reel"
+	ImageIDs  []string           `json:"image_ids" bson:"image_ids"`   // IDs of images in the images collection
+	LinkURL   string             `json:"link_url,omitempty" bson:"link_url,omitempty"`
+	// Video and Reel posts: Message is the description
+	VideoID      string     `json:"video_id,omitempty" bson:"video_id,omitempty"`         // ID of the uploaded video file
+	ThumbnailID  string     `json:"thumbnail_id,omitempty" bson:"thumbnail_id,omitempty"` // image ID used as the cover (optional)
+	Title        string     `json:"title,omitempty" bson:"title,omitempty"`
+	ScheduledAt  time.Time  `json:"scheduled_at" bson:"scheduled_at"`
+	Status       string     `json:"status" bson:"status"` // "pending_approval", "rejected", "scheduled", "publishing", "processing", "published", "failed"
+	FBPostID     string     `json:"fb_post_id,omitempty" bson:"fb_post_id,omitempty"`
+	FBVideoID    string     `json:"fb_video_id,omitempty" bson:"fb_video_id,omitempty"` // set while Facebook processes an uploaded video
+	PublishedAt  *time.Time `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty" bson:"error_message,omitempty"`
+	// Native scheduling: the post is handed to Facebook as an unpublished post with
+	// scheduled_publish_time; the local job only publishes while NativePostID is empty
+	Native       bool   `json:"native,omitempty" bson:"native,omitempty"`
+	NativePostID string `json:"native_post_id,omitempty" bson:"native_post_id,omitempty"`
+	NativeError  string `json:"native_error,omitempty" bson:"native_error,omitempty"` // last hand-off failure (falls back to the local job)
+	// Series link: OccurrenceAt is the series slot this schedule was created for
+	SeriesID     *primitive.ObjectID `json:"series_id,omitempty" bson:"series_id,omitempty"`
+	SeriesItemID *primitive.ObjectID `json:"series_item_id,omitempty" bson:"series_item_id,omitempty"`
+	OccurrenceAt *time.Time          `json:"occurrence_at,omitempty" bson:"occurrence_at,omitempty"`
+	Detached     bool                `json:"detached,omitempty" bson:"detached,omitempty"` // edited individually
+	// Approval workflow history (status "pending_approval" / "rejected" while under review)
+	Approvals []ApprovalDecision `json:"approvals,omitempty" bson:"approvals,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
 }
 
 // CreateFacebookScheduleRequest is the request body for creating a scheduled post
@@ -29,7 +47,11 @@ type CreateFacebookScheduleRequest struct {
 	MediaType   string   `json:"media_type"`
 	ImageIDs    []string `json:"image_ids"`
 	LinkURL     string   `json:"link_url,omitempty"`
-	ScheduledAt string   `json:"scheduled_at"` // ISO 8601
+	VideoID     string   `json:"video_id,omitempty"`
+	ThumbnailID string   `json:"thumbnail_id,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	ScheduledAt string   `json:"scheduled_at"`     // ISO 8601
+	Native      bool     `json:"native,omitempty"` // hand off to Facebook's native scheduling when supported
 }
 
 // UpdateFacebookScheduleRequest is the request body for updating a scheduled post
@@ -38,13 +60,19 @@ type UpdateFacebookScheduleRequest struct {
 	MediaType   *string  `json:"media_type,omitempty"`
 	ImageIDs    []string `json:"image_ids,omitempty"`
 	LinkURL     *string  `json:"link_url,omitempty"`
+	VideoID     *string  `json:"video_id,omitempty"`
+	ThumbnailID *string  `json:"thumbnail_id,omitempty"`
+	Title       *string  `json:"title,omitempty"`
 	ScheduledAt *string  `json:"scheduled_at,omitempty"` // ISO 8601
+	Native      *bool    `json:"native,omitempty"`
 }
 
 // FacebookScheduleResponse is the response for a single schedule with image URLs
 type FacebookScheduleResponse struct {
 	FacebookSchedule `json:",inline"`
 	ImageURLs        []string `json:"image_urls"`
+	ThumbnailURL     string   `json:"thumbnail_url,omitempty"`
+	CommentCount     int      `json:"comment_count"` // internal team comments
 }
 
 // FacebookScheduleListResponse is the paginated response for listing schedules
@@ -63,6 +91,7 @@ type FacebookConfig struct {
 	PageID          string             `json:"page_id" bson:"page_id"`
 	PageAccessToken string             `json:"-" bson:"page_access_token_enc"` // never sent to client (encrypted)
 	PageName        string             `json:"page_name,omitempty" bson:"page_name,omitempty"`
+	TokenMeta       *TokenMeta         `json:"token_meta,omitempty" bson:"token_meta,omitempty"`
 	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
 }
@@ -75,11 +104,12 @@ type SaveFacebookConfigRequest struct {
 
 // FacebookConfigResponse indicates whether Facebook is configured
 type FacebookConfigResponse struct {
-	Configured bool   `json:"configured"`
-	HasToken   bool   `json:"has_token"`
-	PageID     string `json:"page_id"`
-	PageName   string `json:"page_name,omitempty"`
-	Source     string `json:"source"` // "user" or "env"
+	Configured bool         `json:"configured"`
+	HasToken   bool         `json:"has_token"`
+	PageID     string       `json:"page_id"`
+	PageName   string       `json:"page_name,omitempty"`
+	Source     string       `json:"source"` // "user" or "env"
+	Token      *TokenHealth `json:"token,omitempty"`
 }
 
 // FacebookPage represents a Facebook Page accessible to the user
@@ -89,3 +119,12 @@ type FacebookPage struct {
 	AccessToken string `json:"access_token,omitempty"` // Page access token (only in internal use)
 	Category    string `json:"category,omitempty"`
 }
+
+// FacebookVideoUploadResponse is returned after a video is stored for a
+// Facebook video or Reel post. The file is sent to the page when published.
+type FacebookVideoUploadResponse struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
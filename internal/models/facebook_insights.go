@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FBPageSnapshot is a daily capture of a Facebook Page's counters and insights.
+// Insights (reach, impressions, engaged users, page views) refer to Date; counters
+// (fans, followers) are read when the snapshot is taken.
+type FBPageSnapshot struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID           primitive.ObjectID `json:"org_id" bson:"org_id"`
+	PageID          string             `json:"page_id" bson:"page_id"`
+	PageName        string             `json:"page_name,omitempty" bson:"page_name,omitempty"`
+	Date            string             `json:"date" bson:"date"` // YYYY-MM-DD (UTC)
+	Fans            int64              `json:"fans" bson:"fans"`
+	Followers       int64              `json:"followers" bson:"followers"`
+	Reach           int64              `json:"reach" bson:"reach"`
+	Impressions     int64              `json:"impressions" bson:"impressions"`
+	EngagedUsers    int64              `json:"engaged_users" bson:"engaged_users"`
+	PostEngagements int64              `json:"post_engagements" bson:"post_engagements"`
+	PageViews       int64              `json:"page_views" bson:"page_views"`
+	CapturedAt      time.Time          `json:"captured_at" bson:"captured_at"`
+}
+
+// FBPostInsightSnapshot is a daily capture of the lifetime insights of a post
+// published through a Facebook schedule; Date is the capture day.
+type FBPostInsightSnapshot struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID `json:"org_id" bson:"org_id"`
+	PageID         string             `json:"page_id" bson:"page_id"`
+	ScheduleID     primitive.ObjectID `json:"schedule_id" bson:"schedule_id"`
+	PostID         string             `json:"post_id" bson:"post_id"`
+	Date           string             `json:"date" bson:"date"` // YYYY-MM-DD (UTC)
+	Impressions    int64              `json:"impressions" bson:"impressions"`
+	Reach          int64              `json:"reach" bson:"reach"`
+	Reactions      map[string]int64   `json:"reactions" bson:"reactions"` // "like", "love", "wow", "haha", "sorry", "anger"
+	TotalReactions int64              `json:"total_reactions" bson:"total_reactions"`
+	Shares         int64              `json:"shares" bson:"shares"`
+	Comments       int64              `json:"comments" bson:"comments"`
+	Clicks         int64              `json:"clicks" bson:"clicks"`
+	PublishedAt    time.Time          `json:"published_at" bson:"published_at"`
+	CapturedAt     time.Time          `json:"captured_at" bson:"captured_at"`
+}
+
+// FBPostInsightSummary is the latest insight snapshot of a published post.
+type FBPostInsightSummary struct {
+	FBPostInsightSnapshot `json:",inline" bson:",inline"`
+	Message               string `json:"message"`
+	MediaType             string `json:"media_type"`
+}
+
+// FacebookInsightsReport is the response for Facebook Page analytics over a date range.
+type FacebookInsightsReport struct {
+	PageID          string                 `json:"page_id"`
+	PageName        string                 `json:"page_name,omitempty"`
+	From            string                 `json:"from"` // YYYY-MM-DD, inclusive
+	To              string                 `json:"to"`   // YYYY-MM-DD, inclusive
+	Fans            int64                  `json:"fans"` // latest count in the range
+	FansGained      MetricDelta            `json:"fans_gained"`
+	Reach           MetricDelta            `json:"reach"`
+	Impressions     MetricDelta            `json:"impressions"`
+	EngagedUsers    MetricDelta            `json:"engaged_users"`
+	PostEngagements MetricDelta            `json:"post_engagements"`
+	PageViews       MetricDelta            `json:"page_views"`
+	Series          []FBPageSnapshot       `json:"series"` // range, oldest first
+	Posts           []FBPostInsightSummary `json:"posts"`  // posts published in the range, newest first
+}
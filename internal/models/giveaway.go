@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GiveawayRules are the eligibility rules applied to a post's comments.
+type GiveawayRules struct {
+	UniqueUsers       bool     `json:"unique_users" bson:"unique_users"`                               // one entry per user, otherwise one per comment
+	MinMentions       int      `json:"min_mentions" bson:"min_mentions"`                               // distinct @mentions required in the comment
+	Keyword           string   `json:"keyword,omitempty" bson:"keyword,omitempty"`                     // case-insensitive substring
+	ExcludeUsernames  []string `json:"exclude_usernames,omitempty" bson:"exclude_usernames,omitempty"` // without "@"
+	ExcludeOrgMembers bool     `json:"exclude_org_members" bson:"exclude_org_members"`                 // matched by the members' profile social link
+}
+
+// GiveawayEntry is one eligible comment in a draw.
+type GiveawayEntry struct {
+	CommentID string `json:"comment_id" bson:"comment_id"`
+	Username  string `json:"username" bson:"username"`
+}
+
+// GiveawayWinner is a drawn entry and the outcome of notifying it.
+type GiveawayWinner struct {
+	Position    int    `json:"position" bson:"position"`
+	CommentID   string `json:"comment_id" bson:"comment_id"`
+	Username    string `json:"username" bson:"username"`
+	Text        string `json:"text" bson:"text"`
+	Ticket      string `json:"ticket" bson:"ticket"` // sha256(seed + ":" + comment_id), lowest tickets win
+	Notified    bool   `json:"notified" bson:"notified"`
+	NotifyError string `json:"notify_error,omitempty" bson:"notify_error,omitempty"`
+}
+
+// GiveawayDraw is the stored, auditable result of a winner draw. The seed is
+// generated and committed (its hash published) before any comment is fetched,
+// and only revealed once the winners are drawn.
+type GiveawayDraw struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID         primitive.ObjectID `json:"org_id" bson:"org_id"`
+	AccountID     string             `json:"account_id" bson:"account_id"`
+	MediaID       string             `json:"media_id" bson:"media_id"`
+	Permalink     string             `json:"permalink,omitempty" bson:"permalink,omitempty"`
+	Rules         GiveawayRules      `json:"rules" bson:"rules"`
+	WinnersCount  int                `json:"winners_count" bson:"winners_count"`
+	Status        string             `json:"status" bson:"status"`                 // "committed", "drawing", "drawn" (empty on draws made before commitments)
+	Seed          string             `json:"seed,omitempty" bson:"seed"`           // hidden until drawn
+	SeedHash      string             `json:"seed_hash,omitempty" bson:"seed_hash"` // sha256 of the seed, published at commit time
+	EntriesHash   string             `json:"entries_hash" bson:"entries_hash"`     // sha256 of the sorted eligible comment IDs
+	TotalComments int                `json:"total_comments" bson:"total_comments"`
+	EligibleCount int                `json:"eligible_count" bson:"eligible_count"`
+	Entries       []GiveawayEntry    `json:"entries,omitempty" bson:"entries"`
+	Winners       []GiveawayWinner   `json:"winners" bson:"winners"`
+	Notify        string             `json:"notify,omitempty" bson:"notify,omitempty"` // "", "dm" or "reply"
+	NotifyMessage string             `json:"notify_message,omitempty" bson:"notify_message,omitempty"`
+	CreatedBy     primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"` // commitment time
+	DrawnAt       *time.Time         `json:"drawn_at,omitempty" bson:"drawn_at,omitempty"`
+	// DrawingStartedAt is when the running draw was claimed; a stale one was abandoned
+	DrawingStartedAt *time.Time `json:"-" bson:"drawing_started_at,omitempty"`
+}
+
+// CreateGiveawayDrawRequest is the request body for committing a giveaway draw.
+// The seed is always generated by the server.
+type CreateGiveawayDrawRequest struct {
+	MediaID       string        `json:"media_id"`
+	Winners       int           `json:"winners"`
+	Rules         GiveawayRules `json:"rules"`
+	Notify        string        `json:"notify,omitempty"`         // "", "dm" (private reply) or "reply" (public comment reply)
+	NotifyMessage string        `json:"notify_message,omitempty"` // supports {{username}}
+}
+
+// GiveawayDrawListResponse is the paginated response for listing draws (entries omitted).
+type GiveawayDrawListResponse struct {
+	Draws []GiveawayDraw `json:"draws"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+// GiveawayVerifyResponse reports whether a stored draw is reproducible from its seed and entries.
+type GiveawayVerifyResponse struct {
+	Valid           bool     `json:"valid"`
+	SeedHashOK      bool     `json:"seed_hash_ok"` // the revealed seed matches the commitment
+	EntriesHashOK   bool     `json:"entries_hash_ok"`
+	ExpectedWinners []string `json:"expected_winners"` // comment IDs in draw order
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HashtagSet is a reusable group of hashtags that can be appended to captions.
+type HashtagSet struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID     primitive.ObjectID `json:"org_id" bson:"org_id"`
+	Name      string             `json:"name" bson:"name"`
+	Hashtags  []string           `json:"hashtags" bson:"hashtags"` // without the leading '#'
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// CreateHashtagSetRequest is the request body for creating a hashtag set.
+type CreateHashtagSetRequest struct {
+	Name     string   `json:"name"`
+	Hashtags []string `json:"hashtags"`
+}
+
+// UpdateHashtagSetRequest is the request body for updating a hashtag set.
+type UpdateHashtagSetRequest struct {
+	Name     *string  `json:"name,omitempty"`
+	Hashtags []string `json:"hashtags,omitempty"`
+}
+
+// HashtagStat is the performance of posts that used a hashtag.
+type HashtagStat struct {
+	Hashtag       string  `json:"hashtag"`
+	PostCount     int     `json:"post_count"`
+	AvgReach      float64 `json:"avg_reach"`
+	AvgEngagement float64 `json:"avg_engagement"` // (likes+comments+saves+shares) / reach * 100
+	TotalReach    int64   `json:"total_reach"`
+}
+
+// HashtagPerformanceResponse ranks the hashtags used by the org's published posts.
+type HashtagPerformanceResponse struct {
+	Days       int           `json:"days"`
+	Sort       string        `json:"sort"` // "reach", "engagement"
+	TotalPosts int           `json:"total_posts"`
+	Hashtags   []HashtagStat `json:"hashtags"`
+}
+
+// HashtagMedia is a public post returned by the hashtag search.
+type HashtagMedia struct {
+	ID            string `json:"id"`
+	Caption       string `json:"caption,omitempty"`
+	MediaType     string `json:"media_type"`
+	Permalink     string `json:"permalink,omitempty"`
+	LikeCount     int64  `json:"like_count"`
+	CommentsCount int64  `json:"comments_count"`
+	Timestamp     string `json:"timestamp"`
+}
+
+// HashtagSearchResponse is the top and recent media for a hashtag.
+type HashtagSearchResponse struct {
+	Hashtag     string         `json:"hashtag"`
+	HashtagID   string         `json:"hashtag_id"`
+	TopMedia    []HashtagMedia `json:"top_media"`
+	RecentMedia []HashtagMedia `json:"recent_media"` // last 24 hours
+}
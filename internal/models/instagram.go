@@ -15,14 +15,30 @@ type InstagramSchedule struct {
 	MediaType    string             `json:"media_type" bson:"media_type"` // "image" or "carousel"
 	ImageIDs     []string           `json:"image_ids" bson:"image_ids"`   // IDs of images in the images collection
 	ScheduledAt  time.Time          `json:"scheduled_at" bson:"scheduled_at"`
-	Status       string             `json:"status" bson:"status"` // "scheduled", "publishing", "published", "failed"
+	Status       string             `json:"status" bson:"status"` // "pending_approval", "rejected", "scheduled", "publishing", "published", "failed"
 	IGMediaID    string             `json:"ig_media_id,omitempty" bson:"ig_media_id,omitempty"`
+	PublishedAt  *time.Time         `json:"published_at,omitempty" bson:"published_at,omitempty"`
 	ErrorMessage string             `json:"error_message,omitempty" bson:"error_message,omitempty"`
 	// Facebook crosspost fields
 	PostToFacebook bool   `json:"post_to_facebook" bson:"post_to_facebook"`
 	FBPostID       string `json:"fb_post_id,omitempty" bson:"fb_post_id,omitempty"`
 	FBStatus       string `json:"fb_status,omitempty" bson:"fb_status,omitempty"` // "pending", "published", "failed"
 	FBError        string `json:"fb_error,omitempty" bson:"fb_error,omitempty"`
+	// Threads crosspost fields
+	PostToThreads  bool   `json:"post_to_threads" bson:"post_to_threads"`
+	ThreadsMediaID string `json:"threads_media_id,omitempty" bson:"threads_media_id,omitempty"`
+	ThreadsStatus  string `json:"threads_status,omitempty" bson:"threads_status,omitempty"` // "published", "failed"
+	ThreadsError   string `json:"threads_error,omitempty" bson:"threads_error,omitempty"`
+	// Publishing quota deferral: the post stays "scheduled" but is retried after DeferredUntil
+	DeferredUntil *time.Time `json:"deferred_until,omitempty" bson:"deferred_until,omitempty"`
+	DeferReason   string     `json:"defer_reason,omitempty" bson:"defer_reason,omitempty"`
+	// Series link: OccurrenceAt is the series slot this schedule was created for
+	SeriesID     *primitive.ObjectID `json:"series_id,omitempty" bson:"series_id,omitempty"`
+	SeriesItemID *primitive.ObjectID `json:"series_item_id,omitempty" bson:"series_item_id,omitempty"`
+	OccurrenceAt *time.Time          `json:"occurrence_at,omitempty" bson:"occurrence_at,omitempty"`
+	Detached     bool                `json:"detached,omitempty" bson:"detached,omitempty"` // edited individually
+	// Approval workflow history (status "pending_approval" / "rejected" while under review)
+	Approvals []ApprovalDecision `json:"approvals,omitempty" bson:"approvals,omitempty"`
 	CreatedAt      time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at" bson:"updated_at"`
 }
@@ -34,6 +50,8 @@ type CreateInstagramScheduleRequest struct {
 	ImageIDs       []string `json:"image_ids"`
 	ScheduledAt    string   `json:"scheduled_at"` // ISO 8601
 	PostToFacebook bool     `json:"post_to_facebook"`
+	PostToThreads  bool     `json:"post_to_threads"`
+	HashtagSetIDs  []string `json:"hashtag_set_ids,omitempty"` // hashtag sets appended to the caption
 }
 
 // UpdateInstagramScheduleRequest is the request body for updating a scheduled post
@@ -43,12 +61,15 @@ type UpdateInstagramScheduleRequest struct {
 	ImageIDs       []string `json:"image_ids,omitempty"`
 	ScheduledAt    *string  `json:"scheduled_at,omitempty"` // ISO 8601
 	PostToFacebook *bool    `json:"post_to_facebook,omitempty"`
+	PostToThreads  *bool    `json:"post_to_threads,omitempty"`
 }
 
 // InstagramScheduleResponse is the response for a single schedule with image URLs
 type InstagramScheduleResponse struct {
 	InstagramSchedule `json:",inline"`
 	ImageURLs         []string `json:"image_urls"`
+	CommentCount      int      `json:"comment_count"`      // internal team comments
+	Warnings          []string `json:"warnings,omitempty"` // non-blocking issues, e.g. publishing quota
 }
 
 // InstagramScheduleListResponse is the paginated response for listing schedules
@@ -72,6 +93,7 @@ type InstagramConfig struct {
 	AdAccountID        string             `json:"ad_account_id,omitempty" bson:"ad_account_id,omitempty"`
 	BusinessID         string             `json:"business_id,omitempty" bson:"business_id,omitempty"`
 	IsPrimary          bool               `json:"is_primary" bson:"is_primary"`
+	TokenMeta          *TokenMeta         `json:"token_meta,omitempty" bson:"token_meta,omitempty"`
 	CreatedAt          time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt          time.Time          `json:"updated_at" bson:"updated_at"`
 }
@@ -90,6 +112,46 @@ type InstagramConfigResponse struct {
 	HasToken    bool   `json:"has_token"`
 	AccountID   string `json:"account_id"`
 	Source      string `json:"source"` // "user" or "env"
-	AdAccountID string `json:"ad_account_id,omitempty"`
-	BusinessID  string `json:"business_id,omitempty"`
+	AdAccountID string       `json:"ad_account_id,omitempty"`
+	BusinessID  string       `json:"business_id,omitempty"`
+	Token       *TokenHealth `json:"token,omitempty"` // nil for env credentials
+}
+
+// ImageCrop describes how an uploaded Instagram image was fitted to an aspect ratio.
+type ImageCrop struct {
+	AspectRatio string  `json:"aspect_ratio" bson:"aspect_ratio"`                 // "1:1", "4:5", "1.91:1"
+	Mode        string  `json:"mode" bson:"mode"`                                 // "crop" or "letterbox"
+	FocalX      float64 `json:"focal_x" bson:"focal_x"`                           // 0-1, horizontal center of the crop
+	FocalY      float64 `json:"focal_y" bson:"focal_y"`                           // 0-1, vertical center of the crop
+	Background  string  `json:"background,omitempty" bson:"background,omitempty"` // letterbox color, "#RRGGBB"
+}
+
+// ImageCropRequest is the request body for re-cropping an uploaded Instagram image.
+type ImageCropRequest struct {
+	AspectRatio string   `json:"aspect_ratio"`
+	Mode        string   `json:"mode,omitempty"`
+	FocalX      *float64 `json:"focal_x,omitempty"`
+	FocalY      *float64 `json:"focal_y,omitempty"`
+	Background  string   `json:"background,omitempty"`
+}
+
+// InstagramImageResponse is returned after uploading or re-cropping an Instagram image.
+type InstagramImageResponse struct {
+	ID     string     `json:"id"`
+	URL    string     `json:"url"`
+	Width  int        `json:"width"`
+	Height int        `json:"height"`
+	Crop   *ImageCrop `json:"crop,omitempty"`
+}
+
+// InstagramPublishingLimit is the account's content publishing quota (posts per rolling 24h).
+type InstagramPublishingLimit struct {
+	AccountID     string    `json:"account_id"`
+	QuotaUsage    int       `json:"quota_usage"`
+	QuotaTotal    int       `json:"quota_total"`
+	QuotaDuration int       `json:"quota_duration"` // seconds
+	Remaining     int       `json:"remaining"`
+	Planned24h    int       `json:"planned_24h"` // posts scheduled for the next 24h
+	Source        string    `json:"source"`      // "instagram" (live) or "default" (API unavailable)
+	CheckedAt     time.Time `json:"checked_at"`
 }
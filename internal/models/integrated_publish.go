@@ -20,10 +20,11 @@ type IntegratedPublish struct {
 	// Scheduling
 	ScheduledAt time.Time `json:"scheduled_at" bson:"scheduled_at"`
 	Status      string    `json:"status" bson:"status"`
-	// Statuses: "scheduled", "publishing_ig", "publishing_ads", "completed", "failed"
+	// Statuses: "pending_approval", "rejected", "scheduled", "publishing_ig", "publishing_ads", "completed", "failed"
 
 	// Instagram result
-	IGMediaID string `json:"ig_media_id,omitempty" bson:"ig_media_id,omitempty"`
+	IGMediaID     string     `json:"ig_media_id,omitempty" bson:"ig_media_id,omitempty"`
+	IGPublishedAt *time.Time `json:"ig_published_at,omitempty" bson:"ig_published_at,omitempty"`
 
 	// Meta Ads campaign config — used when creating a NEW campaign
 	Campaign IntegratedCampaignConfig `json:"campaign" bson:"campaign"`
@@ -40,6 +41,9 @@ type IntegratedPublish struct {
 	ErrorMessage string `json:"error_message,omitempty" bson:"error_message,omitempty"`
 	ErrorPhase   string `json:"error_phase,omitempty" bson:"error_phase,omitempty"` // "ig" or "ads"
 
+	// Approval workflow history (status "pending_approval" / "rejected" while under review)
+	Approvals []ApprovalDecision `json:"approvals,omitempty" bson:"approvals,omitempty"`
+
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
@@ -75,6 +79,7 @@ type CreateIntegratedPublishRequest struct {
 type IntegratedPublishResponse struct {
 	IntegratedPublish `json:",inline"`
 	ImageURLs         []string `json:"image_urls"`
+	CommentCount      int      `json:"comment_count"` // internal team comments
 }
 
 type IntegratedPublishListResponse struct {
@@ -6,7 +6,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// InstagramLead represents a user who interacted via Instagram auto-reply.
+// InstagramLead represents a user who interacted via auto-reply. For Facebook
+// interactions SenderIGID holds the Facebook user or Messenger sender ID. It is
+// empty for caption/comment mention leads, which are known only by username.
 type InstagramLead struct {
 	ID               primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	OrgID            primitive.ObjectID `json:"org_id" bson:"org_id"`
@@ -15,7 +17,7 @@ type InstagramLead struct {
 	FirstInteraction time.Time          `json:"first_interaction" bson:"first_interaction"`
 	LastInteraction  time.Time          `json:"last_interaction" bson:"last_interaction"`
 	InteractionCount int                `json:"interaction_count" bson:"interaction_count"`
-	Sources          []string           `json:"sources" bson:"sources"`           // "comment", "dm"
+	Sources          []string           `json:"sources" bson:"sources"`           // "comment", "dm", "mention", "facebook_comment", "facebook_dm"
 	RulesTriggered   []string           `json:"rules_triggered" bson:"rules_triggered"` // rule names
 	Tags             []string           `json:"tags" bson:"tags"`
 	CreatedAt        time.Time          `json:"created_at" bson:"created_at"`
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BrandMention records an @mention of the org's Instagram account: in a caption,
+// in a comment, in a story, or a reply to one of the org's stories.
+type BrandMention struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID `json:"org_id" bson:"org_id"`
+	AccountID      string             `json:"account_id" bson:"account_id"` // mentioned IG account
+	Type           string             `json:"type" bson:"type"`             // "caption", "comment", "story_mention", "story_reply"
+	MediaID        string             `json:"media_id,omitempty" bson:"media_id,omitempty"`
+	CommentID      string             `json:"comment_id,omitempty" bson:"comment_id,omitempty"`
+	MID            string             `json:"mid,omitempty" bson:"mid,omitempty"` // DM message ID (stories)
+	AuthorID       string             `json:"author_id,omitempty" bson:"author_id,omitempty"`
+	AuthorUsername string             `json:"author_username,omitempty" bson:"author_username,omitempty"`
+	Text           string             `json:"text,omitempty" bson:"text,omitempty"`
+	MediaType      string             `json:"media_type,omitempty" bson:"media_type,omitempty"` // IMAGE, VIDEO, CAROUSEL_ALBUM
+	MediaURL       string             `json:"media_url,omitempty" bson:"media_url,omitempty"`   // CDN URL (expires)
+	Permalink      string             `json:"permalink,omitempty" bson:"permalink,omitempty"`
+	StoredImageID  string             `json:"stored_image_id,omitempty" bson:"stored_image_id,omitempty"` // copy kept in images collection
+	LikeCount      int                `json:"like_count,omitempty" bson:"like_count,omitempty"`
+	CommentsCount  int                `json:"comments_count,omitempty" bson:"comments_count,omitempty"`
+	MentionedAt    time.Time          `json:"mentioned_at" bson:"mentioned_at"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// BrandMentionListResponse is the paginated response for listing mentions.
+type BrandMentionListResponse struct {
+	Mentions []BrandMention `json:"mentions"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	Limit    int            `json:"limit"`
+}
@@ -16,6 +16,7 @@ type MetaAdsConfig struct {
 	AdAccountID     string             `json:"ad_account_id" bson:"ad_account_id"`
 	BusinessID      string             `json:"business_id,omitempty" bson:"business_id,omitempty"`
 	AccessTokenEnc  string             `json:"-" bson:"access_token_enc"` // never sent to client
+	TokenMeta       *TokenMeta         `json:"token_meta,omitempty" bson:"token_meta,omitempty"`
 	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt       time.Time          `json:"updated_at" bson:"updated_at"`
 }
@@ -27,9 +28,10 @@ type SaveMetaAdsConfigRequest struct {
 }
 
 type MetaAdsConfigResponse struct {
-	Configured  bool   `json:"configured"`
-	AdAccountID string `json:"ad_account_id"`
-	Source      string `json:"source"` // "user" or "env"
+	Configured  bool         `json:"configured"`
+	AdAccountID string       `json:"ad_account_id"`
+	Source      string       `json:"source"` // "user", "instagram" or "env"
+	Token       *TokenHealth `json:"token,omitempty"`
 }
 
 // ── Campaigns ───────────────────────────────────────────────────────
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// TokenMeta is the expiry and debug_token metadata of a stored Meta access token.
+type TokenMeta struct {
+	Status              string     `json:"status" bson:"status"`                                                     // "valid", "expiring", "expired", "invalid"
+	ExpiresAt           *time.Time `json:"expires_at,omitempty" bson:"expires_at,omitempty"`                         // nil = never expires (e.g. page tokens)
+	DataAccessExpiresAt *time.Time `json:"data_access_expires_at,omitempty" bson:"data_access_expires_at,omitempty"` // when the user must re-grant data access
+	Scopes              []string   `json:"scopes,omitempty" bson:"scopes,omitempty"`
+	Type                string     `json:"type,omitempty" bson:"type,omitempty"` // "USER", "PAGE", "SYSTEM_USER"
+	Error               string     `json:"error,omitempty" bson:"error,omitempty"`
+	CheckedAt           *time.Time `json:"checked_at,omitempty" bson:"checked_at,omitempty"`
+	RefreshedAt         *time.Time `json:"refreshed_at,omitempty" bson:"refreshed_at,omitempty"`
+	NotifiedStatus      string     `json:"-" bson:"notified_status,omitempty"` // last status owners were notified about
+}
+
+// TokenHealth is the token status shown in the config GET responses.
+type TokenHealth struct {
+	Status         string     `json:"status"` // "valid", "expiring", "expired", "invalid", "unknown"
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Scopes         []string   `json:"scopes,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CheckedAt      *time.Time `json:"checked_at,omitempty"`
+	NeedsReconnect bool       `json:"needs_reconnect"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification is an in-app notification for a user within an org.
+type Notification struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID     primitive.ObjectID `json:"org_id" bson:"org_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Type      string             `json:"type" bson:"type"` // e.g. "token_expiring", "token_invalid"
+	Title     string             `json:"title" bson:"title"`
+	Message   string             `json:"message" bson:"message"`
+	Link      string             `json:"link,omitempty" bson:"link,omitempty"` // frontend path
+	ReadAt    *time.Time         `json:"read_at,omitempty" bson:"read_at,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NotificationListResponse is the paginated response for listing notifications.
+type NotificationListResponse struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+	Total         int64          `json:"total"`
+	Page          int            `json:"page"`
+	Limit         int            `json:"limit"`
+}
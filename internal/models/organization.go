@@ -52,9 +52,12 @@ const (
 type OrgSettings struct {
 	DefaultLanguage  string         `json:"default_language,omitempty" bson:"default_language,omitempty"`
 	DefaultCurrency  string         `json:"default_currency,omitempty" bson:"default_currency,omitempty"`
+	Timezone         string         `json:"timezone,omitempty" bson:"timezone,omitempty"` // IANA name, e.g. "America/Sao_Paulo"
 	BrandColorsDark  *BrandColors   `json:"brand_colors_dark,omitempty" bson:"brand_colors_dark,omitempty"`
 	BrandColorsLight *BrandColors   `json:"brand_colors_light,omitempty" bson:"brand_colors_light,omitempty"`
 	SidebarDisplay   SidebarDisplay `json:"sidebar_display,omitempty" bson:"sidebar_display,omitempty"`
+	// RequireApproval sends posts created by members without content:approve to review first
+	RequireApproval bool `json:"require_approval,omitempty" bson:"require_approval,omitempty"`
 }
 
 // OrgMembership links a user to an organization with a role.
@@ -182,9 +185,12 @@ type UpdateMemberPermissionsRequest struct {
 // AllPermissions lists every granular permission available for members.
 var AllPermissions = []string{
 	"instagram:schedule",
+	"threads:schedule",
+	"content:approve",
 	"instagram:autoreply",
 	"instagram:leads",
 	"instagram:config",
+	"inbox:manage",
 	"meta_ads:manage",
 	"meta_ads:budget",
 	"auto_boost:manage",
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MediaInsightSnapshot is a point-in-time capture of an Instagram post's insights,
+// taken at fixed checkpoints after publishing.
+type MediaInsightSnapshot struct {
+	ID                primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	OrgID             primitive.ObjectID  `json:"org_id" bson:"org_id"`
+	AccountID         string              `json:"account_id" bson:"account_id"`
+	MediaID           string              `json:"media_id" bson:"media_id"`
+	Source            string              `json:"source" bson:"source"` // "schedule", "integrated_publish", "account"
+	SourceID          *primitive.ObjectID `json:"source_id,omitempty" bson:"source_id,omitempty"`
+	Checkpoint        string              `json:"checkpoint" bson:"checkpoint"` // "1h", "6h", "24h", "72h", "7d"
+	HoursSincePublish float64             `json:"hours_since_publish" bson:"hours_since_publish"`
+	Reach             int64               `json:"reach" bson:"reach"`
+	Impressions       int64               `json:"impressions" bson:"impressions"`
+	Saves             int64               `json:"saves" bson:"saves"`
+	Shares            int64               `json:"shares" bson:"shares"`
+	Plays             int64               `json:"plays" bson:"plays"`
+	ProfileVisits     int64               `json:"profile_visits" bson:"profile_visits"`
+	Likes             int64               `json:"likes" bson:"likes"`
+	Comments          int64               `json:"comments" bson:"comments"`
+	Hashtags          []string            `json:"hashtags,omitempty" bson:"hashtags,omitempty"` // lowercased, from the published caption
+	PublishedAt       time.Time           `json:"published_at" bson:"published_at"`
+	CapturedAt        time.Time           `json:"captured_at" bson:"captured_at"`
+}
+
+// MediaInsightSeriesResponse is the growth curve of a single published post.
+type MediaInsightSeriesResponse struct {
+	MediaID     string                 `json:"media_id"`
+	PublishedAt *time.Time             `json:"published_at,omitempty"`
+	Snapshots   []MediaInsightSnapshot `json:"snapshots"` // ordered by checkpoint
+	Pending     []string               `json:"pending"`   // checkpoints not captured yet
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ScheduleImportRow is one CSV row of a bulk schedule import, as parsed and validated.
+type ScheduleImportRow struct {
+	Row         int        `json:"row"`     // line number in the CSV (the header is line 1)
+	Channel     string     `json:"channel"` // "instagram", "facebook" or "instagram+facebook"
+	Caption     string     `json:"caption"`
+	MediaType   string     `json:"media_type"`
+	MediaFiles  []string   `json:"media_files"` // file names inside the ZIP
+	LinkURL     string     `json:"link_url,omitempty"`
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	Valid       bool       `json:"valid"`
+	Errors      []string   `json:"errors"`
+	ScheduleID  string     `json:"schedule_id,omitempty"` // set once committed
+}
+
+// ScheduleImportResponse is the result of previewing or committing a bulk import.
+type ScheduleImportResponse struct {
+	Rows        []ScheduleImportRow `json:"rows"`
+	Total       int                 `json:"total"`
+	ValidCount  int                 `json:"valid_count"`
+	ErrorCount  int                 `json:"error_count"`
+	UnusedFiles []string            `json:"unused_files"`              // ZIP entries no row references
+	Remaining   int                 `json:"remaining_scheduled_posts"` // scheduled posts left in the plan before the import, -1 = unlimited
+	Committed   bool                `json:"committed"`
+	Created     int                 `json:"created"`
+	Images      int                 `json:"images"` // images uploaded on commit
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SchedulingPreferences are an org's constraints for suggested posting slots and the queue.
+type SchedulingPreferences struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID `json:"org_id" bson:"org_id"`
+	PreferredDays  []int              `json:"preferred_days" bson:"preferred_days"` // 0=Sunday..6=Saturday; empty = any day
+	BlockedHours   []int              `json:"blocked_hours" bson:"blocked_hours"`   // 0-23 in the org timezone
+	MinGapMinutes  int                `json:"min_gap_minutes" bson:"min_gap_minutes"`
+	MaxPostsPerDay int                `json:"max_posts_per_day" bson:"max_posts_per_day"` // 0 = unlimited
+	UpdatedAt      time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// UpdateSchedulingPreferencesRequest is the request body for updating scheduling preferences.
+type UpdateSchedulingPreferencesRequest struct {
+	PreferredDays  []int `json:"preferred_days"`
+	BlockedHours   []int `json:"blocked_hours"`
+	MinGapMinutes  *int  `json:"min_gap_minutes,omitempty"`
+	MaxPostsPerDay *int  `json:"max_posts_per_day,omitempty"`
+}
+
+// HeatmapCell is the average engagement of posts published on a weekday/hour.
+type HeatmapCell struct {
+	Weekday       int     `json:"weekday"` // 0=Sunday..6=Saturday
+	Hour          int     `json:"hour"`
+	AvgEngagement float64 `json:"avg_engagement"` // (likes+comments+saves+shares) / reach * 100
+	PostCount     int     `json:"post_count"`
+}
+
+// EngagementHeatmap is the 7x24 weekday-by-hour heatmap in the org timezone.
+type EngagementHeatmap struct {
+	Timezone   string        `json:"timezone"`
+	Days       int           `json:"days"`
+	TotalPosts int           `json:"total_posts"`
+	Cells      []HeatmapCell `json:"cells"` // 168 cells, weekday-major
+}
+
+// SlotSuggestion is a suggested posting time.
+type SlotSuggestion struct {
+	At        time.Time `json:"at"`
+	Weekday   int       `json:"weekday"`
+	Hour      int       `json:"hour"`
+	Score     float64   `json:"score"`      // smoothed average engagement of the weekday/hour
+	PostCount int       `json:"post_count"` // history behind the score
+}
+
+// SlotSuggestionsResponse is the response for the best-time suggestions endpoint.
+type SlotSuggestionsResponse struct {
+	Timezone    string           `json:"timezone"`
+	Suggestions []SlotSuggestion `json:"suggestions"`
+}
+
+// QueuedPost is a post waiting in the smart queue to be assigned a slot.
+type QueuedPost struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	OrgID          primitive.ObjectID  `json:"org_id" bson:"org_id"`
+	UserID         primitive.ObjectID  `json:"user_id" bson:"user_id"`
+	Caption        string              `json:"caption" bson:"caption"`
+	MediaType      string              `json:"media_type" bson:"media_type"` // "image" or "carousel"
+	ImageIDs       []string            `json:"image_ids" bson:"image_ids"`
+	PostToFacebook bool                `json:"post_to_facebook" bson:"post_to_facebook"`
+	PostToThreads  bool                `json:"post_to_threads" bson:"post_to_threads"`
+	Priority       int                 `json:"priority" bson:"priority"` // higher is assigned first
+	Status         string              `json:"status" bson:"status"`     // "queued", "scheduled"
+	ScheduleID     *primitive.ObjectID `json:"schedule_id,omitempty" bson:"schedule_id,omitempty"`
+	SlotAt         *time.Time          `json:"slot_at,omitempty" bson:"slot_at,omitempty"`
+	CreatedAt      time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// CreateQueuedPostRequest is the request body for adding a post to the queue.
+type CreateQueuedPostRequest struct {
+	Caption        string   `json:"caption"`
+	MediaType      string   `json:"media_type"`
+	ImageIDs       []string `json:"image_ids"`
+	PostToFacebook bool     `json:"post_to_facebook"`
+	PostToThreads  bool     `json:"post_to_threads"`
+	Priority       int      `json:"priority"`
+}
+
+// UpdateQueuedPostRequest is the request body for editing a queued post.
+type UpdateQueuedPostRequest struct {
+	Caption        *string  `json:"caption,omitempty"`
+	MediaType      *string  `json:"media_type,omitempty"`
+	ImageIDs       []string `json:"image_ids,omitempty"`
+	PostToFacebook *bool    `json:"post_to_facebook,omitempty"`
+	PostToThreads  *bool    `json:"post_to_threads,omitempty"`
+	Priority       *int     `json:"priority,omitempty"`
+}
+
+// QueuedPostListResponse is the paginated response for listing the queue.
+type QueuedPostListResponse struct {
+	Posts []QueuedPost `json:"posts"`
+	Total int64        `json:"total"`
+	Page  int          `json:"page"`
+	Limit int          `json:"limit"`
+}
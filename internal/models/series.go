@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PostSeries is a recurring post or an evergreen queue. The series job materializes it
+// into concrete InstagramSchedule or FacebookSchedule entries linked by series_id.
+type PostSeries struct {
+	ID      primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OrgID   primitive.ObjectID `json:"org_id" bson:"org_id"`
+	UserID  primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name    string             `json:"name" bson:"name"`
+	Kind    string             `json:"kind" bson:"kind"`       // "recurring" or "evergreen"
+	Channel string             `json:"channel" bson:"channel"` // "instagram" or "facebook"
+	// Slots, in the org timezone
+	Weekdays []int      `json:"weekdays" bson:"weekdays"` // 0=Sunday..6=Saturday
+	Times    []string   `json:"times" bson:"times"`       // "HH:MM"
+	StartAt  *time.Time `json:"start_at,omitempty" bson:"start_at,omitempty"`
+	EndAt    *time.Time `json:"end_at,omitempty" bson:"end_at,omitempty"`
+	// Content: a single post for recurring series, a pool cycled through for evergreen ones
+	Content      *SeriesContent `json:"content,omitempty" bson:"content,omitempty"`
+	Items        []SeriesItem   `json:"items,omitempty" bson:"items,omitempty"`
+	MinReuseDays int            `json:"min_reuse_days" bson:"min_reuse_days"` // evergreen: days before an item is reused
+	DaysAhead    int            `json:"days_ahead" bson:"days_ahead"`         // how far ahead schedules are materialized
+	// SkippedAt lists occurrences deleted individually, so they are not materialized again
+	SkippedAt         []time.Time `json:"skipped_at,omitempty" bson:"skipped_at,omitempty"`
+	Active            bool        `json:"active" bson:"active"`
+	MaterializedUntil *time.Time  `json:"materialized_until,omitempty" bson:"materialized_until,omitempty"`
+	CreatedAt         time.Time   `json:"created_at" bson:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at" bson:"updated_at"`
+}
+
+// SeriesContent is the post published at each occurrence. Caption is the Facebook message
+// on facebook series; LinkURL is Facebook only and PostToFacebook/PostToThreads Instagram only.
+type SeriesContent struct {
+	Caption        string   `json:"caption" bson:"caption"`
+	MediaType      string   `json:"media_type" bson:"media_type"`
+	ImageIDs       []string `json:"image_ids" bson:"image_ids"`
+	LinkURL        string   `json:"link_url,omitempty" bson:"link_url,omitempty"`
+	PostToFacebook bool     `json:"post_to_facebook,omitempty" bson:"post_to_facebook,omitempty"`
+	PostToThreads  bool     `json:"post_to_threads,omitempty" bson:"post_to_threads,omitempty"`
+}
+
+// SeriesItem is one post of an evergreen pool.
+type SeriesItem struct {
+	ID            primitive.ObjectID `json:"id" bson:"id"`
+	SeriesContent `json:",inline" bson:",inline"`
+}
+
+// CreatePostSeriesRequest is the request body for creating a series.
+type CreatePostSeriesRequest struct {
+	Name         string          `json:"name"`
+	Kind         string          `json:"kind"`
+	Channel      string          `json:"channel"`
+	Weekdays     []int           `json:"weekdays"`
+	Times        []string        `json:"times"`
+	StartAt      string          `json:"start_at,omitempty"` // ISO 8601
+	EndAt        string          `json:"end_at,omitempty"`   // ISO 8601
+	Content      *SeriesContent  `json:"content,omitempty"`
+	Items        []SeriesContent `json:"items,omitempty"`
+	MinReuseDays int             `json:"min_reuse_days"`
+	DaysAhead    int             `json:"days_ahead"`
+}
+
+// UpdatePostSeriesRequest is the request body for editing a whole series. Future
+// occurrences that were not edited individually are regenerated.
+type UpdatePostSeriesRequest struct {
+	Name         *string        `json:"name,omitempty"`
+	Weekdays     []int          `json:"weekdays,omitempty"`
+	Times        []string       `json:"times,omitempty"`
+	StartAt      *string        `json:"start_at,omitempty"`
+	EndAt        *string        `json:"end_at,omitempty"` // "" clears the end date
+	Content      *SeriesContent `json:"content,omitempty"`
+	Items        []SeriesItem   `json:"items,omitempty"` // items keep their id; new ones omit it
+	MinReuseDays *int           `json:"min_reuse_days,omitempty"`
+	DaysAhead    *int           `json:"days_ahead,omitempty"`
+	Active       *bool          `json:"active,omitempty"`
+}
+
+// PostSeriesListResponse is the paginated response for listing series.
+type PostSeriesListResponse struct {
+	Series []PostSeries `json:"series"`
+	Total  int64        `json:"total"`
+	Page   int          `json:"page"`
+	Limit  int          `json:"limit"`
+}
+
+// SeriesOccurrence is a materialized schedule of a series.
+type SeriesOccurrence struct {
+	ScheduleID   primitive.ObjectID  `json:"schedule_id"`
+	OccurrenceAt time.Time           `json:"occurrence_at"`
+	ScheduledAt  time.Time           `json:"scheduled_at"`
+	Status       string              `json:"status"`
+	Caption      string              `json:"caption"`
+	ItemID       *primitive.ObjectID `json:"item_id,omitempty"`
+	Detached     bool                `json:"detached"` // edited individually; series edits no longer apply
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ThreadsSchedule represents a scheduled Threads post
+type ThreadsSchedule struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID         primitive.ObjectID `json:"user_id" bson:"user_id"`
+	OrgID          primitive.ObjectID `json:"org_id" bson:"org_id"`
+	Caption        string             `json:"caption" bson:"caption"`       // post text, up to 500 characters
+	MediaType      string             `json:"media_type" bson:"media_type"` // "text", "image", "carousel"
+	ImageIDs       []string           `json:"image_ids" bson:"image_ids"`   // IDs of images in the images collection
+	ScheduledAt    time.Time          `json:"scheduled_at" bson:"scheduled_at"`
+	Status         string             `json:"status" bson:"status"` // "pending_approval", "rejected", "scheduled", "publishing", "published", "failed"
+	ThreadsMediaID string             `json:"threads_media_id,omitempty" bson:"threads_media_id,omitempty"`
+	PublishedAt    *time.Time         `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	ErrorMessage   string             `json:"error_message,omitempty" bson:"error_message,omitempty"`
+	// Approval workflow history (status "pending_approval" / "rejected" while under review)
+	Approvals []ApprovalDecision `json:"approvals,omitempty" bson:"approvals,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// CreateThreadsScheduleRequest is the request body for creating a scheduled Threads post
+type CreateThreadsScheduleRequest struct {
+	Caption     string   `json:"caption"`
+	MediaType   string   `json:"media_type"`
+	ImageIDs    []string `json:"image_ids"`
+	ScheduledAt string   `json:"scheduled_at"` // ISO 8601
+}
+
+// UpdateThreadsScheduleRequest is the request body for updating a scheduled Threads post
+type UpdateThreadsScheduleRequest struct {
+	Caption     *string  `json:"caption,omitempty"`
+	MediaType   *string  `json:"media_type,omitempty"`
+	ImageIDs    []string `json:"image_ids,omitempty"`
+	ScheduledAt *string  `json:"scheduled_at,omitempty"` // ISO 8601
+}
+
+// ThreadsScheduleResponse is the response for a single schedule with image URLs
+type ThreadsScheduleResponse struct {
+	ThreadsSchedule `json:",inline"`
+	ImageURLs       []string `json:"image_urls"`
+	CommentCount    int      `json:"comment_count"` // internal team comments
+}
+
+// ThreadsScheduleListResponse is the paginated response for listing schedules
+type ThreadsScheduleListResponse struct {
+	Schedules []ThreadsScheduleResponse `json:"schedules"`
+	Total     int64                     `json:"total"`
+	Page      int                       `json:"page"`
+	Limit     int                       `json:"limit"`
+}
+
+// ThreadsPostMetrics is the latest insights of a published Threads post.
+type ThreadsPostMetrics struct {
+	MediaID   string           `json:"media_id"`
+	Metrics   map[string]int64 `json:"metrics"` // "views", "likes", "replies", "reposts", "quotes", "shares"
+	FetchedAt time.Time        `json:"fetched_at"`
+}
+
+// ThreadsConfig stores the per-org Threads profile and its Threads user token,
+// obtained through the Threads OAuth flow or pasted manually.
+type ThreadsConfig struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID         primitive.ObjectID `json:"user_id" bson:"user_id"`
+	OrgID          primitive.ObjectID `json:"org_id" bson:"org_id"`
+	ThreadsUserID  string             `json:"threads_user_id" bson:"threads_user_id"`
+	Username       string             `json:"username,omitempty" bson:"username,omitempty"`
+	TokenSource    string             `json:"token_source" bson:"token_source"` // "oauth" or "manual"
+	AccessTokenEnc string             `json:"-" bson:"access_token_enc"`        // never sent to client
+	TokenMeta      *TokenMeta         `json:"token_meta,omitempty" bson:"token_meta,omitempty"`
+	CreatedAt      time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// SaveThreadsConfigRequest is the request body for connecting a Threads profile
+// with a manually issued Threads user token.
+type SaveThreadsConfigRequest struct {
+	AccessToken string `json:"access_token"`
+}
+
+// ThreadsConfigResponse indicates whether Threads is configured
+type ThreadsConfigResponse struct {
+	Configured    bool         `json:"configured"`
+	ThreadsUserID string       `json:"threads_user_id,omitempty"`
+	Username      string       `json:"username,omitempty"`
+	TokenSource   string       `json:"token_source,omitempty"`
+	Token         *TokenHealth `json:"token,omitempty"`
+}
+
+// ThreadsOAuthCallbackRequest is the request body for finishing the Threads OAuth flow
+type ThreadsOAuthCallbackRequest struct {
+	Code string `json:"code"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// ValidationIssue is a single failed rule, tied to the request field it concerns.
+type ValidationIssue struct {
+	Field   string `json:"field"` // e.g. "caption", "media_type", "image_ids", "image_ids[2]"
+	Code    string `json:"code"`  // e.g. "too_long", "too_many_hashtags", "aspect_ratio"
+	Message string `json:"message"`
+}
+
+// PostValidationResult is the outcome of validating a post against Instagram limits.
+type PostValidationResult struct {
+	Valid         bool              `json:"valid"`
+	Errors        []ValidationIssue `json:"errors"`
+	CaptionLength int               `json:"caption_length"` // in characters
+	HashtagCount  int               `json:"hashtag_count"`
+	MentionCount  int               `json:"mention_count"`
+	ItemCount     int               `json:"item_count"`
+}
+
+// ValidatePostRequest is the request body for the standalone validation endpoint.
+type ValidatePostRequest struct {
+	Caption       string   `json:"caption"`
+	MediaType     string   `json:"media_type"`
+	ImageIDs      []string `json:"image_ids"`
+	HashtagSetIDs []string `json:"hashtag_set_ids,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IGWebhookEvent is a verified Instagram webhook payload persisted before processing,
+// so failures can be retried and events replayed.
+type IGWebhookEvent struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Object      string             `json:"object" bson:"object"`
+	Status      string             `json:"status" bson:"status"` // "pending", "processing", "processed", "failed"
+	Attempts    int                `json:"attempts" bson:"attempts"`
+	LastError   string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextRetryAt *time.Time         `json:"next_retry_at,omitempty" bson:"next_retry_at,omitempty"`
+	RawPayload  json.RawMessage    `json:"raw_payload" bson:"raw_payload"`
+	ReceivedAt  time.Time          `json:"received_at" bson:"received_at"`
+	ProcessedAt *time.Time         `json:"processed_at,omitempty" bson:"processed_at,omitempty"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// ReplayIGWebhookEventsRequest selects events to replay: one event_id, or a from/to range (RFC3339).
+type ReplayIGWebhookEventsRequest struct {
+	EventID string `json:"event_id,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Status  string `json:"status,omitempty"` // optional filter for range replays
+	Force   bool   `json:"force"`            // reprocess items even if already handled
+}
@@ -55,9 +55,11 @@ func New() http.Handler {
 	// Newsletter (public)
 	mux.HandleFunc("POST /api/v1/newsletter/subscribe", handlers.SubscribeNewsletter)
 
-	// Instagram webhooks (public — called by Meta)
+	// Instagram and Facebook Page webhooks (public — called by Meta)
 	mux.HandleFunc("GET /api/v1/webhooks/instagram", handlers.WebhookVerify)
 	mux.HandleFunc("POST /api/v1/webhooks/instagram", handlers.WebhookEvent)
+	mux.HandleFunc("GET /api/v1/webhooks/facebook", handlers.WebhookVerify)
+	mux.HandleFunc("POST /api/v1/webhooks/facebook", handlers.WebhookEvent)
 
 	// Asaas webhooks (public — called by Asaas, validated by token)
 	mux.HandleFunc("POST /api/v1/webhooks/asaas", handlers.AsaasWebhook)
@@ -65,6 +67,9 @@ func New() http.Handler {
 	// Public invitation acceptance (via email link)
 	mux.HandleFunc("POST /api/v1/invitations/accept-token/{token}", handlers.AcceptInvitationByToken)
 
+	// iCalendar feed (public — authenticated by the secret token in the URL)
+	mux.HandleFunc("GET /api/v1/calendar/feeds/{token}", handlers.ServeCalendarFeed)
+
 	// Engagement routes (public)
 	mux.HandleFunc("GET /api/v1/blog/posts/{slug}/comments", handlers.ListComments)
 
@@ -195,10 +200,14 @@ func New() http.Handler {
 	mux.Handle("GET /api/v1/admin/instagram/feed", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetInstagramFeed)))
 	mux.Handle("GET /api/v1/admin/instagram/schedules", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListInstagramSchedules)))
 	mux.Handle("POST /api/v1/admin/instagram/schedules", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.CreateInstagramSchedule)))
+	mux.Handle("GET /api/v1/admin/instagram/publishing-limit", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetInstagramPublishingLimit)))
+	mux.Handle("POST /api/v1/admin/instagram/validate", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ValidateInstagramPost)))
 	mux.Handle("GET /api/v1/admin/instagram/schedules/{id}", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetInstagramSchedule)))
+	mux.Handle("GET /api/v1/admin/instagram/schedules/{id}/insights", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetInstagramScheduleInsights)))
 	mux.Handle("PUT /api/v1/admin/instagram/schedules/{id}", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.UpdateInstagramSchedule)))
 	mux.Handle("DELETE /api/v1/admin/instagram/schedules/{id}", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.DeleteInstagramSchedule)))
 	mux.Handle("POST /api/v1/admin/instagram/upload", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.UploadInstagramImage)))
+	mux.Handle("PUT /api/v1/admin/instagram/images/{id}/crop", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.UpdateInstagramImageCrop)))
 
 	// Instagram auto-reply routes (org-scoped, requires starter+)
 	mux.Handle("GET /api/v1/admin/instagram/autoreply/rules", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListAutoReplyRules)))
@@ -220,6 +229,94 @@ func New() http.Handler {
 	// Instagram analytics routes (org-scoped)
 	mux.Handle("GET /api/v1/admin/instagram/analytics/autoreply", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetAutoReplyAnalytics)))
 	mux.Handle("GET /api/v1/admin/instagram/analytics/engagement", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetEngagementReport)))
+	mux.Handle("GET /api/v1/admin/instagram/analytics/account", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetAccountGrowthReport)))
+
+	// Comment moderation inbox — Instagram + Facebook (org-scoped, requires starter+)
+	mux.Handle("GET /api/v1/admin/inbox/comments", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListInboxComments)))
+	mux.Handle("GET /api/v1/admin/inbox/posts", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListInboxPosts)))
+	mux.Handle("POST /api/v1/admin/inbox/comments/{id}/reply", orgPermPlan("starter", "inbox:manage")(http.HandlerFunc(handlers.ReplyInboxComment)))
+	mux.Handle("POST /api/v1/admin/inbox/comments/{id}/hide", orgPermPlan("starter", "inbox:manage")(http.HandlerFunc(handlers.HideInboxComment)))
+	mux.Handle("PATCH /api/v1/admin/inbox/comments/{id}/status", orgPermPlan("starter", "inbox:manage")(http.HandlerFunc(handlers.UpdateInboxCommentStatus)))
+	mux.Handle("PUT /api/v1/admin/inbox/comments/{id}/assign", orgPermPlan("starter", "inbox:manage")(http.HandlerFunc(handlers.AssignInboxComment)))
+	mux.Handle("DELETE /api/v1/admin/inbox/comments/{id}", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.DeleteInboxComment)))
+
+	// Instagram DM inbox (org-scoped, requires starter+)
+	mux.Handle("GET /api/v1/admin/instagram/conversations", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListDMConversations)))
+	mux.Handle("GET /api/v1/admin/instagram/conversations/{id}", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetDMThread)))
+	mux.Handle("POST /api/v1/admin/instagram/conversations/{id}/messages", orgPermPlan("starter", "inbox:manage")(http.HandlerFunc(handlers.SendDMReply)))
+	mux.Handle("PUT /api/v1/admin/instagram/conversations/{id}/assign", orgPermPlan("starter", "inbox:manage")(http.HandlerFunc(handlers.AssignDMConversation)))
+
+	// Instagram brand mentions (org-scoped, requires starter+)
+	mux.Handle("GET /api/v1/admin/instagram/mentions", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListBrandMentions)))
+
+	// Instagram scheduling: heatmap, suggestions and smart queue (org-scoped)
+	mux.Handle("GET /api/v1/admin/instagram/scheduling/heatmap", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetEngagementHeatmap)))
+	mux.Handle("GET /api/v1/admin/instagram/scheduling/suggestions", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetSlotSuggestions)))
+	mux.Handle("GET /api/v1/admin/instagram/scheduling/preferences", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetSchedulingPreferences)))
+	mux.Handle("PUT /api/v1/admin/instagram/scheduling/preferences", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.UpdateSchedulingPreferences)))
+	mux.Handle("GET /api/v1/admin/instagram/queue", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListQueuedPosts)))
+	mux.Handle("POST /api/v1/admin/instagram/queue", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.CreateQueuedPost)))
+	mux.Handle("PUT /api/v1/admin/instagram/queue/{id}", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.UpdateQueuedPost)))
+	mux.Handle("DELETE /api/v1/admin/instagram/queue/{id}", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.DeleteQueuedPost)))
+
+	// Instagram competitor monitoring (org-scoped)
+	mux.Handle("GET /api/v1/admin/instagram/competitors", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListCompetitors)))
+	mux.Handle("POST /api/v1/admin/instagram/competitors", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.CreateCompetitor)))
+	mux.Handle("GET /api/v1/admin/instagram/competitors/compare", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.CompareCompetitors)))
+	mux.Handle("GET /api/v1/admin/instagram/competitors/alerts", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListCompetitorAlerts)))
+	mux.Handle("GET /api/v1/admin/instagram/competitors/{id}/trends", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetCompetitorTrends)))
+	mux.Handle("PUT /api/v1/admin/instagram/competitors/{id}", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.UpdateCompetitor)))
+	mux.Handle("DELETE /api/v1/admin/instagram/competitors/{id}", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.DeleteCompetitor)))
+
+	// Instagram hashtags: performance, search and reusable sets (org-scoped)
+	mux.Handle("GET /api/v1/admin/instagram/hashtags/performance", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetHashtagPerformance)))
+	mux.Handle("GET /api/v1/admin/instagram/hashtags/search", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.SearchHashtag)))
+	mux.Handle("GET /api/v1/admin/instagram/hashtag-sets", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListHashtagSets)))
+	mux.Handle("POST /api/v1/admin/instagram/hashtag-sets", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.CreateHashtagSet)))
+	mux.Handle("PUT /api/v1/admin/instagram/hashtag-sets/{id}", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.UpdateHashtagSet)))
+	mux.Handle("DELETE /api/v1/admin/instagram/hashtag-sets/{id}", orgPermPlan("starter", "instagram:schedule")(http.HandlerFunc(handlers.DeleteHashtagSet)))
+
+	// Recurring series and evergreen queues (org-scoped; write access is checked per channel)
+	mux.Handle("GET /api/v1/admin/series", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListPostSeries)))
+	mux.Handle("POST /api/v1/admin/series", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.CreatePostSeries)))
+	mux.Handle("GET /api/v1/admin/series/{id}", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetPostSeries)))
+	mux.Handle("PUT /api/v1/admin/series/{id}", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.UpdatePostSeries)))
+	mux.Handle("DELETE /api/v1/admin/series/{id}", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.DeletePostSeries)))
+	mux.Handle("GET /api/v1/admin/series/{id}/occurrences", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListSeriesOccurrences)))
+
+	// Bulk schedule import (org-scoped; per-channel schedule permission is checked per row)
+	mux.Handle("POST /api/v1/admin/schedules/import/preview", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.PreviewScheduleImport)))
+	mux.Handle("POST /api/v1/admin/schedules/import", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.CommitScheduleImport)))
+
+	// Unified content calendar (org-scoped; reschedule permission is checked per item type)
+	mux.Handle("GET /api/v1/admin/calendar", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetCalendar)))
+	mux.Handle("PATCH /api/v1/admin/calendar/{type}/{id}", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.RescheduleCalendarItem)))
+	mux.Handle("GET /api/v1/admin/calendar/feed", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetCalendarFeed)))
+	mux.Handle("POST /api/v1/admin/calendar/feed", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.RotateCalendarFeed)))
+	mux.Handle("DELETE /api/v1/admin/calendar/feed", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.RevokeCalendarFeed)))
+
+	// Internal comments on scheduled content (org-scoped; viewers can read)
+	mux.Handle("GET /api/v1/admin/content/{type}/{id}/comments", orgRoute("owner", "admin", "member", "viewer")(http.HandlerFunc(handlers.ListContentComments)))
+	mux.Handle("POST /api/v1/admin/content/{type}/{id}/comments", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.CreateContentComment)))
+	mux.Handle("PATCH /api/v1/admin/content/{type}/{id}/comments/{commentId}", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.UpdateContentComment)))
+	mux.Handle("DELETE /api/v1/admin/content/{type}/{id}/comments/{commentId}", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.DeleteContentComment)))
+
+	// Content approvals (org-scoped; reviewers hold content:approve)
+	mux.Handle("GET /api/v1/admin/approvals", orgPerm("content:approve")(http.HandlerFunc(handlers.ListPendingApprovals)))
+	mux.Handle("POST /api/v1/admin/approvals/{type}/{id}/approve", orgPerm("content:approve")(http.HandlerFunc(handlers.ApproveContent)))
+	mux.Handle("POST /api/v1/admin/approvals/{type}/{id}/reject", orgPerm("content:approve")(http.HandlerFunc(handlers.RejectContent)))
+
+	// Notifications (org-scoped, per user)
+	mux.Handle("GET /api/v1/admin/notifications", orgRoute("owner", "admin", "member", "viewer")(http.HandlerFunc(handlers.ListNotifications)))
+	mux.Handle("POST /api/v1/admin/notifications/read-all", orgRoute("owner", "admin", "member", "viewer")(http.HandlerFunc(handlers.MarkAllNotificationsRead)))
+	mux.Handle("POST /api/v1/admin/notifications/{id}/read", orgRoute("owner", "admin", "member", "viewer")(http.HandlerFunc(handlers.MarkNotificationRead)))
+
+	// Instagram giveaways (org-scoped)
+	mux.Handle("GET /api/v1/admin/instagram/giveaways", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.ListGiveawayDraws)))
+	mux.Handle("POST /api/v1/admin/instagram/giveaways", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.CreateGiveawayDraw)))
+	mux.Handle("GET /api/v1/admin/instagram/giveaways/{id}", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.GetGiveawayDraw)))
+	mux.Handle("POST /api/v1/admin/instagram/giveaways/{id}/draw", orgRoutePlan("starter", "owner", "admin")(http.HandlerFunc(handlers.RunGiveawayDraw)))
+	mux.Handle("GET /api/v1/admin/instagram/giveaways/{id}/verify", orgRoutePlan("starter", "owner", "admin", "member")(http.HandlerFunc(handlers.VerifyGiveawayDraw)))
 
 	// Meta Ads config (org-scoped)
 	mux.Handle("GET /api/v1/admin/meta-ads/config", orgRoute("owner", "admin")(http.HandlerFunc(handlers.GetMetaAdsConfig)))
@@ -291,6 +388,7 @@ func New() http.Handler {
 	mux.Handle("GET /api/v1/admin/integrated-publish", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.ListIntegratedPublishes)))
 	mux.Handle("POST /api/v1/admin/integrated-publish", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.CreateIntegratedPublish)))
 	mux.Handle("GET /api/v1/admin/integrated-publish/{id}", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetIntegratedPublish)))
+	mux.Handle("GET /api/v1/admin/integrated-publish/{id}/insights", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetIntegratedPublishInsights)))
 	mux.Handle("PUT /api/v1/admin/integrated-publish/{id}", orgRoute("owner", "admin")(http.HandlerFunc(handlers.UpdateIntegratedPublish)))
 	mux.Handle("DELETE /api/v1/admin/integrated-publish/{id}", orgRoute("owner", "admin")(http.HandlerFunc(handlers.DeleteIntegratedPublish)))
 
@@ -301,12 +399,28 @@ func New() http.Handler {
 	mux.Handle("GET /api/v1/admin/facebook/test", orgRoute("owner", "admin")(http.HandlerFunc(handlers.TestFacebookConnection)))
 	mux.Handle("GET /api/v1/admin/facebook/pages", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.ListFacebookPages)))
 	mux.Handle("GET /api/v1/admin/facebook/feed", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetFacebookFeed)))
+	mux.Handle("GET /api/v1/admin/facebook/analytics", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetFacebookInsightsReport)))
 	mux.Handle("GET /api/v1/admin/facebook/schedules", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.ListFacebookSchedules)))
 	mux.Handle("POST /api/v1/admin/facebook/schedules", orgPerm("facebook:schedule")(http.HandlerFunc(handlers.CreateFacebookSchedule)))
 	mux.Handle("GET /api/v1/admin/facebook/schedules/{id}", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetFacebookSchedule)))
 	mux.Handle("PUT /api/v1/admin/facebook/schedules/{id}", orgPerm("facebook:schedule")(http.HandlerFunc(handlers.UpdateFacebookSchedule)))
 	mux.Handle("DELETE /api/v1/admin/facebook/schedules/{id}", orgRoute("owner", "admin")(http.HandlerFunc(handlers.DeleteFacebookSchedule)))
 	mux.Handle("POST /api/v1/admin/facebook/upload", orgPerm("facebook:schedule")(http.HandlerFunc(handlers.UploadFacebookImage)))
+	mux.Handle("POST /api/v1/admin/facebook/upload/video", orgPerm("facebook:schedule")(http.HandlerFunc(handlers.UploadFacebookVideo)))
+
+	// Threads scheduling routes (org-scoped)
+	mux.Handle("GET /api/v1/admin/threads/config", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetThreadsConfig)))
+	mux.Handle("PUT /api/v1/admin/threads/config", orgRoute("owner", "admin")(http.HandlerFunc(handlers.SaveThreadsConfig)))
+	mux.Handle("DELETE /api/v1/admin/threads/config", orgRoute("owner", "admin")(http.HandlerFunc(handlers.DeleteThreadsConfig)))
+	mux.Handle("GET /api/v1/admin/threads/oauth/url", orgRoute("owner", "admin")(http.HandlerFunc(handlers.ThreadsOAuthURL)))
+	mux.Handle("POST /api/v1/admin/threads/oauth/callback", orgRoute("owner", "admin")(http.HandlerFunc(handlers.ThreadsOAuthCallback)))
+	mux.Handle("GET /api/v1/admin/threads/schedules", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.ListThreadsSchedules)))
+	mux.Handle("POST /api/v1/admin/threads/schedules", orgPerm("threads:schedule")(http.HandlerFunc(handlers.CreateThreadsSchedule)))
+	mux.Handle("GET /api/v1/admin/threads/schedules/{id}", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetThreadsSchedule)))
+	mux.Handle("PUT /api/v1/admin/threads/schedules/{id}", orgPerm("threads:schedule")(http.HandlerFunc(handlers.UpdateThreadsSchedule)))
+	mux.Handle("DELETE /api/v1/admin/threads/schedules/{id}", orgRoute("owner", "admin")(http.HandlerFunc(handlers.DeleteThreadsSchedule)))
+	mux.Handle("GET /api/v1/admin/threads/schedules/{id}/insights", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetThreadsScheduleInsights)))
+	mux.Handle("POST /api/v1/admin/threads/upload", orgPerm("threads:schedule")(http.HandlerFunc(handlers.UploadFacebookImage)))
 
 	// AI (Claude) routes (org-scoped)
 	mux.Handle("GET /api/v1/admin/ai/config", orgRoute("owner", "admin", "member")(http.HandlerFunc(handlers.GetAIConfig)))
@@ -355,6 +469,8 @@ func New() http.Handler {
 	mux.Handle("GET /api/v1/platform/overdue-subscriptions", middleware.Auth(middleware.RequireRole("superadmin", "superuser")(http.HandlerFunc(handlers.PlatformListOverdue))))
 	mux.Handle("PUT /api/v1/platform/orgs/{id}/grace-period", middleware.Auth(middleware.RequireRole("superadmin", "superuser")(http.HandlerFunc(handlers.PlatformExtendGrace))))
 	mux.Handle("POST /api/v1/platform/orgs/{id}/sync-billing", middleware.Auth(middleware.RequireRole("superadmin", "superuser")(http.HandlerFunc(handlers.PlatformSyncOrg))))
+	mux.Handle("GET /api/v1/platform/ig-webhook-events", middleware.Auth(middleware.RequireRole("superadmin", "superuser")(http.HandlerFunc(handlers.PlatformListIGWebhookEvents))))
+	mux.Handle("POST /api/v1/platform/ig-webhook-events/replay", middleware.Auth(middleware.RequireRole("superadmin", "superuser")(http.HandlerFunc(handlers.PlatformReplayIGWebhookEvents))))
 	mux.Handle("GET /api/v1/platform/jobs", middleware.Auth(middleware.RequireRole("superadmin", "superuser")(http.HandlerFunc(handlers.PlatformListJobs))))
 	mux.Handle("POST /api/v1/platform/jobs/{id}/trigger", middleware.Auth(middleware.RequireRole("superadmin", "superuser")(http.HandlerFunc(handlers.PlatformTriggerJob))))
 